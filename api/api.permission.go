@@ -13,6 +13,10 @@ func RequestScope(r *http.Request) (string, error) {
 
 	switch {
 
+	// Staff routes are checked first since they may contain any of the other scopes in their uri
+	case strings.Contains(uri, "/staff/"):
+		return "staff", nil
+
 	case strings.Contains(uri, "/profile"):
 		return "profile", nil
 
@@ -39,6 +43,9 @@ func RequestScope(r *http.Request) (string, error) {
 
 	case strings.Contains(uri, "/moneytoken"):
 		return "moneytoken", nil
+
+	case strings.Contains(uri, "/hold"):
+		return "hold", nil
 	}
 
 	return "", errors.New("request scope unknown")
@@ -49,7 +56,7 @@ func RequestScope(r *http.Request) (string, error) {
 func ValidScope(scope string) bool {
 
 	validScopes := []string{"profile", "session", "send", "receive", "pay",
-		"wallet", "history", "linkedaccount", "moneytoken"}
+		"wallet", "history", "linkedaccount", "moneytoken", "hold"}
 	for _, validScope := range validScopes {
 		if validScope == scope {
 			return true
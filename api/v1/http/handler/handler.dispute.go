@@ -0,0 +1,382 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+	"github.com/gorilla/mux"
+)
+
+// DisputeDetail is a type that groups a dispute with the evidences submitted for it
+type DisputeDetail struct {
+	Dispute   *entity.Dispute
+	Evidences []*entity.DisputeEvidence
+}
+
+// HandleOpenDispute is a handler func that handles a request for disputing a qr code payment
+func (handler *UserAPIHandler) HandleOpenDispute(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	historyID, err := strconv.ParseInt(r.FormValue("history_id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "history not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	opDispute, err := handler.app.OpenDispute(opUser.UserID, historyID, r.FormValue("reason"))
+	handler.writeDisputeResult(w, format, opDispute, err)
+}
+
+// HandleGetUserDispute is a handler func that handles a request for viewing a dispute the user is part of
+func (handler *UserAPIHandler) HandleGetUserDispute(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	opDispute, err := handler.findRequestedDispute(r)
+	if err == nil && opDispute.OpenerID != opUser.UserID && opDispute.CounterpartyID != opUser.UserID {
+		err = errors.New("dispute not found")
+	}
+
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(DisputeDetail{Dispute: opDispute,
+		Evidences: handler.app.DisputeService.SearchEvidences(int64(opDispute.ID))}, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetUserDisputes is a handler func that handles a request for viewing all the disputes the user is part of
+func (handler *UserAPIHandler) HandleGetUserDisputes(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	opDisputes := handler.app.DisputeService.SearchDisputes(opUser.UserID)
+	output, _ := tools.MarshalIndent(opDisputes, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleAddDisputeEvidence is a handler func that handles a request for submitting an evidence to a dispute.
+// The evidence description is required while the attachment, sent as multipart form data, is optional.
+func (handler *UserAPIHandler) HandleAddDisputeEvidence(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	disputeID, err := strconv.ParseInt(r.FormValue("dispute_id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "dispute not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	fileName := ""
+	fm, fh, err := r.FormFile("evidence")
+	if err == nil {
+		defer fm.Close()
+
+		// checking the file sent doesn't exceed the size limit
+		if fh.Size > 5000000 {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: "evidence exceeds the file size limit, 5MB"},
+				"", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		tempFile, _ := ioutil.ReadAll(fm)
+		fileName = fmt.Sprintf("evidence_%d_%s%s", disputeID, tools.GenerateRandomString(5), filepath.Ext(fh.Filename))
+
+		wd, _ := os.Getwd()
+		out, err := os.Create(filepath.Join(wd, "./assets/disputes", fileName))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		defer out.Close()
+
+		_, err = io.Copy(out, bytes.NewBuffer(tempFile))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	evidence, err := handler.app.AddDisputeEvidence(opUser.UserID, disputeID, r.FormValue("description"), fileName)
+	if err != nil {
+
+		if fileName != "" {
+			wd, _ := os.Getwd()
+			tools.RemoveFile(filepath.Join(wd, "./assets/disputes", fileName))
+		}
+
+		switch err.Error() {
+		case "dispute not found":
+		case "evidence description can not be empty":
+		case entity.DisputeClosedError:
+		case entity.DisputeDeadlineError:
+		default:
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write(output)
+			return
+		}
+
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(evidence, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+/* +++++++++++++++++++++++++++++++++++++++++++++ STAFF +++++++++++++++++++++++++++++++++++++++++++++ */
+
+// HandleGetDisputes is a handler func that handles a staff member's request for viewing disputes by their status.
+// Without a status all the disputes that are waiting for a resolution are returned.
+func (handler *UserAPIHandler) HandleGetDisputes(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	statuses := []string{entity.DisputeStatusOpen, entity.DisputeStatusUnderReview}
+	if status := r.FormValue("status"); status != "" {
+		statuses = []string{status}
+	}
+
+	opDisputes := handler.app.DisputeService.DisputesByStatus(statuses...)
+	output, _ := tools.MarshalIndent(opDisputes, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetDispute is a handler func that handles a staff member's request for viewing a dispute with its evidences
+func (handler *UserAPIHandler) HandleGetDispute(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	opDispute, err := handler.findRequestedDispute(r)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(DisputeDetail{Dispute: opDispute,
+		Evidences: handler.app.DisputeService.SearchEvidences(int64(opDispute.ID))}, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetDisputeEvidence is a handler func that handles a staff member's request for viewing the attachment of an evidence
+func (handler *UserAPIHandler) HandleGetDisputeEvidence(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	evidenceID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	evidence, err := handler.app.DisputeService.FindEvidence(evidenceID)
+	if err != nil || evidence.FileName == "" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	wd, _ := os.Getwd()
+	filePath := filepath.Join(wd, "./assets/disputes", evidence.FileName)
+	http.ServeFile(w, r, filePath)
+}
+
+// HandleReviewDispute is a handler func that handles a staff member's request for taking an open dispute under review
+func (handler *UserAPIHandler) HandleReviewDispute(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	disputeID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "dispute not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	opDispute, err := handler.app.ReviewDispute(disputeID)
+	handler.writeDisputeResult(w, format, opDispute, err)
+}
+
+// HandleResolveDispute is a handler func that handles a staff member's request for resolving a dispute.
+// The outcome can either be won, in favour of the user who opened the dispute, or lost.
+func (handler *UserAPIHandler) HandleResolveDispute(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opStaff, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	disputeID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "dispute not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	var won bool
+	switch r.FormValue("outcome") {
+	case "won":
+		won = true
+	case "lost":
+		won = false
+	default:
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "invalid outcome used"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	opDispute, err := handler.app.ResolveDispute(opStaff.UserID, disputeID, won, r.FormValue("resolution"))
+	handler.writeDisputeResult(w, format, opDispute, err)
+}
+
+// findRequestedDispute is a method that finds the dispute whose id is provided in the request
+func (handler *UserAPIHandler) findRequestedDispute(r *http.Request) (*entity.Dispute, error) {
+
+	disputeID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		return nil, errors.New("dispute not found")
+	}
+
+	return handler.app.DisputeService.FindDispute(disputeID)
+}
+
+// writeDisputeResult is a method that writes the response of a request that changes a dispute
+func (handler *UserAPIHandler) writeDisputeResult(w http.ResponseWriter, format string,
+	opDispute *entity.Dispute, err error) {
+
+	if err != nil {
+
+		// If error is any of the below then it will break out return bad request
+		// else it will enter the default section so it can return internal server error
+		switch err.Error() {
+		// Whitelisting errors
+		case "history not found":
+		case "dispute not found":
+		case "dispute is not open":
+		case "dispute reason can not be empty":
+		case "cannot dispute history which you haven't paid":
+		case entity.NonDisputableHistoryError:
+		case entity.DisputedHistoryError:
+		case entity.DisputeClosedError:
+		case entity.DisputeDeadlineError:
+		case entity.FullyRefundedError:
+		case entity.InsufficientBalanceError:
+		case entity.QuarantinedWalletError:
+		case entity.ReceiverNotFoundError:
+		default:
+			// Any errors other than the above should be an internal server error
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write(output)
+			return
+		}
+
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(opDispute, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
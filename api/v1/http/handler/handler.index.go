@@ -29,3 +29,51 @@ type NotifierContainer struct {
 type NotificationContainer struct {
 	Histories []*entity.UserHistory
 }
+
+// LimitContainer is a struct that holds the verification tier of a user and the limit profile it gets
+type LimitContainer struct {
+	UserLimit *entity.UserLimit
+	Profile   *entity.LimitProfile
+}
+
+// ReconciliationReportContainer is a struct that holds a reconciliation report with the wallet discrepancies it has found
+type ReconciliationReportContainer struct {
+	Report        *entity.ReconciliationReport
+	Discrepancies []*entity.WalletDiscrepancy
+}
+
+// ReconciliationReportsContainer is a struct that holds a page of reconciliation reports
+type ReconciliationReportsContainer struct {
+	Result      []*entity.ReconciliationReport
+	CurrentPage int64
+	PageCount   int64
+}
+
+// JobRunsContainer is a struct that holds a page of job runs
+type JobRunsContainer struct {
+	Result      []*entity.JobRun
+	CurrentPage int64
+	PageCount   int64
+}
+
+// SettlementStatementContainer is a struct that holds a settlement statement with its items
+type SettlementStatementContainer struct {
+	Statement *entity.SettlementStatement
+	Items     []*entity.SettlementItem
+}
+
+// SettlementStatementsContainer is a struct that holds a page of settlement statements
+type SettlementStatementsContainer struct {
+	Result      []*entity.SettlementStatement
+	CurrentPage int64
+	PageCount   int64
+}
+
+// PaymentGroupContainer is a struct that holds a payment group with the progress of its participants
+type PaymentGroupContainer struct {
+	Group       *entity.PaymentGroup
+	Requests    []*entity.PaymentRequest
+	Paid        entity.Money
+	Outstanding entity.Money
+	Owing       []string
+}
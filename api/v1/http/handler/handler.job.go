@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+	"github.com/gorilla/mux"
+)
+
+// HandleGetJobRuns is a handler func that handles a staff member's request for viewing the run history of the scheduled jobs per page.
+// The runs can be limited to a single job using the job's name.
+func (handler *UserAPIHandler) HandleGetJobRuns(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+	pagenation, _ := strconv.ParseInt(r.FormValue("page"), 0, 64)
+
+	runs, pageCount := handler.app.JobService.SearchJobRuns(r.FormValue("job"), pagenation)
+
+	output, _ := tools.MarshalIndent(JobRunsContainer{
+		Result: runs, CurrentPage: pagenation, PageCount: pageCount}, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+)
+
+// HandleAuditLedger is a handler func that handles a staff member's request for auditing the ledger
+// and the wallets against their ledger accounts
+func (handler *UserAPIHandler) HandleAuditLedger(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	output, _ := tools.MarshalIndent(handler.app.AuditLedger(), "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleRebuildWallet is a handler func that handles a staff member's request for rebuilding a user's wallet from its ledger account
+func (handler *UserAPIHandler) HandleRebuildWallet(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	opWallet, err := handler.app.RebuildWallet(r.FormValue("user_id"), r.FormValue("currency"))
+	if err != nil {
+
+		// Whitelisting errors
+		if err.Error() == "user wallet not found" {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		// Any errors other than the above should be an internal server error
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(opWallet, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
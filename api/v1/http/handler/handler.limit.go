@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+)
+
+// HandleGetUserLimit is a handler func that handles a request for viewing the limits the user is subjected to
+func (handler *UserAPIHandler) HandleGetUserLimit(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	handler.writeUserLimit(w, format, opUser.UserID)
+}
+
+// HandleGetRemainingLimits is a handler func that handles a request for viewing how much of each periodic limit
+// the user has used in the current period and how much is left
+func (handler *UserAPIHandler) HandleGetRemainingLimits(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	remainingLimits := handler.app.RemainingLimits(opUser.UserID, handler.redisClient)
+	output, _ := tools.MarshalIndent(remainingLimits, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetStaffUserLimit is a handler func that handles a staff member's request for viewing the limits of a user
+func (handler *UserAPIHandler) HandleGetStaffUserLimit(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	handler.writeUserLimit(w, format, r.FormValue("user_id"))
+}
+
+// HandleAssignUserLimit is a handler func that handles a staff member's request for setting the verification tier of a user.
+// An optional profile overrides the limit profile of the tier, sending an empty profile removes the override.
+func (handler *UserAPIHandler) HandleAssignUserLimit(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opStaff, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	_, err := handler.app.AssignUserLimit(opStaff.UserID, r.FormValue("user_id"),
+		r.FormValue("tier"), r.FormValue("profile"))
+	if err != nil {
+
+		// Whitelisting errors
+		if err.Error() == "user not found" ||
+			err.Error() == entity.InvalidTierError ||
+			err.Error() == entity.LimitProfileNotFoundError {
+
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		// Any errors other than the above should be an internal server error
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	handler.writeUserLimit(w, format, r.FormValue("user_id"))
+}
+
+// writeUserLimit is a method that writes the verification tier of a user along with the limit profile it gets
+func (handler *UserAPIHandler) writeUserLimit(w http.ResponseWriter, format, userID string) {
+
+	userLimit, err := handler.app.LimitService.FindUserLimit(userID)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	container := LimitContainer{UserLimit: userLimit, Profile: handler.app.UserLimitProfile(userID)}
+	output, _ := tools.MarshalIndent(container, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
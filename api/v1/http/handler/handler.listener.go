@@ -66,14 +66,14 @@ func (handler *UserAPIHandler) HandleListenToWalletChange(w http.ResponseWriter,
 	}
 
 	userID := string(userIDB)
-	opWallet, err := handler.app.WalletService.FindWallet(userID)
-	if err != nil {
-		return
-	}
+	opWallets := handler.app.WalletService.SearchWallets(userID)
 
+	// Every wallet of the user is sent since the notifier doesn't tell which currency has changed
 	activeSocketChannels := handler.activeSocketChannels[userID]
-	for _, channel := range activeSocketChannels {
-		channel <- NotifierContainer{Type: "wallet", Body: opWallet}
+	for _, opWallet := range opWallets {
+		for _, channel := range activeSocketChannels {
+			channel <- NotifierContainer{Type: "wallet", Body: opWallet}
+		}
 	}
 }
 
@@ -105,3 +105,78 @@ func (handler *UserAPIHandler) HandleListenToHistoryChange(w http.ResponseWriter
 		channel <- NotifierContainer{Type: "history", Body: history}
 	}
 }
+
+// HandleListenToScheduledTransferChange is a handler func that listens to scheduled transfer change from its notifier
+func (handler *UserAPIHandler) HandleListenToScheduledTransferChange(w http.ResponseWriter, r *http.Request) {
+
+	handler.Lock()
+	defer handler.Unlock()
+
+	transfer := new(entity.ScheduledTransfer)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(body, &transfer)
+	if err != nil {
+		return
+	}
+
+	activeSocketChannels := handler.activeSocketChannels[transfer.SenderID]
+	for _, channel := range activeSocketChannels {
+		channel <- NotifierContainer{Type: "scheduled_transfer", Body: transfer}
+	}
+}
+
+// HandleListenToPaymentRequestChange is a handler func that listens to payment request change from its notifier
+func (handler *UserAPIHandler) HandleListenToPaymentRequestChange(w http.ResponseWriter, r *http.Request) {
+
+	handler.Lock()
+	defer handler.Unlock()
+
+	paymentRequest := new(entity.PaymentRequest)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(body, &paymentRequest)
+	if err != nil {
+		return
+	}
+
+	requesterActiveSocketChannels := handler.activeSocketChannels[paymentRequest.RequesterID]
+	payerActiveSocketChannels := handler.activeSocketChannels[paymentRequest.PayerID]
+
+	for _, channel := range requesterActiveSocketChannels {
+		channel <- NotifierContainer{Type: "payment_request", Body: paymentRequest}
+	}
+
+	for _, channel := range payerActiveSocketChannels {
+		channel <- NotifierContainer{Type: "payment_request", Body: paymentRequest}
+	}
+}
+
+// HandleListenToMoneyTokenChange is a handler func that listens to money token change from its notifier
+func (handler *UserAPIHandler) HandleListenToMoneyTokenChange(w http.ResponseWriter, r *http.Request) {
+
+	handler.Lock()
+	defer handler.Unlock()
+
+	moneyToken := new(entity.MoneyToken)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(body, &moneyToken)
+	if err != nil {
+		return
+	}
+
+	activeSocketChannels := handler.activeSocketChannels[moneyToken.SenderID]
+	for _, channel := range activeSocketChannels {
+		channel <- NotifierContainer{Type: "money_token", Body: moneyToken}
+	}
+}
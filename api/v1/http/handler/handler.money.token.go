@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Benyam-S/onepay/app"
 	"github.com/Benyam-S/onepay/entity"
 	"github.com/Benyam-S/onepay/tools"
 	"github.com/gorilla/mux"
@@ -23,7 +27,10 @@ func (handler *UserAPIHandler) HandleGetUserMoneyTokens(w http.ResponseWriter, r
 
 	format := mux.Vars(r)["format"]
 
-	moneyTokens := handler.app.MoneyTokenService.SearchMoneyToken(opUser.UserID)
+	// Money tokens can be filtered by their states, without a filter all of the user's money tokens are returned
+	statuses := strings.Fields(r.FormValue("statuses"))
+
+	moneyTokens := handler.app.MoneyTokenService.SearchMoneyToken(opUser.UserID, statuses...)
 	output, _ := tools.MarshalIndent(moneyTokens, "", "\t", format)
 	w.WriteHeader(http.StatusOK)
 	w.Write(output)
@@ -48,6 +55,14 @@ func (handler *UserAPIHandler) HandleRefreshMoneyTokens(w http.ResponseWriter, r
 	format := mux.Vars(r)["format"]
 	codesString := r.FormValue("codes")
 
+	expiry, err := parseExpiry(r.FormValue("expiry"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
 	refreshedMoneyTokens := make([]*entity.MoneyToken, 0)
 	nonRefreshedMoneyTokens := make([]*MoneyTokenError, 0)
 
@@ -59,7 +74,7 @@ func (handler *UserAPIHandler) HandleRefreshMoneyTokens(w http.ResponseWriter, r
 	}
 
 	for _, code := range codes {
-		err := handler.app.RefreshMoneyToken(code, opUser.UserID)
+		err := handler.app.RefreshMoneyToken(code, opUser.UserID, expiry)
 		if err != nil {
 			errMT := new(MoneyTokenError)
 			errMT.Code = code
@@ -121,10 +136,6 @@ func (handler *UserAPIHandler) HandleReclaimMoneyTokens(w http.ResponseWriter, r
 	for _, code := range codes {
 		err := handler.app.ReclaimMoneyToken(code, opUser.UserID)
 		if err != nil {
-			if err.Error() == entity.WalletCheckpointError {
-				// requesting reload
-				handler.app.Channel <- "reload_wallet"
-			}
 			errMap[code] = err
 		}
 	}
@@ -172,10 +183,6 @@ func (handler *UserAPIHandler) HandleRemoveMoneyTokens(w http.ResponseWriter, r
 		if moneyToken.Method == entity.MethodTransactionQRCode {
 			err := handler.app.ReclaimMoneyToken(code, opUser.UserID)
 			if err != nil {
-				if err.Error() == entity.WalletCheckpointError {
-					// requesting reload
-					handler.app.Channel <- "reload_wallet"
-				}
 				errMap[code] = err
 			}
 		} else if moneyToken.Method == entity.MethodPaymentQRCode {
@@ -195,3 +202,124 @@ func (handler *UserAPIHandler) HandleRemoveMoneyTokens(w http.ResponseWriter, r
 		return
 	}
 }
+
+// HandleGetMoneyTokenQRCode is a handler func that handles a request for rendering the qr code of a money token as a png or svg image
+func (handler *UserAPIHandler) HandleGetMoneyTokenQRCode(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	// The format is the image format so errors are returned as json
+	format := mux.Vars(r)["format"]
+
+	code := r.FormValue("code")
+	payloadFormat := r.FormValue("payload")
+	level := r.FormValue("level")
+	logo, _ := strconv.ParseBool(r.FormValue("logo"))
+
+	size, err := parseQRCodeSize(r.FormValue("size"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", "json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	qrCode, err := handler.app.MoneyTokenQRCode(code, opUser.UserID, payloadFormat, format, size, level, logo)
+	if err != nil {
+
+		// Whitelisting errors
+		if err.Error() == entity.InvalidMoneyTokenError ||
+			err.Error() == entity.InactiveMoneyTokenError ||
+			err.Error() == entity.InvalidQRCodeSizeError ||
+			err.Error() == entity.InvalidQRCodeLevelError ||
+			err.Error() == entity.InvalidQRPayloadFormatError ||
+			err.Error() == entity.InvalidMethodError {
+
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", "json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		// Any errors other than the above should be an internal server error
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", "json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	writeQRCode(w, format, qrCode)
+}
+
+// HandleGetQRSigningKeys is a handler func that returns the public keys of the qr signing keys as a JWKS,
+// so terminals can check the signature of scanned qr codes offline
+func (handler *UserAPIHandler) HandleGetQRSigningKeys(w http.ResponseWriter, r *http.Request) {
+
+	keys, err := tools.QRJSONWebKeys(app.QRSigningKeys())
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", "json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	type JSONWebKeySet struct {
+		Keys []*tools.JSONWebKey `json:"keys"`
+	}
+
+	output, _ := tools.MarshalIndent(JSONWebKeySet{Keys: keys}, "", "\t", "json")
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// parseExpiry is a function that parses the money token expiry given in hours, an empty value means no expiry has been picked
+func parseExpiry(value string) (time.Duration, error) {
+
+	if value == "" {
+		return 0, nil
+	}
+
+	hours, err := strconv.ParseFloat(value, 64)
+	if err != nil || hours <= 0 {
+		return 0, errors.New(entity.InvalidExpiryError)
+	}
+
+	return time.Duration(hours * float64(time.Hour)), nil
+}
+
+// parseQRCodeSize is a function that parses the requested qr code size in pixels, an empty value means the default size
+func parseQRCodeSize(value string) (int, error) {
+
+	if value == "" {
+		return 0, nil
+	}
+
+	size, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New(entity.InvalidQRCodeSizeError)
+	}
+
+	return size, nil
+}
+
+// writeQRCode is a function that writes a rendered qr code image with the content type of its format
+func writeQRCode(w http.ResponseWriter, format string, qrCode []byte) {
+
+	contentType := "image/png"
+	if format == entity.QRCodeFormatSVG {
+		contentType = "image/svg+xml"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(qrCode)
+}
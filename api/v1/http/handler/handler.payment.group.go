@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+	"github.com/gorilla/mux"
+)
+
+// HandleSplitPayment is a handler func that handles a request for splitting a bill between a group of users.
+// The participants are sent as comma separated onepay ids and the optional shares as comma separated amounts in the same order.
+// Without shares the amount is split equally between the participants and the user.
+func (handler *UserAPIHandler) HandleSplitPayment(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	currency, err := tools.ParseCurrency(r.FormValue("currency"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	participants := make([]string, 0)
+	for _, participantID := range strings.Split(r.FormValue("participants"), ",") {
+		if participantID = strings.TrimSpace(participantID); participantID != "" {
+			participants = append(participants, participantID)
+		}
+	}
+
+	// Checking participants account validity
+	for _, participantID := range participants {
+		if handler.dService.UserIsFrozen(participantID) {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: entity.FrozenAccountError}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+	}
+
+	shares := make([]entity.Money, 0)
+	total := entity.NewMoney(0, currency)
+
+	if sharesString := strings.TrimSpace(r.FormValue("shares")); sharesString != "" {
+		for _, shareString := range strings.Split(sharesString, ",") {
+			share, err := entity.ParseMoney(strings.TrimSpace(shareString), currency)
+			if err != nil {
+				output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write(output)
+				return
+			}
+			shares = append(shares, share)
+			total = total.Add(share)
+		}
+
+	} else {
+		total, err = entity.ParseMoney(r.FormValue("amount"), currency)
+		if err != nil || !total.IsPositive() {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+	}
+
+	group, requests, err := handler.app.SplitPayment(opUser.UserID, r.FormValue("description"), total, participants, shares)
+	if err != nil {
+
+		// Whitelisting errors
+		if err.Error() == "no participant has been provided" ||
+			err.Error() == "participants should be unique" ||
+			err.Error() == entity.InvalidSharesError ||
+			err.Error() == entity.AmountParsingError ||
+			err.Error() == entity.TransactionBaseLimitError ||
+			err.Error() == entity.ExchangeRateNotFoundError ||
+			err.Error() == entity.SenderNotFoundError ||
+			err.Error() == entity.TransactionWSelfError {
+
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		// Any errors other than the above should be an internal server error
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(newPaymentGroupContainer(group, requests), "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetPaymentGroup is a handler func that handles a request for viewing the progress of a payment group
+func (handler *UserAPIHandler) HandleGetPaymentGroup(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	groupID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "payment group not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	group, requests, err := handler.app.PaymentGroupDetail(opUser.UserID, groupID)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(newPaymentGroupContainer(group, requests), "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetPaymentGroups is a handler func that handles a request for viewing all the payment groups the user has initiated
+func (handler *UserAPIHandler) HandleGetPaymentGroups(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	groups := handler.app.PaymentRequestService.SearchPaymentGroups(opUser.UserID)
+	output, _ := tools.MarshalIndent(groups, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleRemindPaymentGroup is a handler func that handles a request for reminding the participants of a payment group who still owe their share
+func (handler *UserAPIHandler) HandleRemindPaymentGroup(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	groupID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "payment group not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	reminded, err := handler.app.RemindPaymentGroup(opUser.UserID, groupID)
+	if err != nil {
+
+		if err.Error() == "payment group not found" ||
+			err.Error() == "payment group has already been closed" ||
+			err.Error() == "reminder has already been sent recently" {
+
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(reminded, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// newPaymentGroupContainer is a function that sums up how much of a payment group has been paid and who still owes
+func newPaymentGroupContainer(group *entity.PaymentGroup, requests []*entity.PaymentRequest) PaymentGroupContainer {
+
+	container := PaymentGroupContainer{Group: group, Requests: requests, Owing: make([]string, 0),
+		Paid: entity.NewMoney(0, group.Total.Currency), Outstanding: entity.NewMoney(0, group.Total.Currency)}
+
+	for _, request := range requests {
+		if request.Status == entity.PaymentRequestStatusPaid {
+			container.Paid = container.Paid.Add(request.Amount)
+			continue
+		}
+
+		container.Outstanding = container.Outstanding.Add(request.Amount)
+		container.Owing = append(container.Owing, request.PayerID)
+	}
+
+	return container
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+	"github.com/gorilla/mux"
+)
+
+// HandleRequestPayment is a handler func that handles a request for requesting money from another user.
+// The optional expires_in value is the number of hours the request stays pending.
+func (handler *UserAPIHandler) HandleRequestPayment(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	payerID := r.FormValue("payer_id")
+	currency, err := tools.ParseCurrency(r.FormValue("currency"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	amount, err := entity.ParseMoney(r.FormValue("amount"), currency)
+	if err != nil || !amount.IsPositive() {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	var expiry time.Duration
+	if expiresIn := r.FormValue("expires_in"); expiresIn != "" {
+		hours, err := strconv.ParseInt(expiresIn, 10, 64)
+		if err != nil || hours <= 0 {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: "invalid expiry used"}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+		expiry = time.Duration(hours) * time.Hour
+	}
+
+	// Checking payer account validity
+	if handler.dService.UserIsFrozen(payerID) {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.FrozenAccountError}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	request, err := handler.app.RequestPayment(opUser.UserID, payerID, amount, r.FormValue("note"), expiry)
+	if err != nil {
+
+		// Whitelisting errors
+		if err.Error() == entity.TransactionBaseLimitError ||
+			err.Error() == entity.ExchangeRateNotFoundError ||
+			err.Error() == entity.SenderNotFoundError ||
+			err.Error() == entity.TransactionWSelfError {
+
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		// Any errors other than the above should be an internal server error
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(request, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetSentPaymentRequests is a handler func that handles a request for viewing the payment requests the user has made
+func (handler *UserAPIHandler) HandleGetSentPaymentRequests(w http.ResponseWriter, r *http.Request) {
+	handler.writePaymentRequests(w, r, "requester")
+}
+
+// HandleGetReceivedPaymentRequests is a handler func that handles a request for viewing the payment requests the user has to pay
+func (handler *UserAPIHandler) HandleGetReceivedPaymentRequests(w http.ResponseWriter, r *http.Request) {
+	handler.writePaymentRequests(w, r, "payer")
+}
+
+// HandlePayPaymentRequest is a handler func that handles a request for paying a received payment request
+func (handler *UserAPIHandler) HandlePayPaymentRequest(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	requestID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "payment request not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	request, err := handler.app.PayPaymentRequest(opUser.UserID, requestID, handler.redisClient)
+	handler.writePaymentRequestResult(w, format, request, err)
+}
+
+// HandleDeclinePaymentRequest is a handler func that handles a request for declining a received payment request
+func (handler *UserAPIHandler) HandleDeclinePaymentRequest(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	requestID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "payment request not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	request, err := handler.app.DeclinePaymentRequest(opUser.UserID, requestID)
+	handler.writePaymentRequestResult(w, format, request, err)
+}
+
+// HandleCancelPaymentRequest is a handler func that handles a request for cancelling a payment request the user has made
+func (handler *UserAPIHandler) HandleCancelPaymentRequest(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	requestID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "payment request not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	request, err := handler.app.CancelPaymentRequest(opUser.UserID, requestID)
+	handler.writePaymentRequestResult(w, format, request, err)
+}
+
+// writePaymentRequests is a method that writes the payment requests of the user with the provided role.
+// The status value can be open, paid, declined, cancelled or expired and an empty status lists every request.
+func (handler *UserAPIHandler) writePaymentRequests(w http.ResponseWriter, r *http.Request, role string) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	statuses := make([]string, 0)
+	switch strings.ToLower(r.FormValue("status")) {
+	case "":
+	case "open", "pending":
+		statuses = append(statuses, entity.PaymentRequestStatusPending)
+	case "paid":
+		statuses = append(statuses, entity.PaymentRequestStatusPaid)
+	case "declined":
+		statuses = append(statuses, entity.PaymentRequestStatusDeclined)
+	case "cancelled":
+		statuses = append(statuses, entity.PaymentRequestStatusCancelled)
+	case "expired":
+		statuses = append(statuses, entity.PaymentRequestStatusExpired)
+	default:
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "invalid status used"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	requests := handler.app.PaymentRequestService.SearchPaymentRequests(opUser.UserID, role, statuses...)
+	output, _ := tools.MarshalIndent(requests, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// writePaymentRequestResult is a method that writes the response of a request that responds to a payment request
+func (handler *UserAPIHandler) writePaymentRequestResult(w http.ResponseWriter, format string,
+	request *entity.PaymentRequest, err error) {
+
+	if err != nil {
+
+		// If error is any of the below then it will break out return bad request
+		// else it will enter the default section so it can return internal server error
+		switch err.Error() {
+		// Whitelisting errors
+		case "payment request not found":
+		case entity.ClosedPaymentRequestError:
+		case entity.ExpiredPaymentRequestError:
+		case entity.TransactionBaseLimitError:
+		case entity.TransactionLimitError:
+		case entity.DailyTransactionLimitError:
+		case entity.WeeklyTransactionLimitError:
+		case entity.MonthlyTransactionLimitError:
+		case entity.ReceiveLimitError:
+		case entity.MaxBalanceError:
+		case entity.ExchangeRateNotFoundError:
+		case entity.InsufficientBalanceError:
+		case entity.QuarantinedWalletError:
+		case entity.SenderNotFoundError:
+		case entity.ReceiverNotFoundError:
+		default:
+			// Any errors other than the above should be an internal server error
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write(output)
+			return
+		}
+
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(request, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Benyam-S/onepay/app"
@@ -26,8 +28,16 @@ func (handler *UserAPIHandler) HandleCreatePaymentToken(w http.ResponseWriter, r
 
 	format := mux.Vars(r)["format"]
 
+	currency, err := tools.ParseCurrency(r.FormValue("currency"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
 	amountString := r.FormValue("amount")
-	amount, err := strconv.ParseFloat(amountString, 64)
+	amount, err := entity.ParseMoney(amountString, currency)
 	if err != nil {
 		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
 		w.WriteHeader(http.StatusBadRequest)
@@ -35,13 +45,35 @@ func (handler *UserAPIHandler) HandleCreatePaymentToken(w http.ResponseWriter, r
 		return
 	}
 
-	moneyToken, err := handler.app.CreatePaymentToken(opUser.UserID, amount)
+	invoice := entity.Invoice{}
+	invoice.OrderID = strings.TrimSpace(r.FormValue("order_id"))
+	invoice.Description = strings.TrimSpace(r.FormValue("description"))
+	invoice.CallbackRef = strings.TrimSpace(r.FormValue("callback_ref"))
+
+	// Line items are given as a json array, the unit prices are in the currency of the amount
+	if lineItemsString := r.FormValue("line_items"); lineItemsString != "" {
+		invoice.LineItems, err = parseLineItems(lineItemsString, currency)
+		if err != nil {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+	}
+
+	moneyToken, err := handler.app.CreatePaymentToken(opUser.UserID, amount, invoice)
 
 	if err != nil {
 
 		// Whitelisting errors
 		if err.Error() == entity.TransactionBaseLimitError ||
-			err.Error() == entity.DailyTransactionLimitError {
+			err.Error() == entity.ExchangeRateNotFoundError ||
+			err.Error() == entity.ReceiveLimitError ||
+			err.Error() == entity.InvalidOrderIDError ||
+			err.Error() == entity.InvalidDescriptionError ||
+			err.Error() == entity.InvalidCallbackRefError ||
+			err.Error() == entity.InvalidLineItemsError ||
+			err.Error() == entity.LineItemsTotalError {
 
 			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
 			w.WriteHeader(http.StatusBadRequest)
@@ -63,6 +95,39 @@ func (handler *UserAPIHandler) HandleCreatePaymentToken(w http.ResponseWriter, r
 
 }
 
+// parseLineItems is a function that parses the json array of invoice line items, pricing them in the provided currency
+func parseLineItems(value, currency string) (entity.LineItems, error) {
+
+	type LineItemInput struct {
+		Name      string `json:"name"`
+		Quantity  int    `json:"quantity"`
+		UnitPrice string `json:"unit_price"`
+	}
+
+	var inputs []*LineItemInput
+	err := json.Unmarshal([]byte(value), &inputs)
+	if err != nil {
+		return nil, errors.New(entity.InvalidLineItemsError)
+	}
+
+	lineItems := make(entity.LineItems, 0)
+	for _, input := range inputs {
+		if input == nil {
+			return nil, errors.New(entity.InvalidLineItemsError)
+		}
+
+		unitPrice, err := entity.ParseMoney(input.UnitPrice, currency)
+		if err != nil {
+			return nil, errors.New(entity.InvalidLineItemsError)
+		}
+
+		lineItems = append(lineItems, &entity.LineItem{Name: strings.TrimSpace(input.Name),
+			Quantity: input.Quantity, UnitPrice: unitPrice})
+	}
+
+	return lineItems, nil
+}
+
 // HandlePayViaQRCode is a handler func that handles a request for paying via qr code
 func (handler *UserAPIHandler) HandlePayViaQRCode(w http.ResponseWriter, r *http.Request) {
 
@@ -76,21 +141,29 @@ func (handler *UserAPIHandler) HandlePayViaQRCode(w http.ResponseWriter, r *http
 
 	format := mux.Vars(r)["format"]
 
-	code := r.FormValue("code")
-	err := handler.app.PayViaQRCode(opUser.UserID, code, handler.redisClient)
-
-	if err != nil && err.Error() == entity.WalletCheckpointError {
-
-		// requesting reload
-		handler.app.Channel <- "reload_wallet"
+	// The amount is only needed for paying static merchant qr codes, which don't carry an amount
+	amount := entity.NewMoney(0, entity.BaseCurrency)
+	if amountString := r.FormValue("amount"); amountString != "" {
+		currency, err := tools.ParseCurrency(r.FormValue("currency"))
+		if err != nil {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
 
-		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write(output)
-		return
+		amount, err = entity.ParseMoney(amountString, currency)
+		if err != nil {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
 	}
 
-	if err != nil && err.Error() != entity.HistoryCheckpointError {
+	err := handler.app.PayViaQRCode(opUser.UserID, r.FormValue("code"), amount, handler.redisClient)
+
+	if err != nil {
 
 		// If error is any of the below then it will break out return bad request
 		// else it will enter the default section so it can return internal server error
@@ -99,12 +172,26 @@ func (handler *UserAPIHandler) HandlePayViaQRCode(w http.ResponseWriter, r *http
 		case entity.ReceiverNotFoundError:
 		case entity.InvalidMoneyTokenError:
 		case entity.ExpiredMoneyTokenError:
+		case entity.InactiveMoneyTokenError:
 		case entity.TransactionBaseLimitError:
+		case entity.TransactionLimitError:
 		case entity.DailyTransactionLimitError:
+		case entity.WeeklyTransactionLimitError:
+		case entity.MonthlyTransactionLimitError:
+		case entity.ReceiveLimitError:
+		case entity.MaxBalanceError:
 		case entity.TransactionWSelfError:
 		case entity.InvalidMethodError:
 		case entity.SenderNotFoundError:
 		case entity.InsufficientBalanceError:
+		case entity.QuarantinedWalletError:
+		case entity.InvalidQRSignatureError:
+		case entity.QRPayloadMismatchError:
+		case entity.InvalidEMVQRCodeError:
+		case entity.ForeignEMVQRCodeError:
+		case entity.StaticQRCodeAmountError:
+		case entity.QRCodeCurrencyError:
+		case entity.UnsupportedCurrencyError:
 		default:
 			// Any errors other than the above should be an internal server error
 			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
@@ -134,14 +221,18 @@ func (handler *UserAPIHandler) HandleGetPaymentInfo(w http.ResponseWriter, r *ht
 	}
 
 	format := mux.Vars(r)["format"]
-	code := r.FormValue("code")
+	code, scanErr := handler.app.ScannedMoneyTokenCode(r.FormValue("code"))
 	receiverID := opUser.UserID
 
 	moneyToken, err := handler.app.MoneyTokenService.FindMoneyToken(code)
 
 	switch {
+	case scanErr != nil:
+		err = scanErr
 	case err != nil:
 		err = errors.New(entity.InvalidMoneyTokenError)
+	case !moneyToken.IsActive():
+		err = errors.New(entity.InactiveMoneyTokenError)
 	case !moneyToken.ExpirationDate.After(time.Now()):
 		err = errors.New(entity.ExpiredMoneyTokenError)
 	case !app.AboveTransactionBaseLimit(moneyToken.Amount):
@@ -159,8 +250,67 @@ func (handler *UserAPIHandler) HandleGetPaymentInfo(w http.ResponseWriter, r *ht
 		return
 	}
 
+	// The money token carries its invoice so the payer can see the order that is being paid for
 	output, _ := tools.MarshalIndent(moneyToken, "", "\t", format)
 	w.WriteHeader(http.StatusOK)
 	w.Write(output)
 	return
 }
+
+// HandleGetMerchantQRCode is a handler func that handles a request for rendering the static EMVCo merchant qr code of the user
+// as a png or svg image, payers pick the amount when they scan it
+func (handler *UserAPIHandler) HandleGetMerchantQRCode(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	// The format is the image format so errors are returned as json
+	format := mux.Vars(r)["format"]
+
+	level := r.FormValue("level")
+	logo, _ := strconv.ParseBool(r.FormValue("logo"))
+
+	currency, err := tools.ParseCurrency(r.FormValue("currency"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", "json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	size, err := parseQRCodeSize(r.FormValue("size"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", "json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	qrCode, err := handler.app.MerchantQRCode(opUser.UserID, currency, format, size, level, logo)
+	if err != nil {
+
+		// Whitelisting errors
+		if err.Error() == entity.ReceiverNotFoundError ||
+			err.Error() == entity.InvalidQRCodeSizeError ||
+			err.Error() == entity.InvalidQRCodeLevelError {
+
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", "json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		// Any errors other than the above should be an internal server error
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", "json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	writeQRCode(w, format, qrCode)
+}
@@ -26,7 +26,8 @@ func (handler *UserAPIHandler) HandleReceiveViaQRCode(w http.ResponseWriter, r *
 
 	format := mux.Vars(r)["format"]
 
-	code := r.FormValue("code")
+	code, err := handler.app.ScannedMoneyTokenCode(r.FormValue("code"))
+	secret := r.FormValue("secret")
 
 	// checking for false attempts
 	falseAttempts, _ := tools.GetValue(handler.redisClient, entity.ReceiveFault+opUser.UserID)
@@ -38,20 +39,11 @@ func (handler *UserAPIHandler) HandleReceiveViaQRCode(w http.ResponseWriter, r *
 		return
 	}
 
-	err := handler.app.ReceiveViaQRCode(opUser.UserID, code)
-
-	if err != nil && err.Error() == entity.WalletCheckpointError {
-
-		// requesting reload
-		handler.app.Channel <- "reload_wallet"
-
-		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write(output)
-		return
+	if err == nil {
+		err = handler.app.ReceiveViaQRCode(opUser.UserID, code, secret, handler.redisClient)
 	}
 
-	if err != nil && err.Error() != entity.HistoryCheckpointError {
+	if err != nil {
 
 		// registering fault
 		tools.SetValue(handler.redisClient, entity.ReceiveFault+opUser.UserID,
@@ -65,8 +57,16 @@ func (handler *UserAPIHandler) HandleReceiveViaQRCode(w http.ResponseWriter, r *
 		case entity.InvalidMoneyTokenError:
 		case entity.ExpiredMoneyTokenError:
 		case entity.TransactionBaseLimitError:
+		case entity.ReceiveLimitError:
+		case entity.MaxBalanceError:
 		case entity.TransactionWSelfError:
 		case entity.InvalidMethodError:
+		case entity.MoneyTokenClaimedError:
+		case entity.InactiveMoneyTokenError:
+		case entity.InvalidMoneyTokenSecretError:
+		case entity.LockedMoneyTokenError:
+		case entity.InvalidQRSignatureError:
+		case entity.QRPayloadMismatchError:
 		default:
 			// Any errors other than the above should be an internal server error
 			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
@@ -99,7 +99,7 @@ func (handler *UserAPIHandler) HandleGetReceiveInfo(w http.ResponseWriter, r *ht
 	}
 
 	format := mux.Vars(r)["format"]
-	code := r.FormValue("code")
+	code, scanErr := handler.app.ScannedMoneyTokenCode(r.FormValue("code"))
 
 	// checking for false attempts
 	falseAttempts, _ := tools.GetValue(handler.redisClient, entity.ReceiveFault+opUser.UserID)
@@ -114,16 +114,30 @@ func (handler *UserAPIHandler) HandleGetReceiveInfo(w http.ResponseWriter, r *ht
 	moneyToken, err := handler.app.MoneyTokenService.FindMoneyToken(code)
 
 	switch {
+	case scanErr != nil:
+		err = scanErr
 	case err != nil:
 		err = errors.New(entity.InvalidMoneyTokenError)
+	case !moneyToken.IsActive():
+		err = errors.New(entity.InactiveMoneyTokenError)
 	case !moneyToken.ExpirationDate.After(time.Now()):
 		err = errors.New(entity.ExpiredMoneyTokenError)
-	case !app.AboveTransactionBaseLimit(moneyToken.Amount):
-		err = errors.New(entity.TransactionBaseLimitError)
 	case moneyToken.SenderID == opUser.UserID:
 		err = errors.New(entity.TransactionWSelfError)
 	case moneyToken.Method != entity.MethodTransactionQRCode:
 		err = errors.New(entity.InvalidMethodError)
+	case moneyToken.Locked:
+		err = errors.New(entity.LockedMoneyTokenError)
+	}
+
+	// Checking the amount the user would receive from the money token and whether the user has already claimed it
+	if err == nil {
+		claimAmount, _ := moneyToken.NextClaim()
+		if _, claimErr := handler.app.MoneyTokenService.FindClaim(moneyToken.Code, opUser.UserID); claimErr == nil {
+			err = errors.New(entity.MoneyTokenClaimedError)
+		} else if !app.AboveTransactionBaseLimit(claimAmount) {
+			err = errors.New(entity.TransactionBaseLimitError)
+		}
 	}
 
 	if err != nil {
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+)
+
+// HandleReconcileWallets is a handler func that handles a staff member's request for reconciling the wallets with the user histories.
+// Setting quarantine to true also quarantines every mismatched wallet.
+func (handler *UserAPIHandler) HandleReconcileWallets(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opStaff, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+	quarantine, _ := strconv.ParseBool(r.FormValue("quarantine"))
+
+	report, err := handler.app.ReconcileWallets(opStaff.UserID, quarantine)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	handler.writeReconciliationReport(w, format, report)
+}
+
+// HandleGetReconciliationReports is a handler func that handles a staff member's request for viewing the reconciliation reports per page
+func (handler *UserAPIHandler) HandleGetReconciliationReports(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+	pagenation, _ := strconv.ParseInt(r.FormValue("page"), 0, 64)
+
+	reports, pageCount := handler.app.ReconciliationService.SearchReports(pagenation)
+
+	output, _ := tools.MarshalIndent(ReconciliationReportsContainer{
+		Result: reports, CurrentPage: pagenation, PageCount: pageCount}, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetReconciliationReport is a handler func that handles a staff member's request for viewing a reconciliation report
+// along with the wallet discrepancies it has found
+func (handler *UserAPIHandler) HandleGetReconciliationReport(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	reportID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	report, err := handler.app.ReconciliationService.FindReport(reportID)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	handler.writeReconciliationReport(w, format, report)
+}
+
+// HandleQuarantineWallet is a handler func that handles a staff member's request for quarantining or releasing a user's wallet
+func (handler *UserAPIHandler) HandleQuarantineWallet(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	quarantined, err := strconv.ParseBool(r.FormValue("quarantine"))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	opWallet, err := handler.app.QuarantineWallet(r.FormValue("user_id"), r.FormValue("currency"), quarantined)
+	if err != nil {
+
+		// Whitelisting errors
+		if err.Error() == "user wallet not found" ||
+			err.Error() == entity.UnsupportedCurrencyError {
+
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		// Any errors other than the above should be an internal server error
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(opWallet, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// writeReconciliationReport is a method that writes a reconciliation report along with the wallet discrepancies it has found
+func (handler *UserAPIHandler) writeReconciliationReport(w http.ResponseWriter, format string, report *entity.ReconciliationReport) {
+
+	container := ReconciliationReportContainer{Report: report,
+		Discrepancies: handler.app.ReconciliationService.ReportDiscrepancies(int64(report.ID))}
+	output, _ := tools.MarshalIndent(container, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
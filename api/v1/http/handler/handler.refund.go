@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+	"github.com/gorilla/mux"
+)
+
+// HandleRefundHistory is a handler func that handles a request for refunding a received transaction or payment
+func (handler *UserAPIHandler) HandleRefundHistory(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	historyID, amount, err := handler.parseRefundRequest(r)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	opHistory, err := handler.app.RefundHistory(opUser.UserID, historyID, amount)
+	handler.writeRefundResult(w, format, opHistory, err)
+}
+
+// HandleReverseHistory is a handler func that handles a staff member's request for reversing a transaction or payment
+func (handler *UserAPIHandler) HandleReverseHistory(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	historyID, amount, err := handler.parseRefundRequest(r)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	opHistory, err := handler.app.ReverseHistory(historyID, amount)
+	handler.writeRefundResult(w, format, opHistory, err)
+}
+
+// parseRefundRequest is a method that reads the history id and the amount of a refund or reversal request.
+// The amount is read in the currency of the history and an empty amount means the whole refundable amount.
+func (handler *UserAPIHandler) parseRefundRequest(r *http.Request) (int64, entity.Money, error) {
+
+	historyID, err := strconv.ParseInt(r.FormValue("history_id"), 10, 64)
+	if err != nil {
+		return 0, entity.Money{}, errors.New("history not found")
+	}
+
+	opHistory, err := handler.app.HistoryService.FindHistory(historyID)
+	if err != nil {
+		return 0, entity.Money{}, err
+	}
+
+	amountString := strings.TrimSpace(r.FormValue("amount"))
+	if amountString == "" {
+		return historyID, entity.NewMoney(0, opHistory.Amount.Currency), nil
+	}
+
+	amount, err := entity.ParseMoney(amountString, opHistory.Amount.Currency)
+	if err != nil || !amount.IsPositive() {
+		return 0, entity.Money{}, errors.New(entity.AmountParsingError)
+	}
+
+	return historyID, amount, nil
+}
+
+// writeRefundResult is a method that writes the response of a refund or reversal request
+func (handler *UserAPIHandler) writeRefundResult(w http.ResponseWriter, format string,
+	opHistory *entity.UserHistory, err error) {
+
+	if err != nil {
+
+		// If error is any of the below then it will break out return bad request
+		// else it will enter the default section so it can return internal server error
+		switch err.Error() {
+		// Whitelisting errors
+		case "history not found":
+		case "cannot refund history which you haven't received":
+		case "amount currency doesn't match the history currency":
+		case "invalid amount used":
+		case entity.NonRefundableHistoryError:
+		case entity.RefundExceedsAmountError:
+		case entity.FullyRefundedError:
+		case entity.DisputedHistoryError:
+		case entity.InsufficientBalanceError:
+		case entity.QuarantinedWalletError:
+		case entity.ReceiverNotFoundError:
+		default:
+			// Any errors other than the above should be an internal server error
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write(output)
+			return
+		}
+
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(opHistory, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
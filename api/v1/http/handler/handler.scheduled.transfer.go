@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+	"github.com/gorilla/mux"
+)
+
+// HandleScheduleTransfer is a handler func that handles a request for scheduling a one-off or recurring transfer via onepay id.
+// The start time and the optional end date are sent in RFC3339 format.
+func (handler *UserAPIHandler) HandleScheduleTransfer(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	receiverID := r.FormValue("receiver_id")
+	frequency := strings.ToLower(strings.TrimSpace(r.FormValue("frequency")))
+	currency, err := tools.ParseCurrency(r.FormValue("currency"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	amount, err := entity.ParseMoney(r.FormValue("amount"), currency)
+	if err != nil || !amount.IsPositive() {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	startAt, err := time.Parse(time.RFC3339, r.FormValue("start_at"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.InvalidScheduleError}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	var endDate *time.Time
+	if endDateString := r.FormValue("end_date"); endDateString != "" {
+		parsedEndDate, err := time.Parse(time.RFC3339, endDateString)
+		if err != nil {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: entity.InvalidScheduleError}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+		endDate = &parsedEndDate
+	}
+
+	// Checking receiver account validity
+	if handler.dService.UserIsFrozen(receiverID) {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.FrozenAccountError}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	transfer, err := handler.app.ScheduleTransfer(opUser.UserID, receiverID, amount, frequency, startAt, endDate)
+	if err != nil {
+
+		// Whitelisting errors
+		if err.Error() == entity.TransactionBaseLimitError ||
+			err.Error() == entity.ExchangeRateNotFoundError ||
+			err.Error() == entity.InvalidFrequencyError ||
+			err.Error() == entity.InvalidScheduleError ||
+			err.Error() == entity.SenderNotFoundError ||
+			err.Error() == entity.ReceiverNotFoundError ||
+			err.Error() == entity.TransactionWSelfError {
+
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		// Any errors other than the above should be an internal server error
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(transfer, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetScheduledTransfers is a handler func that handles a request for viewing the user's scheduled transfers
+func (handler *UserAPIHandler) HandleGetScheduledTransfers(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	transfers := handler.app.ScheduledTransferService.SearchScheduledTransfers(opUser.UserID)
+	output, _ := tools.MarshalIndent(transfers, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleCancelScheduledTransfer is a handler func that handles a request for cancelling a scheduled transfer
+func (handler *UserAPIHandler) HandleCancelScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	transferID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "scheduled transfer not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	transfer, err := handler.app.CancelScheduledTransfer(opUser.UserID, transferID)
+	if err != nil {
+
+		if err.Error() == "scheduled transfer not found" ||
+			err.Error() == "scheduled transfer is not active" {
+
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(transfer, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
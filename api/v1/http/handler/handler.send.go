@@ -23,8 +23,16 @@ func (handler *UserAPIHandler) HandleSendMoneyViaQRCode(w http.ResponseWriter, r
 
 	format := mux.Vars(r)["format"]
 
+	currency, err := tools.ParseCurrency(r.FormValue("currency"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
 	amountString := r.FormValue("amount")
-	amount, err := strconv.ParseFloat(amountString, 64)
+	amount, err := entity.ParseMoney(amountString, currency)
 	if err != nil {
 		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
 		w.WriteHeader(http.StatusBadRequest)
@@ -32,25 +40,52 @@ func (handler *UserAPIHandler) HandleSendMoneyViaQRCode(w http.ResponseWriter, r
 		return
 	}
 
-	moneyToken, err := handler.app.SendViaQRCode(opUser.UserID, amount, handler.redisClient)
+	// A money token can be claimed only once unless the number of claims is provided
+	maxClaims := int64(1)
+	if r.FormValue("max_claims") != "" {
+		maxClaims, err = strconv.ParseInt(r.FormValue("max_claims"), 10, 64)
+		if err != nil {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: entity.InvalidMaxClaimsError}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+	}
 
-	if err != nil && err.Error() == entity.MoneyTokenCheckpointError {
+	claimMode := r.FormValue("claim_mode")
+	if claimMode == "" {
+		claimMode = entity.MoneyTokenClaimFixed
+	}
 
-		// requesting reload
-		handler.app.Channel <- "reload_money_token"
+	// The secret is optional, only users who know it will be able to claim the money token
+	secret := r.FormValue("secret")
 
+	expiry, err := parseExpiry(r.FormValue("expiry"))
+	if err != nil {
 		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(http.StatusBadRequest)
 		w.Write(output)
 		return
 	}
 
+	moneyToken, err := handler.app.SendViaQRCode(opUser.UserID, amount, int(maxClaims), claimMode, secret,
+		expiry, handler.redisClient)
+
 	if err != nil {
 
 		// Whitelisting errors
 		if err.Error() == entity.TransactionBaseLimitError ||
+			err.Error() == entity.TransactionLimitError ||
 			err.Error() == entity.DailyTransactionLimitError ||
-			err.Error() == entity.InsufficientBalanceError {
+			err.Error() == entity.WeeklyTransactionLimitError ||
+			err.Error() == entity.MonthlyTransactionLimitError ||
+			err.Error() == entity.ExchangeRateNotFoundError ||
+			err.Error() == entity.InsufficientBalanceError ||
+			err.Error() == entity.QuarantinedWalletError ||
+			err.Error() == entity.InvalidMaxClaimsError ||
+			err.Error() == entity.InvalidClaimModeError ||
+			err.Error() == entity.InvalidSecretFormatError ||
+			err.Error() == entity.InvalidExpiryError {
 
 			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
 			w.WriteHeader(http.StatusBadRequest)
@@ -87,8 +122,16 @@ func (handler *UserAPIHandler) HandleSendMoneyViaOnePayID(w http.ResponseWriter,
 	format := mux.Vars(r)["format"]
 
 	receiverID := r.FormValue("receiver_id")
+	currency, err := tools.ParseCurrency(r.FormValue("currency"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
 	amountString := r.FormValue("amount")
-	amount, err := strconv.ParseFloat(amountString, 64)
+	amount, err := entity.ParseMoney(amountString, currency)
 
 	if err != nil {
 		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
@@ -107,23 +150,19 @@ func (handler *UserAPIHandler) HandleSendMoneyViaOnePayID(w http.ResponseWriter,
 
 	err = handler.app.SendViaOnePayID(opUser.UserID, receiverID, amount, handler.redisClient)
 
-	if err != nil && err.Error() == entity.WalletCheckpointError {
-
-		// requesting reload
-		handler.app.Channel <- "reload_wallet"
-
-		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write(output)
-		return
-	}
-
-	if err != nil && err.Error() != entity.HistoryCheckpointError {
+	if err != nil {
 
 		// Whitelisting errors
 		if err.Error() == entity.TransactionBaseLimitError ||
+			err.Error() == entity.TransactionLimitError ||
 			err.Error() == entity.DailyTransactionLimitError ||
+			err.Error() == entity.WeeklyTransactionLimitError ||
+			err.Error() == entity.MonthlyTransactionLimitError ||
+			err.Error() == entity.ReceiveLimitError ||
+			err.Error() == entity.MaxBalanceError ||
+			err.Error() == entity.ExchangeRateNotFoundError ||
 			err.Error() == entity.InsufficientBalanceError ||
+			err.Error() == entity.QuarantinedWalletError ||
 			err.Error() == entity.SenderNotFoundError ||
 			err.Error() == entity.ReceiverNotFoundError ||
 			err.Error() == entity.TransactionWSelfError {
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+)
+
+// HandleImportSettlementStatement is a handler func that handles a staff member's request for importing
+// the settlement statement of an account provider and matching it with the recharge and withdrawal histories
+func (handler *UserAPIHandler) HandleImportSettlementStatement(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opStaff, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	fm, fh, err := r.FormFile("statement")
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "settlement statement file not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+	defer fm.Close()
+
+	// checking the file sent doesn't exceed the size limit
+	if fh.Size > 10000000 {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "settlement statement exceeds the file size limit, 10MB"},
+			"", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	statement, err := handler.app.ImportSettlementStatement(opStaff.UserID, r.FormValue("provider_id"),
+		fh.Filename, r.FormValue("statement_format"), fm)
+	if err != nil {
+
+		switch err.Error() {
+		case "account provider not found":
+		case entity.InvalidSettlementFormatError:
+		case entity.InvalidSettlementLineError:
+		case entity.EmptySettlementError:
+		case entity.UnsupportedCurrencyError:
+		default:
+			// Any errors other than the above should be an internal server error
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write(output)
+			return
+		}
+
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	handler.writeSettlementStatement(w, format, statement)
+}
+
+// HandleGetSettlementStatements is a handler func that handles a staff member's request for viewing the imported settlement statements per page
+func (handler *UserAPIHandler) HandleGetSettlementStatements(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+	pagenation, _ := strconv.ParseInt(r.FormValue("page"), 0, 64)
+
+	statements, pageCount := handler.app.SettlementService.SearchStatements(r.FormValue("provider_id"), pagenation)
+
+	output, _ := tools.MarshalIndent(SettlementStatementsContainer{
+		Result: statements, CurrentPage: pagenation, PageCount: pageCount}, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetSettlementStatement is a handler func that handles a staff member's request for viewing a settlement statement with its items
+func (handler *UserAPIHandler) HandleGetSettlementStatement(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	statementID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	statement, err := handler.app.SettlementService.FindStatement(statementID)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	handler.writeSettlementStatement(w, format, statement)
+}
+
+// HandleGetSettlementItems is a handler func that handles a staff member's request for viewing the settlement items of the provided statuses.
+// If no status is provided the items that still have to be resolved are returned.
+func (handler *UserAPIHandler) HandleGetSettlementItems(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	_, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	statuses := strings.Fields(r.FormValue("statuses"))
+	if len(statuses) == 0 {
+		statuses = []string{entity.SettlementStatusMismatched, entity.SettlementStatusUnmatched, entity.SettlementStatusMissing}
+	}
+
+	output, _ := tools.MarshalIndent(handler.app.SettlementService.ItemsByStatus(statuses...), "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleResolveSettlementItem is a handler func that handles a staff member's request for resolving a settlement item that hasn't matched
+func (handler *UserAPIHandler) HandleResolveSettlementItem(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opStaff, ok := ctx.Value(entity.Key("onepay_staff")).(*entity.Staff)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	itemID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	item, err := handler.app.ResolveSettlementItem(opStaff.UserID, itemID, r.FormValue("note"))
+	if err != nil {
+
+		switch err.Error() {
+		case "settlement item not found":
+		case "resolution note can not be empty":
+		case entity.SettlementItemClosedError:
+		default:
+			// Any errors other than the above should be an internal server error
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write(output)
+			return
+		}
+
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(item, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// writeSettlementStatement is a method that writes a settlement statement along with its items
+func (handler *UserAPIHandler) writeSettlementStatement(w http.ResponseWriter, format string,
+	statement *entity.SettlementStatement) {
+
+	container := SettlementStatementContainer{Statement: statement,
+		Items: handler.app.SettlementService.StatementItems(int64(statement.ID))}
+	output, _ := tools.MarshalIndent(container, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
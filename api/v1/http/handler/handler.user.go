@@ -175,6 +175,7 @@ func (handler *UserAPIHandler) HandleFinishAddUser(w http.ResponseWriter, r *htt
 	// Adding wallet to the user
 	newOPWallet := new(entity.UserWallet)
 	newOPWallet.UserID = newOPUser.UserID
+	newOPWallet.Currency = entity.BaseCurrency
 	err = handler.app.WalletService.AddWallet(newOPWallet)
 	if err != nil {
 		// This is cleaning up if the wallet is not created
@@ -804,8 +805,8 @@ func (handler *UserAPIHandler) HandleDeleteUser(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// Deleting the user's wallet
-	handler.app.WalletService.DeleteWallet(opUser.UserID)
+	// Deleting all the user's wallets
+	handler.app.WalletService.DeleteWallets(opUser.UserID)
 
 	// Removing all the user's linked accounts
 	handler.app.LinkedAccountService.DeleteLinkedAccounts(opUser.UserID)
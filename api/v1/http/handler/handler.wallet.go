@@ -2,14 +2,13 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/Benyam-S/onepay/entity"
 	"github.com/Benyam-S/onepay/tools"
 	"github.com/gorilla/mux"
 )
 
-// HandleGetUserWallet is a handler func that handles the request for getting the user's wallet
+// HandleGetUserWallet is a handler func that handles the request for getting the user's wallet of a certain currency
 func (handler *UserAPIHandler) HandleGetUserWallet(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
@@ -22,7 +21,15 @@ func (handler *UserAPIHandler) HandleGetUserWallet(w http.ResponseWriter, r *htt
 
 	format := mux.Vars(r)["format"]
 
-	opWallet, err := handler.app.WalletService.FindWallet(opUser.UserID)
+	currency, err := tools.ParseCurrency(r.FormValue("currency"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	opWallet, err := handler.app.WalletService.FindWallet(opUser.UserID, currency)
 	if err != nil {
 		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
 		w.WriteHeader(http.StatusBadRequest)
@@ -36,6 +43,91 @@ func (handler *UserAPIHandler) HandleGetUserWallet(w http.ResponseWriter, r *htt
 	return
 }
 
+// HandleGetUserWallets is a handler func that handles the request for getting all the wallets of the user
+func (handler *UserAPIHandler) HandleGetUserWallets(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	opWallets := handler.app.WalletService.SearchWallets(opUser.UserID)
+
+	output, _ := tools.MarshalIndent(opWallets, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+	return
+}
+
+// HandleConvertCurrency is a handler func that handles the request for converting money between the user's wallets
+func (handler *UserAPIHandler) HandleConvertCurrency(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	fromCurrency, err := tools.ParseCurrency(r.FormValue("from"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	toCurrency, err := tools.ParseCurrency(r.FormValue("to"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	amountString := r.FormValue("amount")
+	amount, err := entity.ParseMoney(amountString, fromCurrency)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	opHistory, err := handler.app.ConvertCurrency(opUser.UserID, amount, toCurrency)
+	if err != nil {
+
+		// Any wallet or ledger error should be an internal server error
+		if err.Error() == "unable to update user wallet" ||
+			err.Error() == "unable to add new user wallet" ||
+			err.Error() == "unable to add new history" ||
+			err.Error() == entity.TransactionCommitError {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write(output)
+			return
+		}
+
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(opHistory, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+	return
+}
+
 // HandleDrainWallet is a handler func that handles the request for draining/emptying a wallet
 func (handler *UserAPIHandler) HandleDrainWallet(w http.ResponseWriter, r *http.Request) {
 
@@ -50,7 +142,7 @@ func (handler *UserAPIHandler) HandleDrainWallet(w http.ResponseWriter, r *http.
 	format := mux.Vars(r)["format"]
 	linkedAccountID := r.FormValue("linked_account")
 
-	err := handler.app.DrainWallet(opUser.UserID, linkedAccountID)
+	err := handler.app.DrainWallet(opUser.UserID, linkedAccountID, handler.redisClient)
 	if err != nil && err.Error() == entity.WalletCheckpointError {
 
 		// requesting reload
@@ -106,7 +198,7 @@ func (handler *UserAPIHandler) HandleRechargeWallet(w http.ResponseWriter, r *ht
 	format := mux.Vars(r)["format"]
 	linkedAccountID := r.FormValue("linked_account")
 	amountString := r.FormValue("amount")
-	amount, err := strconv.ParseFloat(amountString, 64)
+	amount, err := entity.ParseMoney(amountString, entity.BaseCurrency)
 	if err != nil {
 		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
 		w.WriteHeader(http.StatusBadRequest)
@@ -157,7 +249,7 @@ func (handler *UserAPIHandler) HandleWithdrawFromWallet(w http.ResponseWriter, r
 	format := mux.Vars(r)["format"]
 	linkedAccountID := r.FormValue("linked_account")
 	amountString := r.FormValue("amount")
-	amount, err := strconv.ParseFloat(amountString, 64)
+	amount, err := entity.ParseMoney(amountString, entity.BaseCurrency)
 	if err != nil {
 		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
 		w.WriteHeader(http.StatusBadRequest)
@@ -165,7 +257,7 @@ func (handler *UserAPIHandler) HandleWithdrawFromWallet(w http.ResponseWriter, r
 		return
 	}
 
-	err = handler.app.WithdrawFromWallet(opUser.UserID, linkedAccountID, amount)
+	err = handler.app.WithdrawFromWallet(opUser.UserID, linkedAccountID, amount, handler.redisClient)
 	if err != nil && err.Error() == entity.WalletCheckpointError {
 
 		// requesting reload
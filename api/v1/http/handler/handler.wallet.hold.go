@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Benyam-S/onepay/api"
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+)
+
+// HandleAuthorizeHold is a handler func that handles a request for holding money on the user's wallet for a merchant.
+// The optional expiry is sent in hours.
+func (handler *UserAPIHandler) HandleAuthorizeHold(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok1 := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+	apiToken, ok2 := ctx.Value(entity.Key("onepay_api_token")).(*api.Token)
+
+	if !ok1 || !ok2 {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	merchantID := r.FormValue("merchant_id")
+	currency, err := tools.ParseCurrency(r.FormValue("currency"))
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	amount, err := entity.ParseMoney(r.FormValue("amount"), currency)
+	if err != nil || !amount.IsPositive() {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	var expiry time.Duration
+	if expiresIn := r.FormValue("expires_in"); expiresIn != "" {
+		hours, err := strconv.ParseInt(expiresIn, 10, 64)
+		if err != nil || hours <= 0 {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: "invalid expiry used"}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+		expiry = time.Duration(hours) * time.Hour
+	}
+
+	// Checking merchant account validity
+	if handler.dService.UserIsFrozen(merchantID) {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.FrozenAccountError}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	opHold, err := handler.app.AuthorizeHold(opUser.UserID, merchantID, apiToken.APIKey, amount,
+		r.FormValue("reference"), expiry, handler.redisClient)
+	if err != nil {
+
+		// Whitelisting errors
+		if err.Error() == entity.TransactionBaseLimitError ||
+			err.Error() == entity.TransactionLimitError ||
+			err.Error() == entity.DailyTransactionLimitError ||
+			err.Error() == entity.WeeklyTransactionLimitError ||
+			err.Error() == entity.MonthlyTransactionLimitError ||
+			err.Error() == entity.ReceiveLimitError ||
+			err.Error() == entity.MaxBalanceError ||
+			err.Error() == entity.InsufficientBalanceError ||
+			err.Error() == entity.QuarantinedWalletError ||
+			err.Error() == entity.ReceiverNotFoundError ||
+			err.Error() == entity.TransactionWSelfError {
+
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		// Any errors other than the above should be an internal server error
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(opHold, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleCaptureHold is a handler func that handles a request for capturing all or part of a hold.
+// When no amount is sent the whole held amount is captured.
+func (handler *UserAPIHandler) HandleCaptureHold(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok1 := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+	apiToken, ok2 := ctx.Value(entity.Key("onepay_api_token")).(*api.Token)
+
+	if !ok1 || !ok2 {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	holdID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "hold not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	var amount entity.Money
+	if amountString := r.FormValue("amount"); amountString != "" {
+		currency, err := tools.ParseCurrency(r.FormValue("currency"))
+		if err != nil {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		amount, err = entity.ParseMoney(amountString, currency)
+		if err != nil || !amount.IsPositive() {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: entity.AmountParsingError}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+	}
+
+	opHold, err := handler.app.CaptureHold(opUser.UserID, apiToken.APIKey, holdID, amount, handler.redisClient)
+	if err != nil {
+
+		// Whitelisting errors
+		if err.Error() == "hold not found" ||
+			err.Error() == "amount currency doesn't match the hold currency" ||
+			err.Error() == entity.ClosedHoldError ||
+			err.Error() == entity.CaptureExceedsHoldError ||
+			err.Error() == entity.FeeExceedsAmountError ||
+			err.Error() == entity.ReceiverNotFoundError {
+
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		// Any errors other than the above should be an internal server error
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(opHold, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleVoidHold is a handler func that handles a request for voiding a hold and releasing its money back to the user
+func (handler *UserAPIHandler) HandleVoidHold(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok1 := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+	apiToken, ok2 := ctx.Value(entity.Key("onepay_api_token")).(*api.Token)
+
+	if !ok1 || !ok2 {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	holdID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "hold not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	opHold, err := handler.app.VoidHold(opUser.UserID, apiToken.APIKey, holdID, handler.redisClient)
+	if err != nil {
+
+		if err.Error() == "hold not found" || err.Error() == entity.ClosedHoldError {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(opHold, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetHold is a handler func that handles a request for viewing a hold the user has placed or received
+func (handler *UserAPIHandler) HandleGetHold(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	holdID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "hold not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	opHold, err := handler.app.HoldService.FindHold(holdID)
+	if err != nil || (opHold.UserID != opUser.UserID && opHold.MerchantID != opUser.UserID) {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: "hold not found"}, "", "\t", format)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(output)
+		return
+	}
+
+	output, _ := tools.MarshalIndent(opHold, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// HandleGetHolds is a handler func that handles a request for viewing the holds the user has placed or received
+func (handler *UserAPIHandler) HandleGetHolds(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+
+	holds := handler.app.HoldService.SearchHolds(opUser.UserID)
+	output, _ := tools.MarshalIndent(holds, "", "\t", format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+)
+
+// responseRecorder is a type that captures the status code and body written by a handler while passing them through
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       []byte
+}
+
+// WriteHeader is a method that records the status code before writing it
+func (recorder *responseRecorder) WriteHeader(statusCode int) {
+	recorder.statusCode = statusCode
+	recorder.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write is a method that records the body before writing it
+func (recorder *responseRecorder) Write(body []byte) (int, error) {
+	recorder.body = append(recorder.body, body...)
+	return recorder.ResponseWriter.Write(body)
+}
+
+// IdempotentRequest is a middleware that makes sure a request sent with an idempotency key is only processed once.
+// A repeated request replays the stored response and a reused key with a different payload is rejected.
+// Only successful and client error responses are stored, a request that fails with a server error can be retried.
+func (handler *UserAPIHandler) IdempotentRequest(next http.HandlerFunc) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		idempotencyKey := r.Header.Get(entity.IdempotencyKeyHeader)
+
+		// Idempotency key is optional so requests without it are processed as usual
+		if idempotencyKey == "" {
+			next(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		format := mux.Vars(r)["format"]
+
+		fingerprint, err := tools.RequestFingerprint(r)
+		if err != nil {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(output)
+			return
+		}
+
+		// Keys are scoped to the user so different users can't collide with each other
+		key := entity.IdempotencyKeyPrefix + opUser.UserID + "-" + idempotencyKey
+
+		pending, _ := json.Marshal(entity.IdempotentResponse{Fingerprint: fingerprint})
+		claimed, err := tools.SetValueIfAbsent(handler.redisClient, key, string(pending), time.Hour*24)
+		if err != nil {
+			output, _ := tools.MarshalIndent(ErrorBody{Error: err.Error()}, "", "\t", format)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write(output)
+			return
+		}
+
+		if !claimed {
+			handler.replayIdempotentResponse(w, key, fingerprint, format)
+			return
+		}
+
+		// The pending key is removed if the handler panics or doesn't finish, so the request isn't kept in progress
+		completed := false
+		defer func() {
+			if !completed {
+				tools.RemoveValues(handler.redisClient, key)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next(recorder, r)
+		completed = true
+
+		// Server errors aren't stored so the request can be retried with the same key
+		if recorder.statusCode >= http.StatusInternalServerError {
+			tools.RemoveValues(handler.redisClient, key)
+			return
+		}
+
+		storedResponse := entity.IdempotentResponse{Fingerprint: fingerprint, Completed: true,
+			StatusCode: recorder.statusCode, ContentType: recorder.Header().Get("Content-Type"), Body: recorder.body}
+
+		output, _ := json.Marshal(storedResponse)
+		tools.SetValue(handler.redisClient, key, string(output), time.Hour*24)
+	}
+}
+
+// replayIdempotentResponse is a method that writes the stored response of an already processed idempotency key
+func (handler *UserAPIHandler) replayIdempotentResponse(w http.ResponseWriter, key, fingerprint, format string) {
+
+	storedValue, err := tools.GetValue(handler.redisClient, key)
+	storedResponse := new(entity.IdempotentResponse)
+	if err == nil {
+		err = json.Unmarshal([]byte(storedValue), storedResponse)
+	}
+
+	if err != nil {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.IdempotentRequestInProgressError}, "", "\t", format)
+		w.WriteHeader(http.StatusConflict)
+		w.Write(output)
+		return
+	}
+
+	if storedResponse.Fingerprint != fingerprint {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.IdempotencyKeyReusedError}, "", "\t", format)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write(output)
+		return
+	}
+
+	if !storedResponse.Completed {
+		output, _ := tools.MarshalIndent(ErrorBody{Error: entity.IdempotentRequestInProgressError}, "", "\t", format)
+		w.WriteHeader(http.StatusConflict)
+		w.Write(output)
+		return
+	}
+
+	if storedResponse.ContentType != "" {
+		w.Header().Set("Content-Type", storedResponse.ContentType)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(storedResponse.StatusCode)
+	w.Write(storedResponse.Body)
+}
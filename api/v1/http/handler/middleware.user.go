@@ -126,6 +126,33 @@ func (handler *UserAPIHandler) Authorization(next http.HandlerFunc) http.Handler
 	}
 }
 
+// StaffAuthorization is a middleware that authorize whether the onepay user of the request is a staff member.
+// It has to run after the Authorization middleware since it uses the onepay user that is added to the context.
+func (handler *UserAPIHandler) StaffAuthorization(next http.HandlerFunc) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx := r.Context()
+		opUser, ok := ctx.Value(entity.Key("onepay_user")).(*entity.User)
+
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		staffMember, err := handler.uService.FindStaffMember(opUser.UserID)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		ctx = context.WithValue(ctx, entity.Key("onepay_staff"), staffMember)
+		r = r.Clone(ctx)
+
+		next(w, r)
+	}
+}
+
 // AuthenticateScope is a middleware that checks if the api token scope is compliant with the request
 func (handler *UserAPIHandler) AuthenticateScope(next http.HandlerFunc) http.HandlerFunc {
 
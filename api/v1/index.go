@@ -18,6 +18,7 @@ func Start(handler *handler.UserAPIHandler, router *mux.Router) {
 	moneyTokenRoutes(handler, router)
 	websocketRoutes(handler, router)
 	extraRoutes(handler, router)
+	staffRoutes(handler, router)
 
 }
 
@@ -125,9 +126,20 @@ func transactionRoutes(handler *handler.UserAPIHandler, router *mux.Router) {
 		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("POST")
 
 	router.HandleFunc("/api/v1/oauth/send/id.{format:json|xml}", tools.MiddlewareFactory(handler.HandleSendMoneyViaOnePayID,
-		handler.Authorization, handler.APITokenDEValidation,
+		handler.IdempotentRequest, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("POST")
+
+	router.HandleFunc("/api/v1/oauth/send/scheduled.{format:json|xml}", tools.MiddlewareFactory(handler.HandleScheduleTransfer,
+		handler.IdempotentRequest, handler.Authorization, handler.APITokenDEValidation,
 		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("POST")
 
+	router.HandleFunc("/api/v1/oauth/send/scheduled.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetScheduledTransfers,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/send/scheduled.{format:json|xml}", tools.MiddlewareFactory(handler.HandleCancelScheduledTransfer,
+		handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("DELETE")
+
 	router.HandleFunc("/api/v1/oauth/receive/code.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetReceiveInfo,
 		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
 
@@ -138,11 +150,71 @@ func transactionRoutes(handler *handler.UserAPIHandler, router *mux.Router) {
 		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
 
 	router.HandleFunc("/api/v1/oauth/pay/code.{format:json|xml}", tools.MiddlewareFactory(handler.HandlePayViaQRCode,
-		handler.Authorization, handler.APITokenDEValidation,
+		handler.IdempotentRequest, handler.Authorization, handler.APITokenDEValidation,
 		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
 
 	router.HandleFunc("/api/v1/oauth/pay/code.{format:json|xml}", tools.MiddlewareFactory(handler.HandleCreatePaymentToken,
 		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("POST")
+
+	router.HandleFunc("/api/v1/oauth/pay/merchant/qr.{format:png|svg}", tools.MiddlewareFactory(handler.HandleGetMerchantQRCode,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/receive/request.{format:json|xml}", tools.MiddlewareFactory(handler.HandleRequestPayment,
+		handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("POST")
+
+	router.HandleFunc("/api/v1/oauth/receive/requests.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetSentPaymentRequests,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/receive/request/cancel.{format:json|xml}", tools.MiddlewareFactory(handler.HandleCancelPaymentRequest,
+		handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/receive/group.{format:json|xml}", tools.MiddlewareFactory(handler.HandleSplitPayment,
+		handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("POST")
+
+	router.HandleFunc("/api/v1/oauth/receive/group.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetPaymentGroup,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/receive/groups.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetPaymentGroups,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/receive/group/remind.{format:json|xml}", tools.MiddlewareFactory(handler.HandleRemindPaymentGroup,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/pay/requests.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetReceivedPaymentRequests,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/pay/request.{format:json|xml}", tools.MiddlewareFactory(handler.HandlePayPaymentRequest,
+		handler.IdempotentRequest, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/pay/request/decline.{format:json|xml}", tools.MiddlewareFactory(handler.HandleDeclinePaymentRequest,
+		handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/send/refund.{format:json|xml}", tools.MiddlewareFactory(handler.HandleRefundHistory,
+		handler.IdempotentRequest, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/hold/authorize.{format:json|xml}", tools.MiddlewareFactory(handler.HandleAuthorizeHold,
+		handler.IdempotentRequest, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("POST")
+
+	router.HandleFunc("/api/v1/oauth/hold/capture.{format:json|xml}", tools.MiddlewareFactory(handler.HandleCaptureHold,
+		handler.IdempotentRequest, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/hold/void.{format:json|xml}", tools.MiddlewareFactory(handler.HandleVoidHold,
+		handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/hold.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetHold,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/holds.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetHolds,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
 }
 
 // walletNHistoryRoutes is a function that defines all the routes for accessing user wallet and it's history
@@ -154,12 +226,25 @@ func walletNHistoryRoutes(handler *handler.UserAPIHandler, router *mux.Router) {
 	router.HandleFunc("/api/v1/oauth/user/wallet.{format:json|xml}", tools.MiddlewareFactory(handler.HandleMarkWalletAsViewed,
 		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
 
+	router.HandleFunc("/api/v1/oauth/user/wallets.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetUserWallets,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/user/wallet/limit.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetUserLimit,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/user/wallet/limit/remaining.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetRemainingLimits,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/user/wallet/convert.{format:json|xml}", tools.MiddlewareFactory(handler.HandleConvertCurrency,
+		handler.IdempotentRequest, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
 	router.HandleFunc("/api/v1/oauth/user/wallet/recharge.{format:json|xml}", tools.MiddlewareFactory(handler.HandleRechargeWallet,
-		handler.Authorization, handler.APITokenDEValidation,
+		handler.IdempotentRequest, handler.Authorization, handler.APITokenDEValidation,
 		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
 
 	router.HandleFunc("/api/v1/oauth/user/wallet/withdraw.{format:json|xml}", tools.MiddlewareFactory(handler.HandleWithdrawFromWallet,
-		handler.Authorization, handler.APITokenDEValidation,
+		handler.IdempotentRequest, handler.Authorization, handler.APITokenDEValidation,
 		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
 
 	router.HandleFunc("/api/v1/oauth/user/wallet/drain.{format:json|xml}", tools.MiddlewareFactory(handler.HandleDrainWallet,
@@ -171,6 +256,19 @@ func walletNHistoryRoutes(handler *handler.UserAPIHandler, router *mux.Router) {
 
 	router.HandleFunc("/api/v1/oauth/user/history.{format:json|xml}", tools.MiddlewareFactory(handler.HandleMarkHistoriesAsViewed,
 		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/user/history/dispute.{format:json|xml}", tools.MiddlewareFactory(handler.HandleOpenDispute,
+		handler.IdempotentRequest, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("POST")
+
+	router.HandleFunc("/api/v1/oauth/user/history/dispute.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetUserDispute,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/user/history/disputes.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetUserDisputes,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/user/history/dispute/evidence.{format:json|xml}", tools.MiddlewareFactory(handler.HandleAddDisputeEvidence,
+		handler.Authorization, handler.APITokenDEValidation, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("POST")
 }
 
 // linkedAccountRoutes is a function that defines all the routes for accessing linked accounts of a certain user
@@ -205,6 +303,9 @@ func moneyTokenRoutes(handler *handler.UserAPIHandler, router *mux.Router) {
 	router.HandleFunc("/api/v1/oauth/user/moneytoken.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetUserMoneyTokens,
 		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
 
+	router.HandleFunc("/api/v1/oauth/user/moneytoken/qr.{format:png|svg}", tools.MiddlewareFactory(handler.HandleGetMoneyTokenQRCode,
+		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
 	router.HandleFunc("/api/v1/oauth/user/moneytoken/refresh.{format:json|xml}", tools.MiddlewareFactory(handler.HandleRefreshMoneyTokens,
 		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
 
@@ -213,6 +314,8 @@ func moneyTokenRoutes(handler *handler.UserAPIHandler, router *mux.Router) {
 
 	router.HandleFunc("/api/v1/oauth/user/moneytoken/remove.{format:json|xml}", tools.MiddlewareFactory(handler.HandleRemoveMoneyTokens,
 		handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("POST")
+
+	router.HandleFunc("/api/v1/qr/jwks.json", handler.HandleGetQRSigningKeys).Methods("GET")
 }
 
 // websocketRoutes is a function that defines all websocket related routes
@@ -229,6 +332,12 @@ func websocketRoutes(handler *handler.UserAPIHandler, router *mux.Router) {
 
 	router.HandleFunc("/api/v1/listener/history", handler.HandleListenToHistoryChange).Methods("PUT")
 
+	router.HandleFunc("/api/v1/listener/scheduledtransfer", handler.HandleListenToScheduledTransferChange).Methods("PUT")
+
+	router.HandleFunc("/api/v1/listener/paymentrequest", handler.HandleListenToPaymentRequestChange).Methods("PUT")
+
+	router.HandleFunc("/api/v1/listener/moneytoken", handler.HandleListenToMoneyTokenChange).Methods("PUT")
+
 }
 
 func extraRoutes(handler *handler.UserAPIHandler, router *mux.Router) {
@@ -239,3 +348,76 @@ func extraRoutes(handler *handler.UserAPIHandler, router *mux.Router) {
 	router.HandleFunc("/api/v1/oauth/user/notifications.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetNotifications, handler.Authorization,
 		handler.AccessTokenAuthentication)).Methods("GET")
 }
+
+// staffRoutes is a function that defines all the routes that can only be accessed by staff members
+func staffRoutes(handler *handler.UserAPIHandler, router *mux.Router) {
+
+	router.HandleFunc("/api/v1/oauth/staff/history/reverse.{format:json|xml}", tools.MiddlewareFactory(handler.HandleReverseHistory,
+		handler.IdempotentRequest, handler.StaffAuthorization, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/staff/disputes.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetDisputes,
+		handler.StaffAuthorization, handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/staff/dispute.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetDispute,
+		handler.StaffAuthorization, handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/staff/dispute/evidence", tools.MiddlewareFactory(handler.HandleGetDisputeEvidence,
+		handler.StaffAuthorization, handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/staff/dispute/review.{format:json|xml}", tools.MiddlewareFactory(handler.HandleReviewDispute,
+		handler.StaffAuthorization, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/staff/dispute/resolve.{format:json|xml}", tools.MiddlewareFactory(handler.HandleResolveDispute,
+		handler.IdempotentRequest, handler.StaffAuthorization, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/staff/user/limit.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetStaffUserLimit,
+		handler.StaffAuthorization, handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/staff/user/limit.{format:json|xml}", tools.MiddlewareFactory(handler.HandleAssignUserLimit,
+		handler.StaffAuthorization, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/staff/reconciliation.{format:json|xml}", tools.MiddlewareFactory(handler.HandleReconcileWallets,
+		handler.StaffAuthorization, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/staff/reconciliation/reports.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetReconciliationReports,
+		handler.StaffAuthorization, handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/staff/reconciliation/report.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetReconciliationReport,
+		handler.StaffAuthorization, handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/staff/wallet/quarantine.{format:json|xml}", tools.MiddlewareFactory(handler.HandleQuarantineWallet,
+		handler.StaffAuthorization, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/staff/ledger/audit.{format:json|xml}", tools.MiddlewareFactory(handler.HandleAuditLedger,
+		handler.StaffAuthorization, handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/staff/wallet/rebuild.{format:json|xml}", tools.MiddlewareFactory(handler.HandleRebuildWallet,
+		handler.StaffAuthorization, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/staff/settlement/import.{format:json|xml}", tools.MiddlewareFactory(handler.HandleImportSettlementStatement,
+		handler.StaffAuthorization, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("POST")
+
+	router.HandleFunc("/api/v1/oauth/staff/settlement/statements.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetSettlementStatements,
+		handler.StaffAuthorization, handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/staff/settlement/statement.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetSettlementStatement,
+		handler.StaffAuthorization, handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/staff/settlement/items.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetSettlementItems,
+		handler.StaffAuthorization, handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+
+	router.HandleFunc("/api/v1/oauth/staff/settlement/item/resolve.{format:json|xml}", tools.MiddlewareFactory(handler.HandleResolveSettlementItem,
+		handler.StaffAuthorization, handler.Authorization, handler.APITokenDEValidation,
+		handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("PUT")
+
+	router.HandleFunc("/api/v1/oauth/staff/jobs/runs.{format:json|xml}", tools.MiddlewareFactory(handler.HandleGetJobRuns,
+		handler.StaffAuthorization, handler.Authorization, handler.AuthenticateScope, handler.AccessTokenAuthentication)).Methods("GET")
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// ConvertCurrency is a method that converts money from one of the user's wallets to the user's wallet of the provided currency.
+// The exchange rate is reduced by the currency spread and both the applied rate and the spread are recorded on the history.
+func (onepay *OnePay) ConvertCurrency(userID string, amount entity.Money, toCurrency string) (*entity.UserHistory, error) {
+
+	toCurrency = strings.ToUpper(toCurrency)
+	if !entity.IsSupportedCurrency(amount.Currency) || !entity.IsSupportedCurrency(toCurrency) {
+		return nil, errors.New(entity.UnsupportedCurrencyError)
+	}
+
+	if amount.Currency == toCurrency {
+		return nil, errors.New(entity.SameCurrencyConversionError)
+	}
+
+	if !amount.IsPositive() {
+		return nil, errors.New("invalid amount used")
+	}
+
+	rate, err := ExchangeRate(amount.Currency, toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	spread := CurrencySpread()
+	appliedRate := rate * (1 - spread/100)
+
+	convertedAmount := amount.Convert(appliedRate, toCurrency)
+	if !convertedAmount.IsPositive() {
+		return nil, errors.New("amount is too small to be converted")
+	}
+
+	opHistory := new(entity.UserHistory)
+	err = onepay.RunInTransaction(func(tx *Transaction) error {
+
+		fromOPWallet, err := tx.WalletService.FindWallet(userID, amount.Currency)
+		if err != nil {
+			return errors.New(entity.InsufficientBalanceError)
+		}
+
+		if fromOPWallet.Amount.LessThan(amount) {
+			return errors.New(entity.InsufficientBalanceError)
+		}
+
+		toOPWallet, err := tx.ReceivingWallet(userID, toCurrency)
+		if err != nil {
+			return err
+		}
+
+		err = tx.WalletService.DebitWallet(fromOPWallet, amount)
+		if err != nil {
+			return err
+		}
+
+		err = tx.WalletService.CreditWallet(toOPWallet, convertedAmount)
+		if err != nil {
+			return err
+		}
+
+		// Recording the conversion in the ledger, the exchange account balances each currency
+		err = tx.AddJournalEntry(entity.MethodCurrencyConversion, "", WalletPosting(userID, amount.Neg()),
+			ExchangePosting(amount), ExchangePosting(convertedAmount.Neg()), WalletPosting(userID, convertedAmount))
+		if err != nil {
+			return err
+		}
+
+		// Adding history for the conversion with the applied rate and spread
+		opHistory = new(entity.UserHistory)
+		opHistory.SenderID = userID
+		opHistory.ReceiverID = userID
+		opHistory.Method = entity.MethodCurrencyConversion
+		opHistory.Amount = amount
+		opHistory.Fee = entity.NewMoney(0, amount.Currency)
+		opHistory.ConvertedAmount = convertedAmount
+		opHistory.ExchangeRate = appliedRate
+		opHistory.Spread = spread
+		opHistory.SentAt = time.Now()
+		opHistory.ReceivedAt = time.Now()
+
+		return tx.HistoryService.AddHistory(opHistory)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opHistory, nil
+}
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/Benyam-S/onepay/entity"
@@ -20,6 +21,12 @@ func (onepay *OnePay) GetCurrencyRates(base string) ([]*entity.CurrencyRate, err
 		return nil, errors.New("unknown base currency")
 	}
 
+	return readCurrencyRates(base)
+}
+
+// readCurrencyRates is a function that reads the currency rates of the provided base currency from the currency assets
+func readCurrencyRates(base string) ([]*entity.CurrencyRate, error) {
+
 	listOfCurrencyRates := make([]*entity.CurrencyRate, 0)
 	wd, _ := os.Getwd()
 	filePath := filepath.Join(wd,
@@ -37,3 +44,83 @@ func (onepay *OnePay) GetCurrencyRates(base string) ([]*entity.CurrencyRate, err
 
 	return listOfCurrencyRates, nil
 }
+
+// ExchangeRate is a function that returns the rate a money value of the from currency is multiplied by
+// to get its value in the to currency. If the rates of the from currency are not available
+// the inverse of the to currency rate is used.
+func ExchangeRate(from, to string) (float64, error) {
+
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+
+	if !entity.IsSupportedCurrency(from) || !entity.IsSupportedCurrency(to) {
+		return 0, errors.New(entity.UnsupportedCurrencyError)
+	}
+
+	if from == to {
+		return 1, nil
+	}
+
+	rates, err := readCurrencyRates(from)
+	if err == nil {
+		for _, rate := range rates {
+			if strings.ToUpper(rate.ToSymbol) == to && rate.CurrentValue > 0 {
+				return rate.CurrentValue, nil
+			}
+		}
+	}
+
+	rates, err = readCurrencyRates(to)
+	if err == nil {
+		for _, rate := range rates {
+			if strings.ToUpper(rate.ToSymbol) == from && rate.CurrentValue > 0 {
+				return 1 / rate.CurrentValue, nil
+			}
+		}
+	}
+
+	return 0, errors.New(entity.ExchangeRateNotFoundError)
+}
+
+// ToBaseCurrency is a function that converts the provided amount to the base currency using the current exchange rate.
+// It is used to compare amounts of any currency with the limits and fee schedules that are set in the base currency.
+func ToBaseCurrency(amount entity.Money) (entity.Money, error) {
+
+	currency := entity.NewMoney(0, amount.Currency).Currency
+	if currency == entity.BaseCurrency {
+		return entity.NewMoney(amount.Minor, entity.BaseCurrency), nil
+	}
+
+	rate, err := ExchangeRate(currency, entity.BaseCurrency)
+	if err != nil {
+		return entity.Money{}, err
+	}
+
+	return amount.Convert(rate, entity.BaseCurrency), nil
+}
+
+// FromBaseCurrency is a function that converts the provided base currency amount to the provided currency
+func FromBaseCurrency(amount entity.Money, currency string) (entity.Money, error) {
+
+	currency = entity.NewMoney(0, currency).Currency
+	if currency == entity.BaseCurrency {
+		return entity.NewMoney(amount.Minor, entity.BaseCurrency), nil
+	}
+
+	rate, err := ExchangeRate(entity.BaseCurrency, currency)
+	if err != nil {
+		return entity.Money{}, err
+	}
+
+	return amount.Convert(rate, currency), nil
+}
+
+// CurrencySpread is a function that returns the percentage taken from the exchange rate when money is converted
+func CurrencySpread() float64 {
+
+	spread, err := strconv.ParseFloat(os.Getenv(entity.CurrencySpread), 64)
+	if err != nil || spread < 0 || spread >= 100 {
+		return 0
+	}
+	return spread
+}
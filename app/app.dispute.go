@@ -0,0 +1,289 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// DisputeOpeningPeriod is a constant that defines how long after a payment its payer can open a dispute
+const DisputeOpeningPeriod = time.Hour * 24 * 60
+
+// DisputeEvidencePeriod is a constant that defines how long both parties have to submit evidence after a dispute is opened
+const DisputeEvidencePeriod = time.Hour * 24 * 10
+
+// OpenDispute is a method that enables the payer of a qr code payment to contest it.
+// Opening a dispute holds the disputed amount, or as much of it as is available, from the wallet of the counterparty
+// until the dispute is resolved. The amount is held even if the counterparty's wallet is quarantined.
+func (onepay *OnePay) OpenDispute(userID string, historyID int64, reason string) (*entity.Dispute, error) {
+
+	opDispute := new(entity.Dispute)
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+		opHistory, err := tx.HistoryService.LockHistory(historyID)
+		if err != nil {
+			return err
+		}
+
+		if opHistory.Method != entity.MethodPaymentQRCode {
+			return errors.New(entity.NonDisputableHistoryError)
+		}
+
+		// For qr code payments the sender is the one who has paid
+		if opHistory.SenderID != userID {
+			return errors.New("cannot dispute history which you haven't paid")
+		}
+
+		if time.Now().After(opHistory.SentAt.Add(DisputeOpeningPeriod)) {
+			return errors.New(entity.DisputeDeadlineError)
+		}
+
+		if _, err := tx.DisputeService.ActiveDispute(historyID); err == nil {
+			return errors.New(entity.DisputedHistoryError)
+		}
+
+		// Only the part of the payment that hasn't been refunded yet can be disputed
+		currency := opHistory.Amount.Currency
+		disputedAmount := opHistory.Amount
+		for _, childHistory := range tx.HistoryService.ChildHistories(historyID, entity.MethodRefund, entity.MethodReversal) {
+			disputedAmount = disputedAmount.Sub(childHistory.Amount)
+		}
+
+		if !disputedAmount.IsPositive() {
+			return errors.New(entity.FullyRefundedError)
+		}
+
+		// Holding as much of the disputed amount as the counterparty currently has
+		heldAmount := entity.NewMoney(0, currency)
+		counterpartyOPWallet, err := tx.WalletService.FindWallet(opHistory.ReceiverID, currency)
+		if err == nil && counterpartyOPWallet.Amount.IsPositive() {
+			heldAmount = disputedAmount
+			if counterpartyOPWallet.Amount.LessThan(disputedAmount) {
+				heldAmount = counterpartyOPWallet.Amount
+			}
+
+			err = tx.WalletService.SeizeWallet(counterpartyOPWallet, heldAmount)
+			if err != nil {
+				return err
+			}
+
+			err = tx.AddJournalEntry(entity.MethodPaymentQRCode, opHistory.Code,
+				WalletPosting(opHistory.ReceiverID, heldAmount.Neg()), DisputeHoldingPosting(heldAmount))
+			if err != nil {
+				return err
+			}
+		}
+
+		opDispute.HistoryID = opHistory.ID
+		opDispute.OpenerID = opHistory.SenderID
+		opDispute.CounterpartyID = opHistory.ReceiverID
+		opDispute.Amount = disputedAmount
+		opDispute.HeldAmount = heldAmount
+		opDispute.Reason = reason
+		opDispute.Deadline = time.Now().Add(DisputeEvidencePeriod)
+
+		return tx.DisputeService.AddDispute(opDispute)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opDispute, nil
+}
+
+// AddDisputeEvidence is a method that adds an evidence to an active dispute.
+// Only the parties of the dispute can submit evidence and only before the dispute deadline.
+func (onepay *OnePay) AddDisputeEvidence(userID string, disputeID int64, description, fileName string) (*entity.DisputeEvidence, error) {
+
+	opDispute, err := onepay.DisputeService.FindDispute(disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opDispute.OpenerID != userID && opDispute.CounterpartyID != userID {
+		return nil, errors.New("dispute not found")
+	}
+
+	if !opDispute.IsActive() {
+		return nil, errors.New(entity.DisputeClosedError)
+	}
+
+	if time.Now().After(opDispute.Deadline) {
+		return nil, errors.New(entity.DisputeDeadlineError)
+	}
+
+	evidence := new(entity.DisputeEvidence)
+	evidence.DisputeID = opDispute.ID
+	evidence.SubmitterID = userID
+	evidence.Description = description
+	evidence.FileName = fileName
+
+	err = onepay.DisputeService.AddEvidence(evidence)
+	if err != nil {
+		return nil, err
+	}
+
+	return evidence, nil
+}
+
+// ReviewDispute is a method that marks an open dispute as being under review by a staff member
+func (onepay *OnePay) ReviewDispute(disputeID int64) (*entity.Dispute, error) {
+
+	opDispute := new(entity.Dispute)
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+		lockedDispute, err := tx.DisputeService.LockDispute(disputeID)
+		if err != nil {
+			return err
+		}
+
+		if lockedDispute.Status != entity.DisputeStatusOpen {
+			return errors.New("dispute is not open")
+		}
+
+		lockedDispute.Status = entity.DisputeStatusUnderReview
+		opDispute = lockedDispute
+
+		return tx.DisputeService.UpdateDispute(lockedDispute)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opDispute, nil
+}
+
+// ResolveDispute is a method that enables staff members to close an active dispute.
+// A won dispute returns the disputed amount to the opener as a reversal of the disputed payment, taking the held money first and
+// the rest from the counterparty's wallet. If the counterparty no longer has the rest, only what could be recovered is returned
+// and the dispute is still closed. A lost dispute releases the held money back to the counterparty.
+func (onepay *OnePay) ResolveDispute(staffID string, disputeID int64, won bool, resolution string) (*entity.Dispute, error) {
+
+	opDispute := new(entity.Dispute)
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+		lockedDispute, err := tx.DisputeService.LockDispute(disputeID)
+		if err != nil {
+			return err
+		}
+
+		if !lockedDispute.IsActive() {
+			return errors.New(entity.DisputeClosedError)
+		}
+
+		opHistory, err := tx.HistoryService.LockHistory(int64(lockedDispute.HistoryID))
+		if err != nil {
+			return err
+		}
+
+		if won {
+			err = onepay.settleWonDispute(tx, lockedDispute, opHistory)
+			lockedDispute.Status = entity.DisputeStatusWon
+		} else {
+			err = onepay.releaseDisputeHold(tx, lockedDispute, opHistory)
+			lockedDispute.Status = entity.DisputeStatusLost
+		}
+
+		if err != nil {
+			return err
+		}
+
+		resolvedAt := time.Now()
+		lockedDispute.Resolution = resolution
+		lockedDispute.ResolvedBy = staffID
+		lockedDispute.ResolvedAt = &resolvedAt
+		opDispute = lockedDispute
+
+		return tx.DisputeService.UpdateDispute(lockedDispute)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opDispute, nil
+}
+
+// settleWonDispute is a method that returns the recoverable part of the disputed amount to the opener of a dispute and records it as a reversal
+func (onepay *OnePay) settleWonDispute(tx *Transaction, opDispute *entity.Dispute, opHistory *entity.UserHistory) error {
+
+	recoveredAmount := opDispute.HeldAmount
+	remainingAmount := opDispute.Amount.Sub(opDispute.HeldAmount)
+	var postings []*entity.Posting
+
+	if opDispute.HeldAmount.IsPositive() {
+		postings = append(postings, DisputeHoldingPosting(opDispute.HeldAmount.Neg()))
+	}
+
+	// The part that couldn't be held when the dispute was opened is taken from the counterparty now, as much of it as is available
+	counterpartyOPWallet, err := tx.WalletService.FindWallet(opDispute.CounterpartyID, opDispute.Amount.Currency)
+	if err == nil && remainingAmount.IsPositive() && counterpartyOPWallet.Amount.IsPositive() {
+		takenAmount := remainingAmount
+		if counterpartyOPWallet.Amount.LessThan(remainingAmount) {
+			takenAmount = counterpartyOPWallet.Amount
+		}
+
+		err = tx.WalletService.SeizeWallet(counterpartyOPWallet, takenAmount)
+		if err != nil {
+			return err
+		}
+
+		recoveredAmount = recoveredAmount.Add(takenAmount)
+		postings = append(postings, WalletPosting(opDispute.CounterpartyID, takenAmount.Neg()))
+	}
+
+	opDispute.RecoveredAmount = recoveredAmount
+	if !recoveredAmount.IsPositive() {
+		return nil
+	}
+
+	openerOPWallet, err := tx.ReceivingWallet(opDispute.OpenerID, opDispute.Amount.Currency)
+	if err != nil {
+		return errors.New(entity.ReceiverNotFoundError)
+	}
+
+	err = tx.WalletService.CreditWallet(openerOPWallet, recoveredAmount)
+	if err != nil {
+		return err
+	}
+
+	postings = append(postings, WalletPosting(opDispute.OpenerID, recoveredAmount))
+	err = tx.AddJournalEntry(entity.MethodReversal, opHistory.Code, postings...)
+	if err != nil {
+		return err
+	}
+
+	reversalHistory := new(entity.UserHistory)
+	reversalHistory.SenderID = opDispute.CounterpartyID
+	reversalHistory.ReceiverID = opDispute.OpenerID
+	reversalHistory.Method = entity.MethodReversal
+	reversalHistory.Code = opHistory.Code
+	reversalHistory.Amount = recoveredAmount
+	reversalHistory.Fee = entity.NewMoney(0, opDispute.Amount.Currency)
+	reversalHistory.ParentID = opHistory.ID
+	reversalHistory.SentAt = time.Now()
+	reversalHistory.ReceivedAt = time.Now()
+
+	return tx.HistoryService.AddHistory(reversalHistory)
+}
+
+// releaseDisputeHold is a method that returns the money held for a lost dispute to the counterparty
+func (onepay *OnePay) releaseDisputeHold(tx *Transaction, opDispute *entity.Dispute, opHistory *entity.UserHistory) error {
+
+	if !opDispute.HeldAmount.IsPositive() {
+		return nil
+	}
+
+	counterpartyOPWallet, err := tx.ReceivingWallet(opDispute.CounterpartyID, opDispute.HeldAmount.Currency)
+	if err != nil {
+		return err
+	}
+
+	err = tx.WalletService.CreditWallet(counterpartyOPWallet, opDispute.HeldAmount)
+	if err != nil {
+		return err
+	}
+
+	return tx.AddJournalEntry(entity.MethodPaymentQRCode, opHistory.Code,
+		DisputeHoldingPosting(opDispute.HeldAmount.Neg()), WalletPosting(opDispute.CounterpartyID, opDispute.HeldAmount))
+}
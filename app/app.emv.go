@@ -0,0 +1,130 @@
+package app
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+	"github.com/go-redis/redis"
+)
+
+// PaymentTokenEMVPayload is a method that returns the dynamic EMVCo qr code payload of a payment money token,
+// the payment token code is carried as its reference label
+func (onepay *OnePay) PaymentTokenEMVPayload(moneyToken *entity.MoneyToken) (string, error) {
+
+	if moneyToken.Method != entity.MethodPaymentQRCode {
+		return "", errors.New(entity.InvalidMethodError)
+	}
+
+	emvQRCode := onepay.merchantEMVQRCode(moneyToken.SenderID, moneyToken.Amount.Currency)
+	emvQRCode.PointOfInitiation = entity.EMVDynamicQRCode
+	emvQRCode.Amount = moneyToken.Amount
+	emvQRCode.ReferenceLabel = moneyToken.Code
+
+	return tools.EncodeEMVQRCode(emvQRCode)
+}
+
+// MerchantEMVPayload is a method that returns the static EMVCo qr code payload of a merchant,
+// the payer picks the amount that is paid in the provided currency
+func (onepay *OnePay) MerchantEMVPayload(userID, currency string) (string, error) {
+
+	_, err := onepay.WalletService.FindWallet(userID, entity.BaseCurrency)
+	if err != nil {
+		return "", errors.New(entity.ReceiverNotFoundError)
+	}
+
+	return tools.EncodeEMVQRCode(onepay.merchantEMVQRCode(userID, currency))
+}
+
+// MerchantQRCode is a method that renders the static EMVCo qr code of a merchant as a png or svg image
+func (onepay *OnePay) MerchantQRCode(userID, currency, format string, size int, level string, withLogo bool) ([]byte, error) {
+
+	payload, err := onepay.MerchantEMVPayload(userID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return RenderQRCode(payload, format, size, level, withLogo)
+}
+
+// merchantEMVQRCode is a method that returns a static EMVCo qr code that identifies the provided merchant
+func (onepay *OnePay) merchantEMVQRCode(userID, currency string) *entity.EMVQRCode {
+
+	// The merchant name is only shown to the payer, so a merchant that can't be found is still given a name
+	merchantName := "OnePay Merchant"
+	opUser, err := onepay.UserService.FindUser(userID)
+	if err == nil && strings.TrimSpace(opUser.FirstName+" "+opUser.LastName) != "" {
+		merchantName = strings.TrimSpace(opUser.FirstName + " " + opUser.LastName)
+	}
+
+	emvQRCode := new(entity.EMVQRCode)
+	emvQRCode.PointOfInitiation = entity.EMVStaticQRCode
+	emvQRCode.MerchantID = userID
+	emvQRCode.MerchantCategoryCode = entity.EMVMerchantCategoryCode
+	emvQRCode.Amount = entity.NewMoney(0, currency)
+	emvQRCode.CountryCode = entity.EMVCountryCode
+	emvQRCode.MerchantName = merchantName
+	emvQRCode.MerchantCity = entity.EMVMerchantCity
+
+	return emvQRCode
+}
+
+// emvPaymentTokenCode is a method that returns the payment token code of a dynamic EMVCo qr code.
+// The qr code is rejected if it doesn't match the stored payment token, static qr codes have no payment token.
+func (onepay *OnePay) emvPaymentTokenCode(payload string) (string, error) {
+
+	emvQRCode, err := tools.DecodeEMVQRCode(payload)
+	if err != nil {
+		return "", err
+	}
+
+	if !emvQRCode.IsDynamic() {
+		return "", errors.New(entity.StaticQRCodeAmountError)
+	}
+
+	moneyToken, err := onepay.MoneyTokenService.FindMoneyToken(emvQRCode.ReferenceLabel)
+	if err != nil {
+		return "", errors.New(entity.InvalidMoneyTokenError)
+	}
+
+	if moneyToken.Method != entity.MethodPaymentQRCode ||
+		moneyToken.SenderID != emvQRCode.MerchantID ||
+		moneyToken.Amount.Currency != emvQRCode.Amount.Currency ||
+		moneyToken.Amount.Cmp(emvQRCode.Amount) != 0 {
+		return "", errors.New(entity.QRPayloadMismatchError)
+	}
+
+	return moneyToken.Code, nil
+}
+
+// payMerchant is a method that pays the amount picked by the payer to the merchant of a static EMVCo qr code.
+// A payment token is created for the merchant and paid right away, it is cancelled if the payment fails.
+func (onepay *OnePay) payMerchant(receiverID string, emvQRCode *entity.EMVQRCode, amount entity.Money,
+	redisClient *redis.Client) error {
+
+	if amount.Minor <= 0 {
+		return errors.New(entity.StaticQRCodeAmountError)
+	}
+
+	if amount.Currency != emvQRCode.Amount.Currency {
+		return errors.New(entity.QRCodeCurrencyError)
+	}
+
+	if emvQRCode.MerchantID == receiverID {
+		return errors.New(entity.TransactionWSelfError)
+	}
+
+	moneyToken, err := onepay.CreatePaymentToken(emvQRCode.MerchantID, amount, entity.Invoice{})
+	if err != nil {
+		return err
+	}
+
+	err = onepay.payMoneyToken(receiverID, moneyToken.Code, redisClient)
+	if err != nil {
+		onepay.closeMoneyToken(moneyToken.Code, entity.MoneyTokenStatusCancelled)
+		return err
+	}
+
+	return nil
+}
@@ -59,11 +59,36 @@ func (onepay *OnePay) UserHistory(userID string, pagenation int64, viewBys ...st
 			searchColumns = append(searchColumns, "sender_id")
 			methods = append(methods, entity.MethodWithdrawn)
 
+		} else if viewBy == "refunded" {
+			if length == 1 {
+				orderBy = "received_at"
+			}
+			searchColumns = append(searchColumns, "sender_id", "receiver_id")
+			methods = append(methods, entity.MethodRefund, entity.MethodReversal)
+
+		} else if viewBy == "held" {
+			if length == 1 {
+				orderBy = "sent_at"
+			}
+			searchColumns = append(searchColumns, "sender_id", "receiver_id")
+			methods = append(methods, entity.MethodHoldAuthorization,
+				entity.MethodHoldCapture, entity.MethodHoldRelease)
+
+		} else if viewBy == "converted" {
+			if length == 1 {
+				orderBy = "sent_at"
+			}
+			searchColumns = append(searchColumns, "sender_id")
+			methods = append(methods, entity.MethodCurrencyConversion)
+
 		} else if viewBy == "all" && length == 1 {
 			searchColumns = append(searchColumns, "sender_id", "receiver_id")
 			methods = append(methods, entity.MethodTransactionOnePayID,
 				entity.MethodTransactionQRCode, entity.MethodPaymentQRCode,
-				entity.MethodWithdrawn, entity.MethodRecharged)
+				entity.MethodWithdrawn, entity.MethodRecharged,
+				entity.MethodRefund, entity.MethodReversal,
+				entity.MethodHoldAuthorization, entity.MethodHoldCapture, entity.MethodHoldRelease,
+				entity.MethodCurrencyConversion)
 		} else {
 			// If it is unknown view by
 			continue
@@ -2,30 +2,61 @@ package app
 
 import (
 	"github.com/Benyam-S/onepay/accountprovider"
+	"github.com/Benyam-S/onepay/dispute"
 	"github.com/Benyam-S/onepay/history"
+	"github.com/Benyam-S/onepay/hold"
+	"github.com/Benyam-S/onepay/job"
+	"github.com/Benyam-S/onepay/ledger"
+	"github.com/Benyam-S/onepay/limit"
 	"github.com/Benyam-S/onepay/linkedaccount"
 	"github.com/Benyam-S/onepay/logger"
 	"github.com/Benyam-S/onepay/moneytoken"
+	"github.com/Benyam-S/onepay/paymentrequest"
+	"github.com/Benyam-S/onepay/reconciliation"
+	"github.com/Benyam-S/onepay/scheduledtransfer"
+	"github.com/Benyam-S/onepay/settlement"
+	"github.com/Benyam-S/onepay/unitofwork"
+	"github.com/Benyam-S/onepay/user"
 	"github.com/Benyam-S/onepay/wallet"
 )
 
 // OnePay is a struct that defines all the methods and the functions that the onepay system can perform
 type OnePay struct {
-	WalletService          wallet.IService
-	HistoryService         history.IService
-	LinkedAccountService   linkedaccount.IService
-	MoneyTokenService      moneytoken.IService
-	AccountProviderService accountprovider.IService
-	Logger                 *logger.Logger
-	Channel                chan string
+	WalletService            wallet.IService
+	HistoryService           history.IService
+	LinkedAccountService     linkedaccount.IService
+	MoneyTokenService        moneytoken.IService
+	AccountProviderService   accountprovider.IService
+	LedgerService            ledger.IService
+	DisputeService           dispute.IService
+	ScheduledTransferService scheduledtransfer.IService
+	PaymentRequestService    paymentrequest.IService
+	HoldService              hold.IService
+	LimitService             limit.IService
+	UserService              user.IService
+	ReconciliationService    reconciliation.IService
+	SettlementService        settlement.IService
+	JobService               job.IService
+	UnitOfWorkManager        *unitofwork.Manager
+	Logger                   *logger.Logger
+	Channel                  chan string
 }
 
 // NewApp is a function that creates a new onepay app
 func NewApp(walletService wallet.IService, historyService history.IService,
 	linkedAccountService linkedaccount.IService, moneyTokenService moneytoken.IService,
-	accountProviderService accountprovider.IService, logger *logger.Logger, channel chan string) *OnePay {
+	accountProviderService accountprovider.IService, ledgerService ledger.IService,
+	disputeService dispute.IService, scheduledTransferService scheduledtransfer.IService,
+	paymentRequestService paymentrequest.IService, holdService hold.IService,
+	limitService limit.IService, userService user.IService, reconciliationService reconciliation.IService,
+	settlementService settlement.IService, jobService job.IService, unitOfWorkManager *unitofwork.Manager,
+	logger *logger.Logger, channel chan string) *OnePay {
 
 	return &OnePay{WalletService: walletService, HistoryService: historyService,
 		LinkedAccountService: linkedAccountService, MoneyTokenService: moneyTokenService,
-		AccountProviderService: accountProviderService, Logger: logger, Channel: channel}
+		AccountProviderService: accountProviderService, LedgerService: ledgerService,
+		DisputeService: disputeService, ScheduledTransferService: scheduledTransferService,
+		PaymentRequestService: paymentRequestService, HoldService: holdService, LimitService: limitService,
+		UserService: userService, ReconciliationService: reconciliationService,
+		SettlementService: settlementService, JobService: jobService, UnitOfWorkManager: unitOfWorkManager, Logger: logger, Channel: channel}
 }
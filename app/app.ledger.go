@@ -0,0 +1,254 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/logger"
+	"github.com/Benyam-S/onepay/tools"
+)
+
+// LedgerAuditInterval is a constant that defines how often the ledger and the wallets are audited
+const LedgerAuditInterval = time.Hour * 6
+
+// WalletPosting is a function that returns a posting made to the ledger account of a certain user's wallet
+// that holds the currency of the amount
+func WalletPosting(userID string, amount entity.Money) *entity.Posting {
+	return &entity.Posting{AccountID: tools.CurrencyLedgerAccountID(entity.LedgerAccountWallet, userID, amount.Currency),
+		Amount: amount}
+}
+
+// RevenuePosting is a function that returns a posting made to the ledger account of the system revenue wallet
+func RevenuePosting(amount entity.Money) *entity.Posting {
+	return WalletPosting(entity.RevenueWalletID, amount)
+}
+
+// ClearingPosting is a function that returns a posting made to a certain account provider's clearing ledger account
+func ClearingPosting(accountProviderID string, amount entity.Money) *entity.Posting {
+	return &entity.Posting{AccountID: tools.CurrencyLedgerAccountID(entity.LedgerAccountProviderClearing,
+		accountProviderID, amount.Currency), Amount: amount}
+}
+
+// MoneyTokenHoldingPosting is a function that returns a posting made to the ledger account
+// that holds the money of unclaimed money tokens
+func MoneyTokenHoldingPosting(amount entity.Money) *entity.Posting {
+	return &entity.Posting{AccountID: tools.CurrencyLedgerAccountID(entity.LedgerAccountMoneyTokenHolding, "", amount.Currency),
+		Amount: amount}
+}
+
+// ExchangePosting is a function that returns a posting made to the ledger account that takes the opposite side
+// of currency conversions. Each conversion credits the exchange account in one currency and debits it in another.
+func ExchangePosting(amount entity.Money) *entity.Posting {
+	return &entity.Posting{AccountID: tools.CurrencyLedgerAccountID(entity.LedgerAccountCurrencyExchange, "", amount.Currency),
+		Amount: amount}
+}
+
+// WalletHoldPosting is a function that returns a posting made to the ledger account that holds the money
+// merchants have reserved on a certain user's wallet
+func WalletHoldPosting(userID string, amount entity.Money) *entity.Posting {
+	return &entity.Posting{AccountID: tools.CurrencyLedgerAccountID(entity.LedgerAccountWalletHold, userID, amount.Currency),
+		Amount: amount}
+}
+
+// DisputeHoldingPosting is a function that returns a posting made to the ledger account
+// that holds the money of open disputes
+func DisputeHoldingPosting(amount entity.Money) *entity.Posting {
+	return &entity.Posting{AccountID: tools.CurrencyLedgerAccountID(entity.LedgerAccountDisputeHolding, "", amount.Currency),
+		Amount: amount}
+}
+
+// AddJournalEntry is a method that records a balanced set of postings in the ledger for the onepay app methods
+func (onepay *OnePay) AddJournalEntry(method, code string, postings ...*entity.Posting) error {
+
+	journalEntry := new(entity.JournalEntry)
+	journalEntry.Method = method
+	journalEntry.Code = code
+	journalEntry.Postings = postings
+	journalEntry.CreatedAt = time.Now()
+
+	/* +++++ +++++ checkpoint - journal entry +++++ ++++++ */
+	// tempJournalEntry is created because the .PostJournalEntry() method wil change some value's of the journalEntry object
+	tempJournalEntry := new(entity.JournalEntry)
+	tempJournalEntry.Method = method
+	tempJournalEntry.Code = code
+	tempJournalEntry.CreatedAt = journalEntry.CreatedAt
+	for _, posting := range postings {
+		tempJournalEntry.Postings = append(tempJournalEntry.Postings,
+			&entity.Posting{AccountID: posting.AccountID, Amount: posting.Amount})
+	}
+	logger.Must(onepay.Logger.LogJournalEntry(tempJournalEntry))
+	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
+
+	err := onepay.LedgerService.PostJournalEntry(journalEntry)
+	if err != nil {
+
+		// An unbalanced entry will never succeed so there is no need to keep it for reload
+		if err.Error() == entity.UnbalancedJournalEntryError {
+			logger.Must(onepay.Logger.RemoveJournalEntry(tempJournalEntry))
+			return err
+		}
+
+		return errors.New(entity.JournalEntryCheckpointError)
+	}
+
+	/* +++++ +++++ +++++ checkpoint end ++++ ++++ +++++ */
+	logger.Must(onepay.Logger.RemoveJournalEntry(tempJournalEntry))
+	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++ */
+
+	return nil
+}
+
+// OpenLedger is a method that opens a ledger account for every wallet that doesn't have one yet.
+// Wallets that existed before the ledger are opened with their current amount as an opening balance.
+func (onepay *OnePay) OpenLedger() error {
+
+	opWallets := onepay.WalletService.AllWallets()
+	for _, opWallet := range opWallets {
+
+		accountID := tools.CurrencyLedgerAccountID(entity.LedgerAccountWallet, opWallet.UserID, opWallet.Currency)
+		_, err := onepay.LedgerService.FindLedgerAccount(accountID)
+		if err == nil {
+			continue
+		}
+
+		if opWallet.Amount.IsZero() {
+			_, err = onepay.LedgerService.OpenLedgerAccount(accountID)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		openingBalanceID := tools.CurrencyLedgerAccountID(entity.LedgerAccountOpeningBalance, "", opWallet.Currency)
+		err = onepay.AddJournalEntry(entity.MethodOpeningBalance, "",
+			&entity.Posting{AccountID: openingBalanceID, Amount: opWallet.Amount.Neg()},
+			WalletPosting(opWallet.UserID, opWallet.Amount))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OpenRevenueWallet is a method that creates the system wallet that collects all the fees if it doesn't exist yet.
+// Fees that were collected in the fee income ledger account before the revenue wallet existed are moved to it.
+func (onepay *OnePay) OpenRevenueWallet() error {
+
+	_, err := onepay.WalletService.FindWallet(entity.RevenueWalletID, entity.BaseCurrency)
+	if err == nil {
+		return nil
+	}
+
+	feeIncomeID := tools.LedgerAccountID(entity.LedgerAccountFeeIncome, "")
+	feeIncome, err := onepay.LedgerService.AccountBalance(feeIncomeID)
+	if err != nil {
+		return err
+	}
+
+	return onepay.RunInTransaction(func(tx *Transaction) error {
+
+		revenueWallet := new(entity.UserWallet)
+		revenueWallet.UserID = entity.RevenueWalletID
+		revenueWallet.Amount = entity.NewMoney(feeIncome.Minor, feeIncome.Currency)
+
+		err := tx.WalletService.AddWallet(revenueWallet)
+		if err != nil {
+			return err
+		}
+
+		if !feeIncome.IsPositive() {
+			_, err = tx.LedgerService.OpenLedgerAccount(tools.LedgerAccountID(entity.LedgerAccountWallet, entity.RevenueWalletID))
+			return err
+		}
+
+		return tx.AddJournalEntry(entity.MethodOpeningBalance, "",
+			&entity.Posting{AccountID: feeIncomeID, Amount: feeIncome.Neg()}, RevenuePosting(feeIncome))
+	})
+}
+
+// RebuildWallet is a method that rebuilds a certain user's wallet amount of the provided currency from its ledger postings
+func (onepay *OnePay) RebuildWallet(userID, currency string) (*entity.UserWallet, error) {
+
+	opWallet, err := onepay.WalletService.FindWallet(userID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := onepay.LedgerService.AccountBalance(
+		tools.CurrencyLedgerAccountID(entity.LedgerAccountWallet, userID, opWallet.Currency))
+	if err != nil {
+		return nil, err
+	}
+
+	if opWallet.Amount.Cmp(balance) == 0 {
+		return opWallet, nil
+	}
+
+	opWallet.Amount = balance
+	err = onepay.WalletService.UpdateWallet(opWallet)
+	if err != nil {
+		return nil, err
+	}
+
+	return opWallet, nil
+}
+
+// AuditWallets is a method that compares every user wallet with its ledger account and
+// returns the wallets whose amount doesn't match the ledger balance
+func (onepay *OnePay) AuditWallets() []*entity.WalletAudit {
+
+	walletAudits := make([]*entity.WalletAudit, 0)
+	opWallets := onepay.WalletService.AllWallets()
+
+	for _, opWallet := range opWallets {
+
+		balance, err := onepay.LedgerService.AccountBalance(
+			tools.CurrencyLedgerAccountID(entity.LedgerAccountWallet, opWallet.UserID, opWallet.Currency))
+		if err != nil {
+			continue
+		}
+
+		if opWallet.Amount.Cmp(balance) == 0 {
+			continue
+		}
+
+		walletAudit := new(entity.WalletAudit)
+		walletAudit.UserID = opWallet.UserID
+		walletAudit.WalletAmount = opWallet.Amount
+		walletAudit.LedgerBalance = balance
+
+		walletAudits = append(walletAudits, walletAudit)
+	}
+
+	return walletAudits
+}
+
+// AuditLedger is a method that checks whether the ledger is balanced and compares every user wallet with its ledger account
+func (onepay *OnePay) AuditLedger() *entity.LedgerAudit {
+
+	ledgerAudit := new(entity.LedgerAudit)
+	ledgerAudit.Balanced = onepay.LedgerService.IsBalanced()
+	ledgerAudit.Wallets = onepay.AuditWallets()
+	ledgerAudit.AuditedAt = time.Now()
+
+	return ledgerAudit
+}
+
+// RunLedgerAudit is a method that audits the ledger as a scheduled job, the run fails if the ledger isn't balanced
+// or any wallet doesn't match its ledger account so the problem shows up in the job runs
+func (onepay *OnePay) RunLedgerAudit() error {
+
+	ledgerAudit := onepay.AuditLedger()
+	if !ledgerAudit.Balanced {
+		return errors.New(entity.UnbalancedLedgerError)
+	}
+
+	if len(ledgerAudit.Wallets) > 0 {
+		return fmt.Errorf("%d wallets don't match their ledger account", len(ledgerAudit.Wallets))
+	}
+
+	return nil
+}
@@ -0,0 +1,355 @@
+package app
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+)
+
+// UserLimitProfile is a method that returns the limit profile a certain user is assigned to.
+// The profile a staff member has assigned to the user overrides the profile of the user's verification tier.
+func (onepay *OnePay) UserLimitProfile(userID string) *entity.LimitProfile {
+
+	userLimit, err := onepay.LimitService.FindUserLimit(userID)
+	if err != nil {
+		return DefaultLimitProfile()
+	}
+
+	profiles := LimitProfiles()
+	if userLimit.Profile != "" {
+		for _, profile := range profiles {
+			if profile.Name == userLimit.Profile {
+				return profile
+			}
+		}
+	}
+
+	for _, profile := range profiles {
+		if profile.Tier == userLimit.Tier {
+			return profile
+		}
+	}
+
+	return DefaultLimitProfile()
+}
+
+// AssignUserLimit is a method that enables a staff member to set the verification tier of a user
+// and optionally override the limit profile of the tier, an empty profile removes the override
+func (onepay *OnePay) AssignUserLimit(staffID, userID, tier, profileName string) (*entity.UserLimit, error) {
+
+	if _, err := onepay.WalletService.FindWallet(userID, entity.BaseCurrency); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if profileName != "" {
+		found := false
+		for _, profile := range LimitProfiles() {
+			if profile.Name == profileName {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, errors.New(entity.LimitProfileNotFoundError)
+		}
+	}
+
+	userLimit := new(entity.UserLimit)
+	userLimit.UserID = userID
+	userLimit.Tier = tier
+	userLimit.Profile = profileName
+	userLimit.StaffID = staffID
+
+	err := onepay.LimitService.UpdateUserLimit(userLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return userLimit, nil
+}
+
+// limitPeriods is a variable that holds the periods of the periodic limits
+var limitPeriods = []string{entity.LimitDaily, entity.LimitWeekly, entity.LimitMonthly}
+
+// limitMethods is a variable that holds the methods whose usage is counted separately for each kind of limit
+var limitMethods = map[string][]string{
+	entity.LimitSend: {entity.MethodTransactionOnePayID, entity.MethodTransactionQRCode,
+		entity.MethodPaymentQRCode, entity.MethodHoldAuthorization},
+	entity.LimitReceive: {entity.MethodTransactionOnePayID, entity.MethodTransactionQRCode,
+		entity.MethodPaymentQRCode, entity.MethodHoldAuthorization},
+	entity.LimitWithdraw: {entity.MethodWithdrawn},
+}
+
+// reserveLimitScript is a script that checks an amount against the periodic limits of a kind and adds it to the usage
+// of a method in one atomic step. The keys hold the usage of every method of the kind grouped by period, the arguments
+// are the amount, the number of methods, the position of the method in a group and then the limit and the expiry of
+// each period. It returns the position of the exceeded period or zero once the amount has been added.
+var reserveLimitScript = redis.NewScript(`
+local amount = tonumber(ARGV[1])
+local methods = tonumber(ARGV[2])
+local position = tonumber(ARGV[3])
+local periods = #KEYS / methods
+
+for period = 0, periods - 1 do
+	local limit = tonumber(ARGV[4 + period * 2])
+	if limit > 0 then
+		local used = 0
+		for method = 1, methods do
+			used = used + (tonumber(redis.call('GET', KEYS[period * methods + method])) or 0)
+		end
+
+		if used + amount > limit then
+			return period + 1
+		end
+	end
+end
+
+for period = 0, periods - 1 do
+	local key = KEYS[period * methods + position]
+	redis.call('INCRBY', key, ARGV[1])
+	redis.call('EXPIREAT', key, ARGV[5 + period * 2])
+end
+
+return 0
+`)
+
+// releaseLimitScript is a script that gives back a reserved amount to the usage of a method for every period.
+// A key that has already expired belongs to a period that is over, so it is left alone instead of being recreated.
+var releaseLimitScript = redis.NewScript(`
+for _, key in ipairs(KEYS) do
+	if redis.call('EXISTS', key) == 1 then
+		redis.call('DECRBY', key, ARGV[1])
+	end
+end
+
+return 0
+`)
+
+// UserLocation is a method that returns the location of the time zone a certain user has set in its preference
+func (onepay *OnePay) UserLocation(userID string) *time.Location {
+
+	timeZone := entity.DefaultTimeZone
+	userPreference, err := onepay.UserService.FindUserPreference(userID)
+	if err == nil && userPreference.TimeZone != "" {
+		timeZone = userPreference.TimeZone
+	}
+
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// LimitPeriod is a function that returns the start and the end of the calendar period the provided time falls in.
+// The periods start at midnight in the location of the provided time and weeks start on monday.
+func LimitPeriod(period string, now time.Time) (time.Time, time.Time) {
+
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+
+	switch period {
+	case entity.LimitWeekly:
+		start := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7)
+
+	case entity.LimitMonthly:
+		start := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+
+	return today, today.AddDate(0, 0, 1)
+}
+
+// ReserveLimit is a method that checks the provided amount against the limits of the provided kind and adds it to
+// the user's usage of the method in one atomic step, so concurrent transactions can't slip past a limit together.
+// Amounts of every currency are counted in their base currency value and receiving money is also checked against
+// the maximum wallet balance. The returned function gives the amount back and has to be called if the transaction fails.
+func (onepay *OnePay) ReserveLimit(userID, kind, method string, amount entity.Money,
+	redisClient *redis.Client) (func(), error) {
+
+	reservation, err := onepay.reserveLimit(userID, kind, method, amount, redisClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		ReleaseLimit(reservation, reservation.Amount, redisClient)
+	}, nil
+}
+
+// ReleaseLimit is a function that gives back the provided part of a reservation, in base currency, to the usage it has been added to.
+// The usage of a period that is already over isn't changed.
+func ReleaseLimit(reservation *entity.LimitReservation, amount entity.Money, redisClient *redis.Client) {
+
+	if len(reservation.Keys) == 0 || !amount.IsPositive() {
+		return
+	}
+
+	releaseLimitScript.Run(redisClient, reservation.Keys, amount.Minor)
+}
+
+// reserveLimit is a method that reserves the provided amount against the limits of the provided kind
+// and returns the reservation so it can be given back later
+func (onepay *OnePay) reserveLimit(userID, kind, method string, amount entity.Money,
+	redisClient *redis.Client) (*entity.LimitReservation, error) {
+
+	baseAmount, err := ToBaseCurrency(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := onepay.UserLimitProfile(userID)
+	limits := profile.Limits(kind)
+
+	if aboveLimit(baseAmount, limits.Transaction) {
+		return nil, limitError(kind, "")
+	}
+
+	if kind == entity.LimitReceive {
+		err = onepay.checkMaxBalance(userID, profile, baseAmount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	methods, position := kindMethods(kind, method)
+	now := time.Now().In(onepay.UserLocation(userID))
+
+	var keys, reservedKeys []string
+	args := []interface{}{baseAmount.Minor, len(methods), position}
+	for _, period := range limitPeriods {
+		start, end := LimitPeriod(period, now)
+		for _, kindMethod := range methods {
+			keys = append(keys, limitUsageKey(userID, kind, kindMethod, period, start))
+		}
+
+		reservedKeys = append(reservedKeys, limitUsageKey(userID, kind, method, period, start))
+		args = append(args, limits.Period(period).Minor, end.Unix())
+	}
+
+	exceeded, err := reserveLimitScript.Run(redisClient, keys, args...).Int()
+	if err != nil {
+		return nil, err
+	}
+
+	if exceeded > 0 {
+		return nil, limitError(kind, limitPeriods[exceeded-1])
+	}
+
+	return &entity.LimitReservation{Keys: reservedKeys, Amount: baseAmount}, nil
+}
+
+// reserveTransferLimits is a method that reserves the send limit of the sender and the receive limit of the receiver
+// of a transfer, the returned function gives back both of the reserved amounts
+func (onepay *OnePay) reserveTransferLimits(senderID, receiverID, method string, amount entity.Money,
+	redisClient *redis.Client) (func(), error) {
+
+	releaseSend, err := onepay.ReserveLimit(senderID, entity.LimitSend, method, amount, redisClient)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseReceive, err := onepay.ReserveLimit(receiverID, entity.LimitReceive, method, amount, redisClient)
+	if err != nil {
+		releaseSend()
+		return nil, err
+	}
+
+	return func() {
+		releaseSend()
+		releaseReceive()
+	}, nil
+}
+
+// reserveHoldLimits is a method that reserves the send limit of the user and the receive limit of the merchant of a hold.
+// The reservations of both are returned as one since the same base currency amount is added to each of them.
+func (onepay *OnePay) reserveHoldLimits(userID, merchantID string, amount entity.Money,
+	redisClient *redis.Client) (*entity.LimitReservation, error) {
+
+	sendReservation, err := onepay.reserveLimit(userID, entity.LimitSend, entity.MethodHoldAuthorization, amount, redisClient)
+	if err != nil {
+		return nil, err
+	}
+
+	receiveReservation, err := onepay.reserveLimit(merchantID, entity.LimitReceive, entity.MethodHoldAuthorization,
+		amount, redisClient)
+	if err != nil {
+		ReleaseLimit(sendReservation, sendReservation.Amount, redisClient)
+		return nil, err
+	}
+
+	keys := append(append([]string{}, sendReservation.Keys...), receiveReservation.Keys...)
+	return &entity.LimitReservation{Keys: keys, Amount: sendReservation.Amount}, nil
+}
+
+// RemainingLimits is a method that returns how much of every periodic limit a certain user has used in the current
+// period, broken down by method, and how much is left before the limit is reached
+func (onepay *OnePay) RemainingLimits(userID string, redisClient *redis.Client) []*entity.RemainingLimit {
+
+	profile := onepay.UserLimitProfile(userID)
+	now := time.Now().In(onepay.UserLocation(userID))
+	remainingLimits := make([]*entity.RemainingLimit, 0)
+
+	for _, kind := range []string{entity.LimitSend, entity.LimitReceive, entity.LimitWithdraw} {
+		for _, period := range limitPeriods {
+			start, end := LimitPeriod(period, now)
+
+			remainingLimit := new(entity.RemainingLimit)
+			remainingLimit.Kind = kind
+			remainingLimit.Period = period
+			remainingLimit.Limit = entity.NewMoney(profile.Limits(kind).Period(period).Minor, entity.BaseCurrency)
+			remainingLimit.Used = entity.NewMoney(0, entity.BaseCurrency)
+			remainingLimit.ResetsAt = end
+
+			for _, method := range limitMethods[kind] {
+				usageString, _ := tools.GetValue(redisClient, limitUsageKey(userID, kind, method, period, start))
+				usageMinor, _ := strconv.ParseInt(usageString, 10, 64)
+				usage := entity.NewMoney(usageMinor, entity.BaseCurrency)
+
+				remainingLimit.Methods = append(remainingLimit.Methods, &entity.MethodUsage{Method: method, Used: usage})
+				remainingLimit.Used = remainingLimit.Used.Add(usage)
+			}
+
+			remainingLimit.Remaining = entity.NewMoney(0, entity.BaseCurrency)
+			if !remainingLimit.Limit.IsPositive() {
+				remainingLimit.Unlimited = true
+			} else if remainingLimit.Used.LessThan(remainingLimit.Limit) {
+				remainingLimit.Remaining = remainingLimit.Limit.Sub(remainingLimit.Used)
+			}
+
+			remainingLimits = append(remainingLimits, remainingLimit)
+		}
+	}
+
+	return remainingLimits
+}
+
+// kindMethods is a function that returns the methods whose usage is counted for the provided kind of limit
+// along with the position of the provided method, a method that isn't listed is counted on its own
+func kindMethods(kind, method string) ([]string, int) {
+
+	methods := limitMethods[kind]
+	for index, kindMethod := range methods {
+		if kindMethod == method {
+			return methods, index + 1
+		}
+	}
+
+	methods = append(append([]string{}, methods...), method)
+	return methods, len(methods)
+}
+
+// limitUsageKey is a function that returns the redis key that holds the usage of a method for a certain period.
+// The user id is used as a hash tag so all the keys of a user are kept together when redis is clustered.
+func limitUsageKey(userID, kind, method, period string, start time.Time) string {
+	method = strings.ToLower(strings.Replace(method, " ", "_", -1))
+	return "limit_usage:{" + userID + "}:" + kind + ":" + method + ":" + period + ":" + start.Format("2006-01-02")
+}
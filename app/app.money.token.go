@@ -2,12 +2,44 @@ package app
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/Benyam-S/onepay/entity"
-	"github.com/Benyam-S/onepay/logger"
 )
 
+// DefaultMoneyTokenExpiry is a constant that defines the expiry of money tokens whose sender hasn't picked one
+const DefaultMoneyTokenExpiry = time.Hour * 48
+
+// DefaultMinMoneyTokenExpiry is a constant that defines the shortest money token expiry when it hasn't been configured
+const DefaultMinMoneyTokenExpiry = time.Hour
+
+// DefaultMaxMoneyTokenExpiry is a constant that defines the longest money token expiry when it hasn't been configured
+const DefaultMaxMoneyTokenExpiry = time.Hour * 24 * 7
+
+// MoneyTokenExpiry is a function that checks the expiry picked by a sender against the configured bounds.
+// If no expiry has been picked the default expiry, kept within the bounds, is returned.
+func MoneyTokenExpiry(expiry time.Duration) (time.Duration, error) {
+
+	minExpiry, maxExpiry := MoneyTokenExpiryBounds()
+
+	if expiry == 0 {
+		switch {
+		case DefaultMoneyTokenExpiry < minExpiry:
+			return minExpiry, nil
+		case DefaultMoneyTokenExpiry > maxExpiry:
+			return maxExpiry, nil
+		}
+		return DefaultMoneyTokenExpiry, nil
+	}
+
+	if expiry < minExpiry || expiry > maxExpiry {
+		return 0, errors.New(entity.InvalidExpiryError)
+	}
+
+	return expiry, nil
+}
+
 // ReclaimMoneyToken is a method that enables user to reclaim token that has been generated by the user
 func (onepay *OnePay) ReclaimMoneyToken(code, userID string) error {
 
@@ -24,39 +56,69 @@ func (onepay *OnePay) ReclaimMoneyToken(code, userID string) error {
 		return errors.New("cannot reclaim token, invalid method")
 	}
 
-	opWallet, err := onepay.WalletService.FindWallet(userID)
-	if err != nil {
-		return err
-	}
-
-	opWallet.Amount += moneyToken.Amount + GetTransactionFee(moneyToken.Amount)
-	_, err = onepay.MoneyTokenService.DeleteMoneyToken(code)
-	if err != nil {
-		return err
-	}
-
-	/* ++++ ++++ ++++ ++++ ++++ ++ checkpoint - wallet ++ ++++ ++++ ++++ ++++ ++++ */
-	tempOPWallet := new(entity.UserWallet)
-	tempOPWallet.UserID = opWallet.UserID
-	tempOPWallet.Amount = moneyToken.Amount + GetTransactionFee(moneyToken.Amount)
-	logger.Must(onepay.Logger.LogWallet(tempOPWallet))
-	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
-
-	err = onepay.WalletService.UpdateWallet(opWallet)
-	if err != nil {
-		return errors.New(entity.WalletCheckpointError)
-	}
-
-	/* +++++ +++++ +++++ checkpoint end +++++ +++++ +++++ */
-	logger.Must(onepay.Logger.RemoveWallet(tempOPWallet))
-	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
+	return onepay.closeMoneyToken(code, entity.MoneyTokenStatusReclaimed)
+}
 
-	return nil
+// closeMoneyToken is a method that closes an active money token with the provided status,
+// returning the money and fee of the claims that haven't been made to the sender
+func (onepay *OnePay) closeMoneyToken(code, status string) error {
+
+	return onepay.RunInTransaction(func(tx *Transaction) error {
+
+		// Locking the money token so that it can't be claimed while it is being closed
+		moneyToken, err := tx.MoneyTokenService.LockMoneyToken(code)
+		if err != nil {
+			return err
+		}
+
+		if !moneyToken.IsActive() {
+			return errors.New(entity.InactiveMoneyTokenError)
+		}
+
+		lockedAmount := moneyToken.Remaining.Add(moneyToken.RemainingFee)
+
+		closedAt := time.Now()
+		moneyToken.Status = status
+		moneyToken.Remaining = entity.NewMoney(0, moneyToken.Amount.Currency)
+		moneyToken.RemainingFee = entity.NewMoney(0, moneyToken.Amount.Currency)
+		switch status {
+		case entity.MoneyTokenStatusReclaimed:
+			moneyToken.ReclaimedAt = &closedAt
+		case entity.MoneyTokenStatusExpired:
+			moneyToken.ExpiredAt = &closedAt
+		case entity.MoneyTokenStatusCancelled:
+			moneyToken.CancelledAt = &closedAt
+		}
+
+		err = tx.MoneyTokenService.UpdateMoneyToken(moneyToken)
+		if err != nil {
+			return err
+		}
+
+		// Payment money tokens don't lock any money
+		if moneyToken.Method != entity.MethodTransactionQRCode {
+			return nil
+		}
+
+		opWallet, err := tx.ReceivingWallet(moneyToken.SenderID, moneyToken.Amount.Currency)
+		if err != nil {
+			return err
+		}
+
+		err = tx.WalletService.CreditWallet(opWallet, lockedAmount)
+		if err != nil {
+			return err
+		}
+
+		// Returning the money locked in the money token back to the sender in the ledger
+		return tx.AddJournalEntry(entity.MethodTransactionQRCode, moneyToken.Code,
+			MoneyTokenHoldingPosting(lockedAmount.Neg()), WalletPosting(moneyToken.SenderID, lockedAmount))
+	})
 }
 
 // RefreshMoneyToken is a method that enables user to refersh money token.
-// In another word increase the life cycle of the money token
-func (onepay *OnePay) RefreshMoneyToken(code, userID string) error {
+// In another word increase the life cycle of the money token by the provided expiry
+func (onepay *OnePay) RefreshMoneyToken(code, userID string, expiry time.Duration) error {
 
 	moneyToken, err := onepay.MoneyTokenService.FindMoneyToken(code)
 	if err != nil {
@@ -67,9 +129,17 @@ func (onepay *OnePay) RefreshMoneyToken(code, userID string) error {
 		return errors.New("cannot refersh token which is not yours")
 	}
 
-	moneyToken.ExpirationDate = time.Now().Add(time.Hour * 48)
+	if !moneyToken.IsActive() {
+		return errors.New(entity.InactiveMoneyTokenError)
+	}
+
+	expiry, err = MoneyTokenExpiry(expiry)
+	if err != nil {
+		return err
+	}
 
-	err = onepay.MoneyTokenService.UpdateMoneyToken(moneyToken)
+	// Only the expiration date is changed so a claim made in the mean time isn't overwritten
+	err = onepay.MoneyTokenService.UpdateMoneyTokenSingleValue(code, "expiration_date", time.Now().Add(expiry))
 	if err != nil {
 		return err
 	}
@@ -95,20 +165,27 @@ func (onepay *OnePay) RemoveMoneyToken(code, userID string) error {
 		return errors.New("cannot remove non payment qr code")
 	}
 
-	_, err = onepay.MoneyTokenService.DeleteMoneyToken(code)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return onepay.closeMoneyToken(code, entity.MoneyTokenStatusCancelled)
 }
 
-// ReclaimExpiredMoneyTokens is a method that returns all the expired money tokens to their owner's
-func (onepay *OnePay) ReclaimExpiredMoneyTokens() {
+// ReclaimExpiredMoneyTokens is a method that returns all the expired money tokens to their owner's,
+// including whatever is left of money tokens that have only been partly claimed.
+// The senders are notified through the money token change, an error is returned if any of the money tokens couldn't be reclaimed.
+func (onepay *OnePay) ReclaimExpiredMoneyTokens() error {
 
 	moneyTokens := onepay.MoneyTokenService.ExpiredMoneyTokens()
 
+	failed := 0
 	for _, moneyToken := range moneyTokens {
-		onepay.ReclaimMoneyToken(moneyToken.Code, moneyToken.SenderID)
+		err := onepay.closeMoneyToken(moneyToken.Code, entity.MoneyTokenStatusExpired)
+		if err != nil {
+			failed++
+		}
 	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to reclaim %d of %d expired money tokens", failed, len(moneyTokens))
+	}
+
+	return nil
 }
@@ -2,29 +2,50 @@ package app
 
 import (
 	"errors"
-	"os"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Benyam-S/onepay/entity"
-	"github.com/Benyam-S/onepay/logger"
+	"github.com/Benyam-S/onepay/tools"
 	"github.com/go-redis/redis"
 )
 
-// CreatePaymentToken is a method that generate a payment token
-func (onepay *OnePay) CreatePaymentToken(userID string, amount float64) (*entity.MoneyToken, error) {
+// MaxOrderIDLength is a constant that defines the longest order id an invoice can have
+const MaxOrderIDLength = 64
+
+// MaxDescriptionLength is a constant that defines the longest description an invoice can have
+const MaxDescriptionLength = 255
+
+// MaxCallbackRefLength is a constant that defines the longest callback reference an invoice can have
+const MaxCallbackRefLength = 255
+
+// MaxLineItems is a constant that defines the largest number of line items an invoice can have
+const MaxLineItems = 50
+
+// CreatePaymentToken is a method that generate a payment token.
+// The invoice holds the merchant order details of the payment token, all of them are optional.
+func (onepay *OnePay) CreatePaymentToken(userID string, amount entity.Money, invoice entity.Invoice) (*entity.MoneyToken, error) {
 
 	if !AboveTransactionBaseLimit(amount) {
 		return nil, errors.New(entity.TransactionBaseLimitError)
 	}
 
-	// Checking if the created token can be claimed since if the amount exceeds the daily transaction base limit no one can claim it
-	dailyTransactionLimit, _ := strconv.ParseFloat(os.Getenv(entity.DailyTransactionLimit), 64)
-	if amount > dailyTransactionLimit {
-		return nil, errors.New(entity.DailyTransactionLimitError)
+	err := ValidateInvoice(invoice, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	baseAmount, err := ToBaseCurrency(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checking if the created token can be claimed since if the amount exceeds the user's receive limit the payment will always fail
+	if aboveLimit(baseAmount, onepay.UserLimitProfile(userID).Limits(entity.LimitReceive).Transaction) {
+		return nil, errors.New(entity.ReceiveLimitError)
 	}
 
-	opWallet, err := onepay.WalletService.FindWallet(userID)
+	opWallet, err := onepay.WalletService.FindWallet(userID, entity.BaseCurrency)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +55,13 @@ func (onepay *OnePay) CreatePaymentToken(userID string, amount float64) (*entity
 	moneyToken.Method = entity.MethodPaymentQRCode
 	moneyToken.SenderID = opWallet.UserID
 	moneyToken.SentAt = time.Now()
+	moneyToken.Invoice = invoice
+
+	expiry, err := MoneyTokenExpiry(0)
+	if err != nil {
+		return nil, err
+	}
+	moneyToken.ExpirationDate = moneyToken.SentAt.Add(expiry)
 
 	err = onepay.MoneyTokenService.AddMoneyToken(moneyToken)
 	if err != nil {
@@ -43,12 +71,76 @@ func (onepay *OnePay) CreatePaymentToken(userID string, amount float64) (*entity
 	return moneyToken, nil
 }
 
+// ValidateInvoice is a function that validates the invoice of a payment token.
+// If the invoice has line items they should be in the currency of the amount and add up to it.
+func ValidateInvoice(invoice entity.Invoice, amount entity.Money) error {
+
+	if len(invoice.OrderID) > MaxOrderIDLength {
+		return errors.New(entity.InvalidOrderIDError)
+	}
+
+	if len(invoice.Description) > MaxDescriptionLength {
+		return errors.New(entity.InvalidDescriptionError)
+	}
+
+	if len(invoice.CallbackRef) > MaxCallbackRefLength {
+		return errors.New(entity.InvalidCallbackRefError)
+	}
+
+	if len(invoice.LineItems) == 0 {
+		return nil
+	}
+
+	if len(invoice.LineItems) > MaxLineItems {
+		return errors.New(entity.InvalidLineItemsError)
+	}
+
+	total := entity.NewMoney(0, amount.Currency)
+	for _, lineItem := range invoice.LineItems {
+		if strings.TrimSpace(lineItem.Name) == "" || lineItem.Quantity <= 0 ||
+			lineItem.UnitPrice.IsNegative() || lineItem.UnitPrice.Currency != amount.Currency {
+			return errors.New(entity.InvalidLineItemsError)
+		}
+		total = total.Add(lineItem.Total())
+	}
+
+	if total.Cmp(amount) != 0 {
+		return errors.New(entity.LineItemsTotalError)
+	}
+
+	return nil
+}
+
 // PayViaQRCode is a method that enables user's to pay to another user via qr code.
 // InPayViaQRCode the receiverID is referred to as the one who is paying the money, in the other word receiving  the qr code.
-// Also transaction fee while be deducted from the senderID since the sender initiated the request
-func (onepay *OnePay) PayViaQRCode(receiverID string, code string, redisClient *redis.Client) error {
+// Also transaction fee while be deducted from the senderID since the sender initiated the request.
+// The payload can be a OnePay payment token code or payload, or an EMVCo merchant presented qr code.
+// The amount is only used for static EMVCo qr codes, which don't carry an amount of their own.
+func (onepay *OnePay) PayViaQRCode(receiverID string, payload string, amount entity.Money, redisClient *redis.Client) error {
+
+	if tools.IsEMVQRPayload(payload) {
+		emvQRCode, err := tools.DecodeEMVQRCode(payload)
+		if err != nil {
+			return err
+		}
 
-	receiverOPWallet, err := onepay.WalletService.FindWallet(receiverID)
+		if !emvQRCode.IsDynamic() {
+			return onepay.payMerchant(receiverID, emvQRCode, amount, redisClient)
+		}
+	}
+
+	code, err := onepay.ScannedMoneyTokenCode(payload)
+	if err != nil {
+		return err
+	}
+
+	return onepay.payMoneyToken(receiverID, code, redisClient)
+}
+
+// payMoneyToken is a method that pays the payment money token with the provided code
+func (onepay *OnePay) payMoneyToken(receiverID string, code string, redisClient *redis.Client) error {
+
+	_, err := onepay.WalletService.FindWallet(receiverID, entity.BaseCurrency)
 	if err != nil {
 		return errors.New(entity.ReceiverNotFoundError)
 	}
@@ -58,6 +150,10 @@ func (onepay *OnePay) PayViaQRCode(receiverID string, code string, redisClient *
 		return errors.New(entity.InvalidMoneyTokenError)
 	}
 
+	if !moneyToken.IsActive() {
+		return errors.New(entity.InactiveMoneyTokenError)
+	}
+
 	if !moneyToken.ExpirationDate.After(time.Now()) {
 		return errors.New(entity.ExpiredMoneyTokenError)
 	}
@@ -66,10 +162,6 @@ func (onepay *OnePay) PayViaQRCode(receiverID string, code string, redisClient *
 		return errors.New(entity.TransactionBaseLimitError)
 	}
 
-	if AboveDailyTransactionLimit(receiverID, moneyToken.Amount, redisClient) {
-		return errors.New(entity.DailyTransactionLimitError)
-	}
-
 	if moneyToken.SenderID == receiverID {
 		return errors.New(entity.TransactionWSelfError)
 	}
@@ -78,70 +170,102 @@ func (onepay *OnePay) PayViaQRCode(receiverID string, code string, redisClient *
 		return errors.New(entity.InvalidMethodError)
 	}
 
-	senderOPWallet, err := onepay.WalletService.FindWallet(moneyToken.SenderID)
+	_, err = onepay.WalletService.FindWallet(moneyToken.SenderID, entity.BaseCurrency)
 	if err != nil {
 		return errors.New(entity.SenderNotFoundError)
 	}
 
-	if receiverOPWallet.Amount < moneyToken.Amount {
-		return errors.New(entity.InsufficientBalanceError)
-	}
-
-	transactionFee := GetTransactionFee(moneyToken.Amount)
-	receiverOPWallet.Amount = receiverOPWallet.Amount - moneyToken.Amount
-	senderOPWallet.Amount = senderOPWallet.Amount + (moneyToken.Amount - transactionFee)
-
-	// Delete the money token first
-	_, err = onepay.MoneyTokenService.DeleteMoneyToken(moneyToken.Code)
+	transactionFee, err := GetTransactionFee(entity.MethodPaymentQRCode, moneyToken.Amount)
 	if err != nil {
 		return err
 	}
 
-	err = onepay.WalletService.UpdateWallet(receiverOPWallet)
+	// The payer sends the money to the one who created the payment token
+	releaseLimits, err := onepay.reserveTransferLimits(receiverID, moneyToken.SenderID, entity.MethodPaymentQRCode,
+		moneyToken.Amount, redisClient)
 	if err != nil {
 		return err
 	}
 
-	/* +++++ +++++ +++++ checkpoint - wallet ++++ ++++ +++++ */
-	tempOPWallet := new(entity.UserWallet)
-	tempOPWallet.UserID = senderOPWallet.UserID
-	tempOPWallet.Amount = moneyToken.Amount - transactionFee
-	logger.Must(onepay.Logger.LogWallet(tempOPWallet))
-	/* +++++ +++++ +++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
+	err = onepay.RunInTransaction(func(tx *Transaction) error {
 
-	err = onepay.WalletService.UpdateWallet(senderOPWallet)
-	if err != nil {
+		// The wallets are read inside the transaction so a concurrent change can be detected.
+		// The payer has to hold the currency of the payment token, if it doesn't it has no balance to pay with.
+		receiverOPWallet, err := tx.WalletService.FindWallet(receiverID, moneyToken.Amount.Currency)
+		if err != nil {
+			return errors.New(entity.InsufficientBalanceError)
+		}
 
-		/* ++++++++++++++++++++++++++++++ Undo ++++++++++++++++++++++++++++++ */
-		receiverOPWallet.Amount = receiverOPWallet.Amount + moneyToken.Amount
-		innerErr := onepay.WalletService.UpdateWallet(receiverOPWallet)
-		/* ++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++ */
+		senderOPWallet, err := tx.ReceivingWallet(moneyToken.SenderID, moneyToken.Amount.Currency)
+		if err != nil {
+			return errors.New(entity.SenderNotFoundError)
+		}
 
-		if innerErr != nil {
+		if receiverOPWallet.Amount.LessThan(moneyToken.Amount) {
+			return errors.New(entity.InsufficientBalanceError)
+		}
 
-			// Adding history for the potential reload
-			onepay.AddUserHistory(receiverID, moneyToken.SenderID, entity.MethodPaymentQRCode, moneyToken.Code,
-				moneyToken.Amount, moneyToken.SentAt, time.Now())
+		// The payment money token is locked so that it can only be paid once
+		lockedMoneyToken, err := tx.MoneyTokenService.LockMoneyToken(moneyToken.Code)
+		if err != nil {
+			return errors.New(entity.InvalidMoneyTokenError)
+		}
 
-			return errors.New(entity.WalletCheckpointError)
+		if !lockedMoneyToken.IsActive() {
+			return errors.New(entity.InactiveMoneyTokenError)
 		}
 
-		/* +++++ +++++ +++++ checkpoint end +++++ +++++ +++++ */
-		logger.Must(onepay.Logger.RemoveWallet(tempOPWallet))
-		/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
+		paidAt := time.Now()
+		lockedMoneyToken.Status = entity.MoneyTokenStatusClaimed
+		lockedMoneyToken.ClaimantID = receiverID
+		lockedMoneyToken.ClaimedAt = &paidAt
 
-		return err
-	}
+		err = tx.MoneyTokenService.UpdateMoneyToken(lockedMoneyToken)
+		if err != nil {
+			return err
+		}
 
-	/* +++++ +++++ +++++ checkpoint end +++++ +++++ +++++ */
-	logger.Must(onepay.Logger.RemoveWallet(tempOPWallet))
-	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
+		err = tx.WalletService.DebitWallet(receiverOPWallet, moneyToken.Amount)
+		if err != nil {
+			return err
+		}
 
-	// Just updating the users daily transaction limit
-	AddToDailyTransaction(receiverID, moneyToken.Amount, redisClient)
+		err = tx.WalletService.CreditWallet(senderOPWallet, moneyToken.Amount.Sub(transactionFee))
+		if err != nil {
+			return err
+		}
+
+		// Recording the payment in the ledger
+		err = tx.AddJournalEntry(entity.MethodPaymentQRCode, moneyToken.Code, WalletPosting(receiverID, moneyToken.Amount.Neg()),
+			WalletPosting(moneyToken.SenderID, moneyToken.Amount.Sub(transactionFee)), RevenuePosting(transactionFee))
+		if err != nil {
+			return err
+		}
+
+		err = tx.CollectFee(transactionFee)
+		if err != nil {
+			return err
+		}
+
+		// Adding history for the received payment, the invoice is copied so both sides can match the payment to the order
+		opHistory := new(entity.UserHistory)
+		opHistory.SenderID = receiverID
+		opHistory.ReceiverID = moneyToken.SenderID
+		opHistory.Method = entity.MethodPaymentQRCode
+		opHistory.Code = moneyToken.Code
+		opHistory.Amount = moneyToken.Amount
+		opHistory.Fee = transactionFee
+		opHistory.SentAt = moneyToken.SentAt
+		opHistory.ReceivedAt = time.Now()
+		opHistory.Invoice = moneyToken.Invoice
+
+		return tx.HistoryService.AddHistory(opHistory)
+	})
+	if err != nil {
+		releaseLimits()
+		return err
+	}
 
-	// Adding history for the received payment
-	return onepay.AddUserHistory(receiverID, moneyToken.SenderID, entity.MethodPaymentQRCode, moneyToken.Code,
-		moneyToken.Amount, moneyToken.SentAt, time.Now())
+	return nil
 
 }
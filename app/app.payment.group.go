@@ -0,0 +1,204 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// PaymentGroupReminderInterval is a constant that defines how often the initiator of a payment group can remind its participants
+const PaymentGroupReminderInterval = time.Hour
+
+// SplitPayment is a method that splits a bill between the initiator and a group of participants by sending each participant
+// a payment request for its share. Without custom shares the total is split equally between the participants and the initiator,
+// whose own share isn't requested. Custom shares are matched to the participants by their order and have to add up to the total,
+// so the initiator's own part should be left out of the total when the shares are picked by hand.
+// The group is completed once every share has been paid, or closed as incomplete once a share is declined, cancelled or expires.
+func (onepay *OnePay) SplitPayment(initiatorID, description string, total entity.Money,
+	participants []string, shares []entity.Money) (*entity.PaymentGroup, []*entity.PaymentRequest, error) {
+
+	if len(participants) == 0 {
+		return nil, nil, errors.New("no participant has been provided")
+	}
+
+	seen := make(map[string]bool)
+	for _, participantID := range participants {
+		if participantID == initiatorID {
+			return nil, nil, errors.New(entity.TransactionWSelfError)
+		}
+
+		if seen[participantID] {
+			return nil, nil, errors.New("participants should be unique")
+		}
+		seen[participantID] = true
+
+		if participantID == entity.RevenueWalletID {
+			return nil, nil, errors.New(entity.SenderNotFoundError)
+		}
+
+		if _, err := onepay.WalletService.FindWallet(participantID, entity.BaseCurrency); err != nil {
+			return nil, nil, errors.New(entity.SenderNotFoundError)
+		}
+	}
+
+	customShares := len(shares) > 0
+	if !customShares {
+		shares = SplitEqually(total, len(participants)+1)[1:]
+	}
+
+	if len(shares) != len(participants) {
+		return nil, nil, errors.New(entity.InvalidSharesError)
+	}
+
+	sharesTotal := entity.NewMoney(0, total.Currency)
+	for _, share := range shares {
+		if !share.IsPositive() || share.Currency != total.Currency {
+			return nil, nil, errors.New(entity.InvalidSharesError)
+		}
+
+		if !AboveTransactionBaseLimit(share) {
+			return nil, nil, errors.New(entity.TransactionBaseLimitError)
+		}
+
+		sharesTotal = sharesTotal.Add(share)
+	}
+
+	if customShares && sharesTotal.Cmp(total) != 0 {
+		return nil, nil, errors.New(entity.InvalidSharesError)
+	}
+
+	group := new(entity.PaymentGroup)
+	requests := make([]*entity.PaymentRequest, 0)
+
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+		group = new(entity.PaymentGroup)
+		group.InitiatorID = initiatorID
+		group.Total = total
+		group.Description = description
+
+		err := tx.PaymentRequestService.AddPaymentGroup(group)
+		if err != nil {
+			return err
+		}
+
+		requests = make([]*entity.PaymentRequest, 0)
+		for i, participantID := range participants {
+
+			request := new(entity.PaymentRequest)
+			request.RequesterID = initiatorID
+			request.PayerID = participantID
+			request.Amount = shares[i]
+			request.Note = description
+			request.GroupID = group.ID
+			request.ExpiresAt = time.Now().Add(PaymentRequestDefaultExpiry)
+
+			err = tx.PaymentRequestService.AddPaymentRequest(request)
+			if err != nil {
+				return err
+			}
+
+			requests = append(requests, request)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return group, requests, nil
+}
+
+// PaymentGroupDetail is a method that returns a payment group the user has initiated along with the payment request of each participant
+func (onepay *OnePay) PaymentGroupDetail(userID string, groupID int64) (*entity.PaymentGroup, []*entity.PaymentRequest, error) {
+
+	group, err := onepay.PaymentRequestService.FindPaymentGroup(groupID)
+	if err != nil || group.InitiatorID != userID {
+		return nil, nil, errors.New("payment group not found")
+	}
+
+	return group, onepay.PaymentRequestService.GroupPaymentRequests(groupID), nil
+}
+
+// RemindPaymentGroup is a method that notifies every participant of a payment group that still owes its share again.
+// Reminders can only be sent once in every reminder interval.
+func (onepay *OnePay) RemindPaymentGroup(userID string, groupID int64) ([]*entity.PaymentRequest, error) {
+
+	reminded := make([]*entity.PaymentRequest, 0)
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+		group, err := tx.PaymentRequestService.LockPaymentGroup(groupID)
+		if err != nil || group.InitiatorID != userID {
+			return errors.New("payment group not found")
+		}
+
+		if group.Status != entity.PaymentGroupStatusOpen {
+			return errors.New("payment group has already been closed")
+		}
+
+		if group.LastRemindedAt != nil && time.Now().Before(group.LastRemindedAt.Add(PaymentGroupReminderInterval)) {
+			return errors.New("reminder has already been sent recently")
+		}
+
+		reminded = make([]*entity.PaymentRequest, 0)
+		for _, request := range tx.PaymentRequestService.GroupPaymentRequests(groupID) {
+			if request.Status == entity.PaymentRequestStatusPending {
+				tx.PaymentRequestService.RemindPaymentRequest(request)
+				reminded = append(reminded, request)
+			}
+		}
+
+		remindedAt := time.Now()
+		group.LastRemindedAt = &remindedAt
+
+		return tx.PaymentRequestService.UpdatePaymentGroup(group)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reminded, nil
+}
+
+// completePaymentGroup is a method that marks a payment group as completed, as part of the transaction,
+// once the payment requests of all of its participants have been paid
+func (tx *Transaction) completePaymentGroup(groupID int64) error {
+
+	group, err := tx.PaymentRequestService.LockPaymentGroup(groupID)
+	if err != nil {
+		return err
+	}
+
+	for _, request := range tx.PaymentRequestService.GroupPaymentRequests(groupID) {
+		if request.Status != entity.PaymentRequestStatusPaid {
+			return nil
+		}
+	}
+
+	group.Status = entity.PaymentGroupStatusCompleted
+	return tx.PaymentRequestService.UpdatePaymentGroup(group)
+}
+
+// SplitEqually is a function that splits an amount into the provided number of shares that differ by at most one minor unit.
+// The remaining minor units are added to the first shares so the shares always add up to the amount.
+func SplitEqually(amount entity.Money, count int) []entity.Money {
+
+	shares := make([]entity.Money, count)
+	if count <= 0 {
+		return shares
+	}
+
+	share := amount.Minor / int64(count)
+	remainder := amount.Minor % int64(count)
+
+	for i := range shares {
+		shares[i] = entity.NewMoney(share, amount.Currency)
+		if int64(i) < remainder {
+			shares[i] = entity.NewMoney(share+1, amount.Currency)
+		}
+	}
+
+	return shares
+}
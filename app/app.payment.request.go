@@ -0,0 +1,188 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// PaymentRequestDefaultExpiry is a constant that defines how long a payment request stays pending when no expiry is provided
+const PaymentRequestDefaultExpiry = time.Hour * 24 * 7
+
+// PaymentRequestMaxExpiry is a constant that defines the longest a payment request can stay pending
+const PaymentRequestMaxExpiry = time.Hour * 24 * 30
+
+// RequestPayment is a method that enables a user to request money from another user.
+// The payer is notified and can pay or decline the request until it expires.
+func (onepay *OnePay) RequestPayment(requesterID, payerID string, amount entity.Money,
+	note string, expiry time.Duration) (*entity.PaymentRequest, error) {
+
+	if !AboveTransactionBaseLimit(amount) {
+		return nil, errors.New(entity.TransactionBaseLimitError)
+	}
+
+	if requesterID == payerID {
+		return nil, errors.New(entity.TransactionWSelfError)
+	}
+
+	if payerID == entity.RevenueWalletID {
+		return nil, errors.New(entity.SenderNotFoundError)
+	}
+
+	if _, err := onepay.WalletService.FindWallet(payerID, entity.BaseCurrency); err != nil {
+		return nil, errors.New(entity.SenderNotFoundError)
+	}
+
+	if expiry <= 0 {
+		expiry = PaymentRequestDefaultExpiry
+	}
+
+	if expiry > PaymentRequestMaxExpiry {
+		expiry = PaymentRequestMaxExpiry
+	}
+
+	request := new(entity.PaymentRequest)
+	request.RequesterID = requesterID
+	request.PayerID = payerID
+	request.Amount = amount
+	request.Note = note
+	request.ExpiresAt = time.Now().Add(expiry)
+
+	err := onepay.PaymentRequestService.AddPaymentRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// PayPaymentRequest is a method that enables the payer of a pending payment request to pay it.
+// The money is sent via onepay id so the usual limits and fees apply.
+func (onepay *OnePay) PayPaymentRequest(userID string, requestID int64,
+	redisClient *redis.Client) (*entity.PaymentRequest, error) {
+
+	request, err := onepay.PaymentRequestService.FindPaymentRequest(requestID)
+	if err != nil || request.PayerID != userID {
+		return nil, errors.New("payment request not found")
+	}
+
+	if !AboveTransactionBaseLimit(request.Amount) {
+		return nil, errors.New(entity.TransactionBaseLimitError)
+	}
+
+	transactionFee, err := GetTransactionFee(entity.MethodTransactionOnePayID, request.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseLimits, err := onepay.reserveTransferLimits(userID, request.RequesterID, entity.MethodTransactionOnePayID,
+		request.Amount, redisClient)
+	if err != nil {
+		return nil, err
+	}
+
+	err = onepay.RunInTransaction(func(tx *Transaction) error {
+
+		// Locking the request so it can only be paid once
+		lockedRequest, err := tx.PaymentRequestService.LockPaymentRequest(requestID)
+		if err != nil {
+			return err
+		}
+
+		err = checkPendingPaymentRequest(lockedRequest)
+		if err != nil {
+			return err
+		}
+
+		opHistory, err := tx.TransferViaOnePayID(lockedRequest.PayerID, lockedRequest.RequesterID,
+			lockedRequest.Amount, transactionFee)
+		if err != nil {
+			return err
+		}
+
+		respondedAt := time.Now()
+		lockedRequest.Status = entity.PaymentRequestStatusPaid
+		lockedRequest.HistoryID = opHistory.ID
+		lockedRequest.RespondedAt = &respondedAt
+		request = lockedRequest
+
+		err = tx.PaymentRequestService.UpdatePaymentRequest(lockedRequest)
+		if err != nil {
+			return err
+		}
+
+		if lockedRequest.GroupID != 0 {
+			return tx.completePaymentGroup(int64(lockedRequest.GroupID))
+		}
+
+		return nil
+	})
+	if err != nil {
+		releaseLimits()
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// DeclinePaymentRequest is a method that enables the payer of a pending payment request to decline it
+func (onepay *OnePay) DeclinePaymentRequest(userID string, requestID int64) (*entity.PaymentRequest, error) {
+	return onepay.closePaymentRequest(requestID, entity.PaymentRequestStatusDeclined, func(request *entity.PaymentRequest) bool {
+		return request.PayerID == userID
+	})
+}
+
+// CancelPaymentRequest is a method that enables the requester of a pending payment request to withdraw it
+func (onepay *OnePay) CancelPaymentRequest(userID string, requestID int64) (*entity.PaymentRequest, error) {
+	return onepay.closePaymentRequest(requestID, entity.PaymentRequestStatusCancelled, func(request *entity.PaymentRequest) bool {
+		return request.RequesterID == userID
+	})
+}
+
+// closePaymentRequest is a method that closes a pending payment request with the provided status without moving any money
+func (onepay *OnePay) closePaymentRequest(requestID int64, status string,
+	authorize func(request *entity.PaymentRequest) bool) (*entity.PaymentRequest, error) {
+
+	request := new(entity.PaymentRequest)
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+		lockedRequest, err := tx.PaymentRequestService.LockPaymentRequest(requestID)
+		if err != nil || !authorize(lockedRequest) {
+			return errors.New("payment request not found")
+		}
+
+		err = checkPendingPaymentRequest(lockedRequest)
+		if err != nil {
+			return err
+		}
+
+		respondedAt := time.Now()
+		lockedRequest.Status = status
+		lockedRequest.RespondedAt = &respondedAt
+		request = lockedRequest
+
+		return tx.PaymentRequestService.UpdatePaymentRequest(lockedRequest)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// checkPendingPaymentRequest is a function that checks a payment request can still be responded to
+func checkPendingPaymentRequest(request *entity.PaymentRequest) error {
+
+	if request.Status != entity.PaymentRequestStatusPending {
+		return errors.New(entity.ClosedPaymentRequestError)
+	}
+
+	if time.Now().After(request.ExpiresAt) {
+		return errors.New(entity.ExpiredPaymentRequestError)
+	}
+
+	return nil
+}
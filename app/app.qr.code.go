@@ -0,0 +1,194 @@
+package app
+
+import (
+	"errors"
+	"image"
+	"strings"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+	"github.com/skip2/go-qrcode"
+)
+
+// DefaultQRCodeSize is a constant that defines the size in pixels of qr codes whose size hasn't been requested
+const DefaultQRCodeSize = 256
+
+// MinQRCodeSize is a constant that defines the smallest size in pixels a qr code can be rendered at
+const MinQRCodeSize = 64
+
+// MaxQRCodeSize is a constant that defines the largest size in pixels a qr code can be rendered at
+const MaxQRCodeSize = 1024
+
+// DefaultQRCodeLevel is a constant that defines the error correction level of qr codes whose level hasn't been requested
+const DefaultQRCodeLevel = "M"
+
+// MoneyTokenQRPayload is a function that returns the payload that is encoded in the qr code of a money token.
+// If qr signing keys have been configured the payload carries a signed envelope of the money token.
+func MoneyTokenQRPayload(moneyToken *entity.MoneyToken) (string, error) {
+
+	payloadType := entity.QRPayloadReceive
+	if moneyToken.Method == entity.MethodPaymentQRCode {
+		payloadType = entity.QRPayloadPay
+	}
+
+	keys := QRSigningKeys()
+	if len(keys) == 0 {
+		return tools.QRPayload(payloadType, moneyToken.Code, ""), nil
+	}
+
+	claims := new(tools.QRClaims)
+	claims.Code = moneyToken.Code
+	claims.Amount = moneyToken.Amount.String()
+	claims.Currency = moneyToken.Amount.Currency
+	claims.SenderID = moneyToken.SenderID
+	claims.ExpiresAt = moneyToken.ExpirationDate.Unix()
+
+	envelope, err := tools.SignQRClaims(claims, keys[0])
+	if err != nil {
+		return "", err
+	}
+
+	return tools.QRPayload(payloadType, moneyToken.Code, envelope), nil
+}
+
+// ScannedMoneyTokenCode is a method that returns the money token code from a scanned qr code payload.
+// Once qr signing keys have been configured only OnePay payloads that carry a signed envelope are accepted, the envelope
+// is rejected if its signature or content doesn't match the stored money token. Plain money token codes, unsigned OnePay
+// payloads and EMVCo payloads are only accepted if no keys have been configured or if they have been allowed explicitly.
+// The expiration date of the envelope isn't compared since refreshing a money token extends it,
+// the expiration date of the stored money token is checked when it is used.
+func (onepay *OnePay) ScannedMoneyTokenCode(value string) (string, error) {
+
+	unsignedAccepted := UnsignedQRCodesAccepted()
+
+	if tools.IsEMVQRPayload(value) {
+		if !unsignedAccepted {
+			return "", errors.New(entity.InvalidQRSignatureError)
+		}
+		return onepay.emvPaymentTokenCode(value)
+	}
+
+	if !strings.HasPrefix(strings.TrimSpace(value), entity.QRPayloadScheme+":") {
+		if !unsignedAccepted {
+			return "", errors.New(entity.InvalidQRSignatureError)
+		}
+		return value, nil
+	}
+
+	payloadType, code, envelope, err := tools.ParseQRPayload(value)
+	if err != nil {
+		return "", err
+	}
+
+	if envelope == "" {
+		if !unsignedAccepted {
+			return "", errors.New(entity.InvalidQRSignatureError)
+		}
+		return code, nil
+	}
+
+	claims, err := tools.VerifyQREnvelope(envelope, QRSigningKeys())
+	if err != nil {
+		return "", err
+	}
+
+	moneyToken, err := onepay.MoneyTokenService.FindMoneyToken(code)
+	if err != nil {
+		return "", errors.New(entity.InvalidMoneyTokenError)
+	}
+
+	expectedType := entity.QRPayloadReceive
+	if moneyToken.Method == entity.MethodPaymentQRCode {
+		expectedType = entity.QRPayloadPay
+	}
+
+	if payloadType != expectedType ||
+		claims.Code != moneyToken.Code ||
+		claims.Amount != moneyToken.Amount.String() ||
+		claims.Currency != moneyToken.Amount.Currency ||
+		claims.SenderID != moneyToken.SenderID {
+		return "", errors.New(entity.QRPayloadMismatchError)
+	}
+
+	return code, nil
+}
+
+// MoneyTokenQRCode is a method that renders the qr code of an active money token as a png or svg image.
+// Only the sender of the money token can get its qr code. Payment money tokens can also be rendered as dynamic EMVCo qr codes.
+func (onepay *OnePay) MoneyTokenQRCode(code, userID, payloadFormat, format string, size int, level string,
+	withLogo bool) ([]byte, error) {
+
+	moneyToken, err := onepay.MoneyTokenService.FindMoneyToken(code)
+	if err != nil || moneyToken.SenderID != userID {
+		return nil, errors.New(entity.InvalidMoneyTokenError)
+	}
+
+	if !moneyToken.IsActive() {
+		return nil, errors.New(entity.InactiveMoneyTokenError)
+	}
+
+	var payload string
+	switch payloadFormat {
+	case "", entity.QRPayloadFormatNative:
+		payload, err = MoneyTokenQRPayload(moneyToken)
+	case entity.QRPayloadFormatEMV:
+		// EMVCo payloads can't carry a signed envelope so they aren't rendered if they wouldn't be accepted
+		if !UnsignedQRCodesAccepted() {
+			err = errors.New(entity.InvalidQRPayloadFormatError)
+			break
+		}
+		payload, err = onepay.PaymentTokenEMVPayload(moneyToken)
+	default:
+		err = errors.New(entity.InvalidQRPayloadFormatError)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return RenderQRCode(payload, format, size, level, withLogo)
+}
+
+// RenderQRCode is a function that renders the payload as a png or svg qr code image.
+// The size and error correction level fall back to the defaults if they are empty.
+func RenderQRCode(payload, format string, size int, level string, withLogo bool) ([]byte, error) {
+
+	if size == 0 {
+		size = DefaultQRCodeSize
+	}
+
+	if size < MinQRCodeSize || size > MaxQRCodeSize {
+		return nil, errors.New(entity.InvalidQRCodeSizeError)
+	}
+
+	if level == "" {
+		level = DefaultQRCodeLevel
+	}
+
+	recoveryLevel, err := tools.QRCodeLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	var logo image.Image
+	if withLogo {
+		logo, err = tools.QRCodeLogo()
+		if err != nil {
+			return nil, err
+		}
+
+		// The logo covers the center of the qr code so at least a quarter of the code should be recoverable
+		if recoveryLevel < qrcode.High {
+			recoveryLevel = qrcode.High
+		}
+	}
+
+	switch format {
+	case entity.QRCodeFormatPNG:
+		return tools.QRCodePNG(payload, size, recoveryLevel, logo)
+	case entity.QRCodeFormatSVG:
+		return tools.QRCodeSVG(payload, size, recoveryLevel, logo)
+	}
+
+	return nil, errors.New("unsupported qr code format used")
+}
@@ -4,14 +4,16 @@ import (
 	"errors"
 	"time"
 
+	"github.com/go-redis/redis"
+
 	"github.com/Benyam-S/onepay/entity"
-	"github.com/Benyam-S/onepay/logger"
 )
 
-// ReceiveViaQRCode is a method that endables users to receive money via qr code
-func (onepay *OnePay) ReceiveViaQRCode(receiverID string, code string) error {
+// ReceiveViaQRCode is a method that endables users to receive money via qr code.
+// The secret is only checked for money tokens that have been protected by their sender.
+func (onepay *OnePay) ReceiveViaQRCode(receiverID, code, secret string, redisClient *redis.Client) error {
 
-	receiverOPWallet, err := onepay.WalletService.FindWallet(receiverID)
+	_, err := onepay.WalletService.FindWallet(receiverID, entity.BaseCurrency)
 	if err != nil {
 		return errors.New(entity.ReceiverNotFoundError)
 	}
@@ -21,12 +23,12 @@ func (onepay *OnePay) ReceiveViaQRCode(receiverID string, code string) error {
 		return errors.New(entity.InvalidMoneyTokenError)
 	}
 
-	if !moneyToken.ExpirationDate.After(time.Now()) {
-		return errors.New(entity.ExpiredMoneyTokenError)
+	if !moneyToken.IsActive() {
+		return errors.New(entity.InactiveMoneyTokenError)
 	}
 
-	if !AboveTransactionBaseLimit(moneyToken.Amount) {
-		return errors.New(entity.TransactionBaseLimitError)
+	if !moneyToken.ExpirationDate.After(time.Now()) {
+		return errors.New(entity.ExpiredMoneyTokenError)
 	}
 
 	if moneyToken.SenderID == receiverID {
@@ -37,35 +39,119 @@ func (onepay *OnePay) ReceiveViaQRCode(receiverID string, code string) error {
 		return errors.New(entity.InvalidMethodError)
 	}
 
-	// Delete the money token first in case the use wallet fails to update
-	receiverOPWallet.Amount = receiverOPWallet.Amount + moneyToken.Amount
-	_, err = onepay.MoneyTokenService.DeleteMoneyToken(moneyToken.Code)
-	if err != nil {
-		return err
+	if moneyToken.Locked {
+		return errors.New(entity.LockedMoneyTokenError)
 	}
 
-	/* ++++ ++++ +++ checkpoint - wallet +++ ++++ ++++ */
-	tempOPWallet := new(entity.UserWallet)
-	tempOPWallet.UserID = receiverOPWallet.UserID
-	tempOPWallet.Amount = moneyToken.Amount
-	logger.Must(onepay.Logger.LogWallet(tempOPWallet))
-	/* +++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++++ */
+	// Failed attempts are counted outside of the claim's transaction so that they aren't rolled back
+	if !onepay.MoneyTokenService.CheckMoneyTokenSecret(moneyToken, secret) {
+		onepay.MoneyTokenService.AddFailedAttempt(moneyToken.Code, MaxMoneyTokenAttempts)
+		return errors.New(entity.InvalidMoneyTokenSecretError)
+	}
 
-	err = onepay.WalletService.UpdateWallet(receiverOPWallet)
-	if err != nil {
+	_, err = onepay.MoneyTokenService.FindClaim(moneyToken.Code, receiverID)
+	if err == nil {
+		return errors.New(entity.MoneyTokenClaimedError)
+	}
 
-		// Adding history for the potential reload
-		onepay.AddUserHistory(moneyToken.SenderID, receiverID, entity.MethodTransactionQRCode, moneyToken.Code,
-			moneyToken.Amount, moneyToken.SentAt, time.Now())
+	claimAmount, _ := moneyToken.NextClaim()
+	if !AboveTransactionBaseLimit(claimAmount) {
+		return errors.New(entity.TransactionBaseLimitError)
+	}
 
-		return errors.New(entity.WalletCheckpointError)
+	releaseLimit, err := onepay.ReserveLimit(receiverID, entity.LimitReceive, entity.MethodTransactionQRCode,
+		claimAmount, redisClient)
+	if err != nil {
+		return err
 	}
 
-	/* +++++ +++++ +++++ checkpoint end +++++ +++++ +++++ */
-	logger.Must(onepay.Logger.RemoveWallet(tempOPWallet))
-	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
+	err = onepay.RunInTransaction(func(tx *Transaction) error {
+
+		// The money token is locked before anything else is read so that claims on it are made one at a time
+		moneyToken, err := tx.MoneyTokenService.LockMoneyToken(code)
+		if err != nil {
+			return errors.New(entity.InvalidMoneyTokenError)
+		}
+
+		if !moneyToken.IsActive() {
+			return errors.New(entity.InactiveMoneyTokenError)
+		}
+
+		if !moneyToken.ExpirationDate.After(time.Now()) {
+			return errors.New(entity.ExpiredMoneyTokenError)
+		}
+
+		if moneyToken.Locked {
+			return errors.New(entity.LockedMoneyTokenError)
+		}
+
+		_, err = tx.MoneyTokenService.FindClaim(moneyToken.Code, receiverID)
+		if err == nil {
+			return errors.New(entity.MoneyTokenClaimedError)
+		}
+
+		// The wallet is read inside the transaction so a concurrent change can be detected
+		receiverOPWallet, err := tx.ReceivingWallet(receiverID, moneyToken.Amount.Currency)
+		if err != nil {
+			return errors.New(entity.ReceiverNotFoundError)
+		}
+
+		claimAmount, transactionFee := moneyToken.NextClaim()
+
+		claim := new(entity.MoneyTokenClaim)
+		claim.Code = moneyToken.Code
+		claim.ClaimantID = receiverID
+		claim.Amount = claimAmount
+		claim.Fee = transactionFee
+		claim.ClaimedAt = time.Now()
+
+		err = tx.MoneyTokenService.AddClaim(claim)
+		if err != nil {
+			return err
+		}
+
+		moneyToken.ClaimCount++
+		moneyToken.Remaining = moneyToken.Remaining.Sub(claimAmount)
+		moneyToken.RemainingFee = moneyToken.RemainingFee.Sub(transactionFee)
+		moneyToken.ClaimantID = receiverID
+		moneyToken.ClaimedAt = &claim.ClaimedAt
+
+		// The money token is only closed once all of its claims have been made
+		if moneyToken.ClaimsLeft() == 0 {
+			moneyToken.Status = entity.MoneyTokenStatusClaimed
+		}
+
+		err = tx.MoneyTokenService.UpdateMoneyToken(moneyToken)
+		if err != nil {
+			return err
+		}
+
+		err = tx.WalletService.CreditWallet(receiverOPWallet, claimAmount)
+		if err != nil {
+			return err
+		}
+
+		// Releasing the money locked in the money token to the receiver in the ledger
+		err = tx.AddJournalEntry(entity.MethodTransactionQRCode, moneyToken.Code,
+			MoneyTokenHoldingPosting(claimAmount.Add(transactionFee).Neg()),
+			WalletPosting(receiverID, claimAmount), RevenuePosting(transactionFee))
+		if err != nil {
+			return err
+		}
+
+		err = tx.CollectFee(transactionFee)
+		if err != nil {
+			return err
+		}
+
+		// Adding history for the received token
+		return tx.AddUserHistory(moneyToken.SenderID, receiverID, entity.MethodTransactionQRCode, moneyToken.Code,
+			claimAmount, transactionFee, moneyToken.SentAt, time.Now())
+	})
+	if err != nil {
+		releaseLimit()
+		return err
+	}
 
-	// Adding history for the received token
-	return onepay.AddUserHistory(moneyToken.SenderID, receiverID, entity.MethodTransactionQRCode, moneyToken.Code,
-		moneyToken.Amount, moneyToken.SentAt, time.Now())
+	return nil
 }
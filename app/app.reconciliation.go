@@ -0,0 +1,250 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// ReconciliationInterval is a constant that defines how often the wallets are reconciled with the user histories
+const ReconciliationInterval = time.Hour * 24
+
+// ReconcileWallets is a method that recomputes the expected balance of every wallet from the user histories and
+// records the wallets whose amount or held amount doesn't match in a new reconciliation report.
+// If quarantine is set the mismatched wallets are also quarantined so no money can leave them until they are reviewed.
+func (onepay *OnePay) ReconcileWallets(triggeredBy string, quarantine bool) (*entity.ReconciliationReport, error) {
+
+	report := new(entity.ReconciliationReport)
+	report.TriggeredBy = triggeredBy
+	report.StartedAt = time.Now()
+
+	err := onepay.ReconciliationService.AddReport(report)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0)
+	reconciled := make(map[string]bool)
+	for _, opWallet := range onepay.WalletService.AllWallets() {
+		if !reconciled[opWallet.UserID] {
+			reconciled[opWallet.UserID] = true
+			userIDs = append(userIDs, opWallet.UserID)
+		}
+	}
+
+	for _, userID := range userIDs {
+
+		var walletCount int
+		var discrepancies []*entity.WalletDiscrepancy
+
+		// Each user is reconciled in its own transaction so the wallets and the histories are read from the same snapshot
+		err = onepay.RunInTransaction(func(tx *Transaction) error {
+
+			opWallets := tx.WalletService.SearchWallets(userID)
+			expectedAmounts, expectedHelds := tx.ExpectedBalances(userID)
+
+			found, err := tx.walletDiscrepancies(opWallets, userID, expectedAmounts, expectedHelds, quarantine)
+			walletCount = len(opWallets)
+			discrepancies = found
+			return err
+		})
+		if err != nil {
+			continue
+		}
+
+		report.WalletCount += walletCount
+		for _, discrepancy := range discrepancies {
+			discrepancy.ReportID = report.ID
+			if onepay.ReconciliationService.AddDiscrepancy(discrepancy) != nil {
+				continue
+			}
+
+			report.DiscrepancyCount++
+			if discrepancy.Quarantined {
+				report.QuarantineCount++
+			}
+		}
+	}
+
+	finishedAt := time.Now()
+	report.FinishedAt = &finishedAt
+
+	err = onepay.ReconciliationService.UpdateReport(report)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// ExpectedBalances is a method that recomputes the amount and the held amount a certain user's wallets should have,
+// one for each currency, from the user histories together with the money that is still locked outside of them.
+// The system revenue wallet is expected to hold the fees of every history.
+func (tx *Transaction) ExpectedBalances(userID string) (map[string]entity.Money, map[string]entity.Money) {
+
+	expectedAmounts := make(map[string]entity.Money)
+	expectedHelds := make(map[string]entity.Money)
+
+	addTo := func(balances map[string]entity.Money, amount entity.Money) {
+		if balance, ok := balances[amount.Currency]; ok {
+			amount = balance.Add(amount)
+		}
+		balances[amount.Currency] = amount
+	}
+
+	if userID == entity.RevenueWalletID {
+		for _, totalFee := range tx.HistoryService.TotalFees() {
+			addTo(expectedAmounts, totalFee)
+		}
+		return expectedAmounts, expectedHelds
+	}
+
+	for _, opHistory := range tx.HistoryService.AllUserHistories(userID) {
+
+		amount := opHistory.Amount
+		fee := opHistory.Fee
+
+		// Capturing a hold takes the money from the held amount of the sender, so it is accounted for by the holds
+		if opHistory.SenderID == userID {
+			switch opHistory.Method {
+			case entity.MethodTransactionOnePayID, entity.MethodTransactionQRCode, entity.MethodWithdrawn:
+				addTo(expectedAmounts, amount.Add(fee).Neg())
+			case entity.MethodPaymentQRCode, entity.MethodHoldAuthorization, entity.MethodRefund, entity.MethodReversal:
+				addTo(expectedAmounts, amount.Neg())
+			case entity.MethodCurrencyConversion:
+				addTo(expectedAmounts, amount.Neg())
+				addTo(expectedAmounts, opHistory.ConvertedAmount)
+			}
+		}
+
+		if opHistory.ReceiverID == userID {
+			switch opHistory.Method {
+			case entity.MethodTransactionOnePayID, entity.MethodTransactionQRCode, entity.MethodHoldRelease,
+				entity.MethodRefund:
+				addTo(expectedAmounts, amount)
+
+			// A reversed withdrawal returns its fee as a negative fee, any other reversal has no fee
+			case entity.MethodPaymentQRCode, entity.MethodHoldCapture, entity.MethodRecharged, entity.MethodReversal:
+				addTo(expectedAmounts, amount.Sub(fee))
+			}
+		}
+	}
+
+	// Money and fee locked in transaction money tokens for the claims that haven't been made yet
+	for _, moneyToken := range tx.MoneyTokenService.SearchMoneyToken(userID, entity.MoneyTokenStatusActive) {
+		if moneyToken.Method == entity.MethodTransactionQRCode {
+			addTo(expectedAmounts, moneyToken.Remaining.Add(moneyToken.RemainingFee).Neg())
+		}
+	}
+
+	// Money held from the counterparty of disputes that haven't been resolved yet
+	for _, opDispute := range tx.DisputeService.SearchDisputes(userID) {
+		if opDispute.CounterpartyID == userID && opDispute.IsActive() {
+			addTo(expectedAmounts, opDispute.HeldAmount.Neg())
+		}
+	}
+
+	for _, opHold := range tx.HoldService.SearchHolds(userID) {
+		if opHold.UserID == userID && opHold.Status == entity.HoldStatusAuthorized {
+			addTo(expectedHelds, opHold.Amount)
+		}
+	}
+
+	return expectedAmounts, expectedHelds
+}
+
+// walletDiscrepancies is a method that compares a certain user's wallets with their expected balances and
+// returns the mismatched ones, quarantining them if requested.
+// An expected balance of a currency the user has no wallet for is also returned as a discrepancy.
+func (tx *Transaction) walletDiscrepancies(opWallets []*entity.UserWallet, userID string,
+	expectedAmounts, expectedHelds map[string]entity.Money, quarantine bool) ([]*entity.WalletDiscrepancy, error) {
+
+	discrepancies := make([]*entity.WalletDiscrepancy, 0)
+	compared := make(map[string]bool)
+
+	expected := func(balances map[string]entity.Money, currency string) entity.Money {
+		if balance, ok := balances[currency]; ok {
+			return balance
+		}
+		return entity.NewMoney(0, currency)
+	}
+
+	for _, opWallet := range opWallets {
+
+		compared[opWallet.Currency] = true
+		expectedAmount := expected(expectedAmounts, opWallet.Currency)
+		expectedHeld := expected(expectedHelds, opWallet.Currency)
+
+		if opWallet.Amount.Cmp(expectedAmount) == 0 && opWallet.Held.Cmp(expectedHeld) == 0 {
+			continue
+		}
+
+		if quarantine && !opWallet.Quarantined {
+			err := tx.WalletService.QuarantineWallet(opWallet, true)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		discrepancy := new(entity.WalletDiscrepancy)
+		discrepancy.UserID = userID
+		discrepancy.ExpectedAmount = expectedAmount
+		discrepancy.ActualAmount = opWallet.Amount
+		discrepancy.ExpectedHeld = expectedHeld
+		discrepancy.ActualHeld = opWallet.Held
+		discrepancy.Quarantined = opWallet.Quarantined
+
+		discrepancies = append(discrepancies, discrepancy)
+	}
+
+	for currency, expectedAmount := range expectedAmounts {
+
+		expectedHeld := expected(expectedHelds, currency)
+		if compared[currency] || (expectedAmount.IsZero() && expectedHeld.IsZero()) {
+			continue
+		}
+
+		discrepancy := new(entity.WalletDiscrepancy)
+		discrepancy.UserID = userID
+		discrepancy.ExpectedAmount = expectedAmount
+		discrepancy.ActualAmount = entity.NewMoney(0, currency)
+		discrepancy.ExpectedHeld = expectedHeld
+		discrepancy.ActualHeld = entity.NewMoney(0, currency)
+
+		discrepancies = append(discrepancies, discrepancy)
+	}
+
+	return discrepancies, nil
+}
+
+// QuarantineWallet is a method that enables a staff member to quarantine or release a certain user's wallet of the provided currency
+func (onepay *OnePay) QuarantineWallet(userID, currency string, quarantined bool) (*entity.UserWallet, error) {
+
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency != "" && !entity.IsSupportedCurrency(currency) {
+		return nil, errors.New(entity.UnsupportedCurrencyError)
+	}
+
+	opWallet := new(entity.UserWallet)
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+		lockedWallet, err := tx.WalletService.FindWallet(userID, currency)
+		if err != nil {
+			return err
+		}
+
+		opWallet = lockedWallet
+		if lockedWallet.Quarantined == quarantined {
+			return nil
+		}
+
+		return tx.WalletService.QuarantineWallet(lockedWallet, quarantined)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opWallet, nil
+}
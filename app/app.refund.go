@@ -0,0 +1,144 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// RefundHistory is a method that enables the receiver of a transaction or a payment to return all or part of the money to the sender.
+// A zero amount refunds everything that hasn't been refunded yet.
+func (onepay *OnePay) RefundHistory(userID string, historyID int64, amount entity.Money) (*entity.UserHistory, error) {
+
+	return onepay.returnHistoryAmount(historyID, entity.MethodRefund, amount, func(opHistory *entity.UserHistory) error {
+		if opHistory.ReceiverID != userID {
+			return errors.New("cannot refund history which you haven't received")
+		}
+		return nil
+	})
+}
+
+// ReverseHistory is a method that enables staff members to return all or part of the money of a transaction or a payment
+// to the sender without the consent of the receiver, for example when money has been sent to the wrong onepay id.
+// A zero amount reverses everything that hasn't been refunded yet.
+func (onepay *OnePay) ReverseHistory(historyID int64, amount entity.Money) (*entity.UserHistory, error) {
+
+	return onepay.returnHistoryAmount(historyID, entity.MethodReversal, amount, func(opHistory *entity.UserHistory) error {
+		return nil
+	})
+}
+
+// returnHistoryAmount is a method that moves the provided amount from the receiver of a history back to its sender
+// and records it as a new history that references the original one. The original history is locked while the
+// refundable amount is checked so concurrent refunds can never return more than the original amount.
+// The fee of the original history is not returned.
+func (onepay *OnePay) returnHistoryAmount(historyID int64, method string, amount entity.Money,
+	authorize func(opHistory *entity.UserHistory) error) (*entity.UserHistory, error) {
+
+	if amount.IsNegative() {
+		return nil, errors.New("invalid amount used")
+	}
+
+	opHistory := new(entity.UserHistory)
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+		parentHistory, err := tx.HistoryService.LockHistory(historyID)
+		if err != nil {
+			return err
+		}
+
+		switch parentHistory.Method {
+		case entity.MethodTransactionOnePayID, entity.MethodTransactionQRCode, entity.MethodPaymentQRCode,
+			entity.MethodHoldCapture:
+		default:
+			return errors.New(entity.NonRefundableHistoryError)
+		}
+
+		// Disputed money is settled by the dispute resolution, so it can't be refunded in the meantime
+		if _, err := tx.DisputeService.ActiveDispute(historyID); err == nil {
+			return errors.New(entity.DisputedHistoryError)
+		}
+
+		err = authorize(parentHistory)
+		if err != nil {
+			return err
+		}
+
+		// Refunds are made in the currency of the original history
+		currency := parentHistory.Amount.Currency
+		if amount.Currency != "" && !amount.IsZero() && amount.Currency != currency {
+			return errors.New("amount currency doesn't match the history currency")
+		}
+
+		refundedAmount := entity.NewMoney(0, currency)
+		for _, childHistory := range tx.HistoryService.ChildHistories(historyID, entity.MethodRefund, entity.MethodReversal) {
+			refundedAmount = refundedAmount.Add(childHistory.Amount)
+		}
+
+		refundableAmount := parentHistory.Amount.Sub(refundedAmount)
+		if !refundableAmount.IsPositive() {
+			return errors.New(entity.FullyRefundedError)
+		}
+
+		refundAmount := entity.NewMoney(amount.Minor, currency)
+		if refundAmount.IsZero() {
+			refundAmount = refundableAmount
+		}
+
+		if refundAmount.GreaterThan(refundableAmount) {
+			return errors.New(entity.RefundExceedsAmountError)
+		}
+
+		// The money goes back from the one who has received it to the one who has sent it
+		payerOPWallet, err := tx.WalletService.FindWallet(parentHistory.ReceiverID, currency)
+		if err != nil {
+			return errors.New(entity.InsufficientBalanceError)
+		}
+
+		if payerOPWallet.Amount.LessThan(refundAmount) {
+			return errors.New(entity.InsufficientBalanceError)
+		}
+
+		payeeOPWallet, err := tx.ReceivingWallet(parentHistory.SenderID, currency)
+		if err != nil {
+			return errors.New(entity.ReceiverNotFoundError)
+		}
+
+		err = tx.WalletService.DebitWallet(payerOPWallet, refundAmount)
+		if err != nil {
+			return err
+		}
+
+		err = tx.WalletService.CreditWallet(payeeOPWallet, refundAmount)
+		if err != nil {
+			return err
+		}
+
+		// Recording the returned money in the ledger
+		err = tx.AddJournalEntry(method, parentHistory.Code, WalletPosting(parentHistory.ReceiverID, refundAmount.Neg()),
+			WalletPosting(parentHistory.SenderID, refundAmount))
+		if err != nil {
+			return err
+		}
+
+		// Adding a history that references the original one, adding it notifies both parties
+		opHistory = new(entity.UserHistory)
+		opHistory.SenderID = parentHistory.ReceiverID
+		opHistory.ReceiverID = parentHistory.SenderID
+		opHistory.Method = method
+		opHistory.Code = parentHistory.Code
+		opHistory.Amount = refundAmount
+		opHistory.Fee = entity.NewMoney(0, currency)
+		opHistory.ParentID = parentHistory.ID
+		opHistory.SentAt = time.Now()
+		opHistory.ReceivedAt = time.Now()
+
+		return tx.HistoryService.AddHistory(opHistory)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opHistory, nil
+}
@@ -13,6 +13,7 @@ func (onepay *OnePay) ReloadHistory() {
 
 		tempHistory := new(entity.UserHistory)
 		tempHistory.Amount = loggedHistory.Amount
+		tempHistory.Fee = loggedHistory.Fee
 		tempHistory.Code = loggedHistory.Code
 		tempHistory.Method = loggedHistory.Method
 		tempHistory.SentAt = loggedHistory.SentAt
@@ -35,6 +36,7 @@ func (onepay *OnePay) ReloadMoneyToken() {
 
 		tempMoneyToken := new(entity.MoneyToken)
 		tempMoneyToken.Amount = loggedMoneyToken.Amount
+		tempMoneyToken.Fee = loggedMoneyToken.Fee
 		tempMoneyToken.Code = loggedMoneyToken.Code
 		tempMoneyToken.ExpirationDate = loggedMoneyToken.ExpirationDate
 		tempMoneyToken.Method = loggedMoneyToken.Method
@@ -53,21 +55,43 @@ func (onepay *OnePay) ReloadWallet() {
 
 	loggedWallets := onepay.Logger.LoggedWallets()
 	for _, loggedWallet := range loggedWallets {
-		opWallet, err := onepay.WalletService.FindWallet(loggedWallet.UserID)
+		opWallet, err := onepay.WalletService.FindWallet(loggedWallet.UserID, loggedWallet.Amount.Currency)
 		if err != nil {
 			continue
 		}
 
 		// This will protect the user from multiple draining
-		if opWallet.Amount <= 0 && loggedWallet.Amount < 0 {
+		if !opWallet.Amount.IsPositive() && loggedWallet.Amount.IsNegative() {
 			logger.Must(onepay.Logger.RemoveWallet(loggedWallet))
 			continue
 		}
 
-		opWallet.Amount += loggedWallet.Amount
+		opWallet.Amount = opWallet.Amount.Add(loggedWallet.Amount)
 		err = onepay.WalletService.UpdateWallet(opWallet)
 		if err == nil {
 			logger.Must(onepay.Logger.RemoveWallet(loggedWallet))
 		}
 	}
 }
+
+// ReloadJournalEntry is a method that enables the server to reload any journal entry posting process
+func (onepay *OnePay) ReloadJournalEntry() {
+
+	loggedJournalEntries := onepay.Logger.LoggedJournalEntries()
+	for _, loggedJournalEntry := range loggedJournalEntries {
+
+		tempJournalEntry := new(entity.JournalEntry)
+		tempJournalEntry.Method = loggedJournalEntry.Method
+		tempJournalEntry.Code = loggedJournalEntry.Code
+		tempJournalEntry.CreatedAt = loggedJournalEntry.CreatedAt
+		for _, posting := range loggedJournalEntry.Postings {
+			tempJournalEntry.Postings = append(tempJournalEntry.Postings,
+				&entity.Posting{AccountID: posting.AccountID, Amount: posting.Amount})
+		}
+
+		err := onepay.LedgerService.PostJournalEntry(loggedJournalEntry)
+		if err == nil || err.Error() == entity.UnbalancedJournalEntryError {
+			logger.Must(onepay.Logger.RemoveJournalEntry(tempJournalEntry))
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/go-redis/redis"
+)
+
+// ScheduledTransferMaxAttempts is a constant that defines how many times a scheduled transfer run is tried before it is given up
+const ScheduledTransferMaxAttempts = 5
+
+// ScheduledTransferRetryDelay is a constant that defines how long the scheduler waits before retrying a failed run for the first time.
+// The delay doubles on every retry.
+const ScheduledTransferRetryDelay = time.Minute * 5
+
+// ScheduledTransferClaimLease is a constant that defines how long a scheduled transfer can be running before its run
+// is taken to be interrupted. The interrupted run is retried, so a run that has moved the money and then died is made again.
+const ScheduledTransferClaimLease = time.Minute * 15
+
+// ScheduleTransfer is a method that schedules a transfer via onepay id to be made at the start time and, for recurring transfers,
+// on every following day, week or month until the end date
+func (onepay *OnePay) ScheduleTransfer(senderID, receiverID string, amount entity.Money,
+	frequency string, startAt time.Time, endDate *time.Time) (*entity.ScheduledTransfer, error) {
+
+	if !AboveTransactionBaseLimit(amount) {
+		return nil, errors.New(entity.TransactionBaseLimitError)
+	}
+
+	if senderID == receiverID {
+		return nil, errors.New(entity.TransactionWSelfError)
+	}
+
+	if receiverID == entity.RevenueWalletID {
+		return nil, errors.New(entity.ReceiverNotFoundError)
+	}
+
+	if _, err := onepay.WalletService.FindWallet(senderID, entity.BaseCurrency); err != nil {
+		return nil, errors.New(entity.SenderNotFoundError)
+	}
+
+	if _, err := onepay.WalletService.FindWallet(receiverID, entity.BaseCurrency); err != nil {
+		return nil, errors.New(entity.ReceiverNotFoundError)
+	}
+
+	// A one-off transfer has nothing to end
+	if frequency == entity.FrequencyOnce {
+		endDate = nil
+	}
+
+	if !startAt.After(time.Now()) || (endDate != nil && endDate.Before(startAt)) {
+		return nil, errors.New(entity.InvalidScheduleError)
+	}
+
+	transfer := new(entity.ScheduledTransfer)
+	transfer.SenderID = senderID
+	transfer.ReceiverID = receiverID
+	transfer.Amount = amount
+	transfer.Frequency = frequency
+	transfer.StartAt = startAt
+	transfer.EndDate = endDate
+
+	err := onepay.ScheduledTransferService.AddScheduledTransfer(transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// CancelScheduledTransfer is a method that stops a certain user's scheduled transfer from making any further runs.
+// A running transfer can also be cancelled, the run that is being made isn't stopped but no further run is made.
+func (onepay *OnePay) CancelScheduledTransfer(userID string, transferID int64) (*entity.ScheduledTransfer, error) {
+	return onepay.ScheduledTransferService.CancelScheduledTransfer(transferID, userID)
+}
+
+// RunScheduledTransfers is a method that makes all the scheduled transfers that are due.
+// Each transfer is claimed before it is made so concurrent schedulers never make the same run twice,
+// a claim that hasn't finished within the claim lease is taken to be interrupted and its transfer is requeued.
+func (onepay *OnePay) RunScheduledTransfers(redisClient *redis.Client) {
+
+	onepay.ScheduledTransferService.RequeueScheduledTransfers(time.Now().Add(-ScheduledTransferClaimLease))
+
+	for _, transfer := range onepay.ScheduledTransferService.DueScheduledTransfers(time.Now()) {
+
+		if !onepay.ScheduledTransferService.ClaimScheduledTransfer(transfer) {
+			continue
+		}
+
+		onepay.runScheduledTransfer(transfer, redisClient)
+	}
+}
+
+// runScheduledTransfer is a method that makes a single run of a claimed scheduled transfer and plans its next run.
+// A failed run is retried with an increasing delay, once all the attempts fail a one-off transfer is marked as failed
+// while a recurring transfer skips to its next occurrence.
+func (onepay *OnePay) runScheduledTransfer(transfer *entity.ScheduledTransfer, redisClient *redis.Client) {
+
+	err := onepay.SendViaOnePayID(transfer.SenderID, transfer.ReceiverID, transfer.Amount, redisClient)
+
+	lastRunAt := time.Now()
+	transfer.LastRunAt = &lastRunAt
+
+	if err != nil {
+		transfer.Attempts++
+		transfer.LastError = err.Error()
+
+		if transfer.Attempts < ScheduledTransferMaxAttempts {
+			transfer.Status = entity.ScheduledTransferStatusActive
+			transfer.NextRunAt = lastRunAt.Add(ScheduledTransferRetryDelay * time.Duration(1<<uint(transfer.Attempts-1)))
+			onepay.saveScheduledTransfer(transfer)
+			return
+		}
+
+		if transfer.Frequency == entity.FrequencyOnce {
+			transfer.Status = entity.ScheduledTransferStatusFailed
+			onepay.saveScheduledTransfer(transfer)
+			return
+		}
+
+	} else {
+		transfer.LastError = ""
+	}
+
+	transfer.Attempts = 0
+	transfer.Occurrences++
+
+	nextOccurrence := NextOccurrence(transfer.StartAt, transfer.Frequency, transfer.Occurrences)
+	if transfer.Frequency == entity.FrequencyOnce || (transfer.EndDate != nil && nextOccurrence.After(*transfer.EndDate)) {
+		transfer.Status = entity.ScheduledTransferStatusCompleted
+	} else {
+		transfer.Status = entity.ScheduledTransferStatusActive
+		transfer.ScheduledAt = nextOccurrence
+		transfer.NextRunAt = nextOccurrence
+	}
+
+	onepay.saveScheduledTransfer(transfer)
+}
+
+// saveScheduledTransfer is a method that stores the outcome of a scheduled transfer run,
+// keeping the transfer cancelled if its sender has cancelled it while it was running.
+// The cancellation is checked by the update itself so a concurrent cancel can't be overwritten.
+func (onepay *OnePay) saveScheduledTransfer(transfer *entity.ScheduledTransfer) {
+
+	updated, err := onepay.ScheduledTransferService.UpdateScheduledTransferUnlessCancelled(transfer)
+	if err == nil && !updated {
+		transfer.Status = entity.ScheduledTransferStatusCancelled
+	}
+}
+
+// NextOccurrence is a function that returns the time of the nth occurrence after the start of a recurring schedule.
+// Monthly occurrences keep the day of the start time, falling back to the last day of shorter months.
+func NextOccurrence(startAt time.Time, frequency string, n int) time.Time {
+
+	switch frequency {
+	case entity.FrequencyDaily:
+		return startAt.AddDate(0, 0, n)
+
+	case entity.FrequencyWeekly:
+		return startAt.AddDate(0, 0, 7*n)
+
+	case entity.FrequencyMonthly:
+		firstOfMonth := time.Date(startAt.Year(), startAt.Month()+time.Month(n), 1,
+			startAt.Hour(), startAt.Minute(), startAt.Second(), startAt.Nanosecond(), startAt.Location())
+
+		day := startAt.Day()
+		if lastDay := firstOfMonth.AddDate(0, 1, -1).Day(); day > lastDay {
+			day = lastDay
+		}
+
+		return firstOfMonth.AddDate(0, 0, day-1)
+	}
+
+	return startAt
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+)
+
+// JobLockTimeout is a constant that defines how long a job's lock is kept if the instance running the job stops before releasing it
+const JobLockTimeout = time.Hour
+
+// JobRetryDelay is a constant that defines how long the scheduler waits before trying again to run a job it couldn't run
+const JobRetryDelay = time.Second * 30
+
+// JobRunRetention is a constant that defines how long the runs of the jobs are kept before they are pruned
+const JobRunRetention = time.Hour * 24 * 7
+
+// JobRunPruneInterval is a constant that defines how often the job runs that are older than the retention period are pruned
+const JobRunPruneInterval = time.Hour * 24
+
+// Job is a type that defines a named job that is run once every interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// Scheduler is a type that runs the registered jobs in the background.
+// Server instances that share the same redis store take a lock before running a job, so only one of them runs a job at a time,
+// and every run is recorded so an instance knows when a job has last been run by any of the instances.
+type Scheduler struct {
+	onepay      *OnePay
+	redisClient *redis.Client
+	instance    string
+	jobs        []*Job
+}
+
+// NewScheduler is a function that returns a new job scheduler
+func NewScheduler(onepay *OnePay, redisClient *redis.Client) *Scheduler {
+
+	// The instance name identifies the lock holder and the instance that has made a job run
+	hostname, _ := os.Hostname()
+	instance := hostname + "-" + tools.GenerateRandomString(8)
+
+	return &Scheduler{onepay: onepay, redisClient: redisClient, instance: instance}
+}
+
+// Register is a method that adds a new job to the scheduler, jobs should be registered before the scheduler is started
+func (scheduler *Scheduler) Register(name string, interval time.Duration, run func() error) {
+	scheduler.jobs = append(scheduler.jobs, &Job{Name: name, Interval: interval, Run: run})
+}
+
+// Start is a method that starts running each of the registered jobs in its own go routine
+func (scheduler *Scheduler) Start() {
+	for _, job := range scheduler.jobs {
+		go scheduler.schedule(job)
+	}
+}
+
+// schedule is a method that runs a job every time it becomes due
+func (scheduler *Scheduler) schedule(job *Job) {
+	for {
+		wait := scheduler.untilDue(job)
+		if wait > 0 {
+			time.Sleep(wait)
+			continue
+		}
+
+		if !scheduler.run(job) {
+			time.Sleep(JobRetryDelay)
+		}
+	}
+}
+
+// untilDue is a method that returns how long it is until a job becomes due, based on the latest run made by any instance
+func (scheduler *Scheduler) untilDue(job *Job) time.Duration {
+
+	lastRun, err := scheduler.onepay.JobService.LastJobRun(job.Name)
+	if err != nil {
+		return 0
+	}
+
+	return time.Until(lastRun.StartedAt.Add(job.Interval))
+}
+
+// run is a method that runs a job and records the run once the job's lock has been taken.
+// It returns false if the job couldn't be run, such as when another instance is running it.
+func (scheduler *Scheduler) run(job *Job) bool {
+
+	lockKey := entity.JobLockPrefix + job.Name
+	locked, err := tools.SetValueIfAbsent(scheduler.redisClient, lockKey, scheduler.instance, JobLockTimeout)
+	if err != nil || !locked {
+		return false
+	}
+	defer tools.RemoveValueIfEqual(scheduler.redisClient, lockKey, scheduler.instance)
+
+	// Another instance may have run the job after it has been checked
+	if scheduler.untilDue(job) > 0 {
+		return true
+	}
+
+	jobRun := new(entity.JobRun)
+	jobRun.JobName = job.Name
+	jobRun.Instance = scheduler.instance
+	jobRun.Status = entity.JobRunStatusRunning
+	jobRun.StartedAt = time.Now()
+
+	err = scheduler.onepay.JobService.AddJobRun(jobRun)
+	if err != nil {
+		return false
+	}
+
+	err = runJob(job)
+
+	finishedAt := time.Now()
+	jobRun.FinishedAt = &finishedAt
+	jobRun.Status = entity.JobRunStatusSucceeded
+	if err != nil {
+		jobRun.Status = entity.JobRunStatusFailed
+		jobRun.Error = err.Error()
+	}
+
+	scheduler.onepay.JobService.UpdateJobRun(jobRun)
+	return true
+}
+
+// runJob is a function that runs a job, turning a panic into an error so a failing job doesn't stop the server
+func runJob(job *Job) (err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job has panicked: %v", r)
+		}
+	}()
+
+	return job.Run()
+}
+
+// PruneJobRuns is a method that deletes the job runs that are older than the retention period.
+// The latest run of each job is kept, so the scheduler still knows when every job becomes due.
+func (onepay *OnePay) PruneJobRuns() error {
+	return onepay.JobService.PruneJobRuns(time.Now().Add(-JobRunRetention))
+}
@@ -2,82 +2,120 @@ package app
 
 import (
 	"errors"
-	"os"
-	"strconv"
 	"time"
 
-	"github.com/Benyam-S/onepay/logger"
 	"github.com/go-redis/redis"
 
 	"github.com/Benyam-S/onepay/entity"
 )
 
-// SendViaQRCode is a method that enables user to send money via qr code
-func (onepay *OnePay) SendViaQRCode(userID string, amount float64, redisClient *redis.Client) (*entity.MoneyToken, error) {
+// MaxMoneyTokenClaims is the largest number of users that can claim a single money token
+const MaxMoneyTokenClaims = 1000
 
-	if !AboveTransactionBaseLimit(amount) {
-		return nil, errors.New(entity.TransactionBaseLimitError)
+// MaxMoneyTokenAttempts is the number of times a wrong secret can be used on a money token before it gets locked
+const MaxMoneyTokenAttempts = 5
+
+// SendViaQRCode is a method that enables user to send money via qr code.
+// The money token can be claimed by up to maxClaims different users, in the fixed claim mode every claimant receives
+// the provided amount while in the split claim mode the provided amount is split between the claimants.
+// If a secret is provided the money token can only be claimed by users who know the secret.
+// The money token expires after the provided expiry, or after the default expiry if none is provided.
+func (onepay *OnePay) SendViaQRCode(userID string, amount entity.Money, maxClaims int, claimMode, secret string,
+	expiry time.Duration, redisClient *redis.Client) (*entity.MoneyToken, error) {
+
+	expiry, err := MoneyTokenExpiry(expiry)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxClaims < 1 || maxClaims > MaxMoneyTokenClaims {
+		return nil, errors.New(entity.InvalidMaxClaimsError)
 	}
 
-	if AboveDailyTransactionLimit(userID, amount, redisClient) {
-		return nil, errors.New(entity.DailyTransactionLimitError)
+	// The last claim of a split money token takes the minor units that couldn't be split equally
+	claimAmount, lastClaimAmount := amount, amount
+	switch claimMode {
+	case entity.MoneyTokenClaimFixed:
+		amount = entity.Money{Minor: amount.Minor * int64(maxClaims), Currency: amount.Currency}
+	case entity.MoneyTokenClaimSplit:
+		claimAmount = entity.Money{Minor: amount.Minor / int64(maxClaims), Currency: amount.Currency}
+		lastClaimAmount = entity.Money{Minor: amount.Minor - claimAmount.Minor*int64(maxClaims-1), Currency: amount.Currency}
+	default:
+		return nil, errors.New(entity.InvalidClaimModeError)
 	}
 
-	opWallet, err := onepay.WalletService.FindWallet(userID)
+	if !AboveTransactionBaseLimit(claimAmount) {
+		return nil, errors.New(entity.TransactionBaseLimitError)
+	}
+
+	claimFee, err := GetTransactionFee(entity.MethodTransactionQRCode, claimAmount)
 	if err != nil {
 		return nil, err
 	}
 
-	transactionFee := GetTransactionFee(amount)
-	if opWallet.Amount < amount+transactionFee {
-		return nil, errors.New(entity.InsufficientBalanceError)
+	lastClaimFee, err := GetTransactionFee(entity.MethodTransactionQRCode, lastClaimAmount)
+	if err != nil {
+		return nil, err
 	}
 
-	opWallet.Amount = opWallet.Amount - (amount + transactionFee)
-	err = onepay.WalletService.UpdateWallet(opWallet)
+	// The fee of every claim is locked together with the money token
+	transactionFee := entity.Money{Minor: claimFee.Minor * int64(maxClaims-1), Currency: claimFee.Currency}.Add(lastClaimFee)
+
+	releaseLimit, err := onepay.ReserveLimit(userID, entity.LimitSend, entity.MethodTransactionQRCode, amount, redisClient)
 	if err != nil {
 		return nil, err
 	}
 
 	moneyToken := new(entity.MoneyToken)
-	moneyToken.Amount = amount
-	moneyToken.Method = entity.MethodTransactionQRCode
-	moneyToken.SenderID = opWallet.UserID
-	moneyToken.SentAt = time.Now()
-
-	/* ++++ ++++ +++ checkpoint - money token ++++ ++++ +++ */
-	tempMoneyToken := new(entity.MoneyToken)
-	tempMoneyToken.Amount = amount
-	tempMoneyToken.Method = entity.MethodTransactionQRCode
-	tempMoneyToken.SenderID = opWallet.UserID
-	tempMoneyToken.SentAt = time.Now()
-	logger.Must(onepay.Logger.LogMoneyToken(tempMoneyToken))
-	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++ */
-
-	err = onepay.MoneyTokenService.AddMoneyToken(moneyToken)
-	if err != nil {
 
-		/* ++++++++++++++++++++++++++ Undo ++++++++++++++++++++++++++ */
-		opWallet.Amount = opWallet.Amount + (amount + transactionFee)
-		innerErr := onepay.WalletService.UpdateWallet(opWallet)
-		/* ++++++++++++++++++++++++++++++++++++++++++++++++++++++++++ */
+	err = onepay.RunInTransaction(func(tx *Transaction) error {
 
-		if innerErr != nil {
-			return nil, errors.New(entity.MoneyTokenCheckpointError)
+		opWallet, err := tx.WalletService.FindWallet(userID, amount.Currency)
+		if err != nil {
+			return errors.New(entity.InsufficientBalanceError)
 		}
 
-		/* +++++ ++++ ++++ ++++ checkpoint end ++++ ++++ ++++ +++++ */
-		logger.Must(onepay.Logger.RemoveMoneyToken(tempMoneyToken))
-		/* +++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
-		return nil, err
-	}
+		if opWallet.Amount.LessThan(amount.Add(transactionFee)) {
+			return errors.New(entity.InsufficientBalanceError)
+		}
+
+		err = tx.WalletService.DebitWallet(opWallet, amount.Add(transactionFee))
+		if err != nil {
+			return err
+		}
+
+		moneyToken = new(entity.MoneyToken)
+		moneyToken.Amount = amount
+		moneyToken.Fee = transactionFee
+		moneyToken.Method = entity.MethodTransactionQRCode
+		moneyToken.SenderID = opWallet.UserID
+		moneyToken.SentAt = time.Now()
+		moneyToken.ExpirationDate = moneyToken.SentAt.Add(expiry)
+		moneyToken.ClaimMode = claimMode
+		moneyToken.MaxClaims = maxClaims
+		moneyToken.Remaining = amount
+		moneyToken.RemainingFee = transactionFee
+
+		if secret != "" {
+			err = tx.MoneyTokenService.SetMoneyTokenSecret(moneyToken, secret)
+			if err != nil {
+				return err
+			}
+		}
 
-	/* +++++ ++++ ++++ ++++ checkpoint end ++++ ++++ ++++ +++++ */
-	logger.Must(onepay.Logger.RemoveMoneyToken(tempMoneyToken))
-	/* +++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
+		err = tx.MoneyTokenService.AddMoneyToken(moneyToken)
+		if err != nil {
+			return err
+		}
 
-	// Just updating the users daily transaction limit
-	AddToDailyTransaction(userID, amount, redisClient)
+		// Recording the money locked in the money token in the ledger
+		return tx.AddJournalEntry(entity.MethodTransactionQRCode, moneyToken.Code,
+			WalletPosting(userID, amount.Add(transactionFee).Neg()), MoneyTokenHoldingPosting(amount.Add(transactionFee)))
+	})
+	if err != nil {
+		releaseLimit()
+		return nil, err
+	}
 
 	return moneyToken, nil
 
@@ -85,83 +123,106 @@ func (onepay *OnePay) SendViaQRCode(userID string, amount float64, redisClient *
 
 // SendViaOnePayID is a method that enables user to send money via onepay id
 func (onepay *OnePay) SendViaOnePayID(senderID, receiverID string,
-	amount float64, redisClient *redis.Client) error {
+	amount entity.Money, redisClient *redis.Client) error {
 
 	if !AboveTransactionBaseLimit(amount) {
 		return errors.New(entity.TransactionBaseLimitError)
 	}
 
-	if AboveDailyTransactionLimit(senderID, amount, redisClient) {
-		return errors.New(entity.DailyTransactionLimitError)
-	}
-
 	if senderID == receiverID {
 		return errors.New(entity.TransactionWSelfError)
 	}
 
-	senderOPWallet, err := onepay.WalletService.FindWallet(senderID)
-	if err != nil {
-		return errors.New(entity.SenderNotFoundError)
+	// The revenue wallet only collects fees so it can't be used as a receiver
+	if receiverID == entity.RevenueWalletID {
+		return errors.New(entity.ReceiverNotFoundError)
 	}
 
-	receiverOPWallet, err := onepay.WalletService.FindWallet(receiverID)
+	transactionFee, err := GetTransactionFee(entity.MethodTransactionOnePayID, amount)
 	if err != nil {
-		return errors.New(entity.ReceiverNotFoundError)
+		return err
 	}
 
-	transactionFee, _ := strconv.ParseFloat(os.Getenv(entity.TransactionFee), 64)
-	if senderOPWallet.Amount < amount+transactionFee {
-		return errors.New(entity.InsufficientBalanceError)
+	releaseLimits, err := onepay.reserveTransferLimits(senderID, receiverID, entity.MethodTransactionOnePayID,
+		amount, redisClient)
+	if err != nil {
+		return err
 	}
 
-	senderOPWallet.Amount = senderOPWallet.Amount - (amount + transactionFee)
-	receiverOPWallet.Amount = receiverOPWallet.Amount + amount
-
-	err = onepay.WalletService.UpdateWallet(senderOPWallet)
+	err = onepay.RunInTransaction(func(tx *Transaction) error {
+		_, err := tx.TransferViaOnePayID(senderID, receiverID, amount, transactionFee)
+		return err
+	})
 	if err != nil {
+		releaseLimits()
 		return err
 	}
 
-	/* ++++ ++++ +++ checkpoint - wallet +++ ++++ ++++ */
-	tempOPWallet := new(entity.UserWallet)
-	tempOPWallet.UserID = receiverOPWallet.UserID
-	tempOPWallet.Amount = amount
-	logger.Must(onepay.Logger.LogWallet(tempOPWallet))
-	/* +++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++++ */
+	return nil
 
-	err = onepay.WalletService.UpdateWallet(receiverOPWallet)
-	if err != nil {
+}
+
+// TransferViaOnePayID is a method that moves money from the sender's wallet to the receiver's wallet as part of the transaction,
+// charging the sender the provided fee, and returns the history of the transfer.
+// The limits aren't checked here since they are checked by the callers before the transaction starts.
+func (tx *Transaction) TransferViaOnePayID(senderID, receiverID string,
+	amount, transactionFee entity.Money) (*entity.UserHistory, error) {
 
-		/* ++++++++++++++++++++++++++++++++ Undo +++++++++++++++++++++++++++++++ */
-		senderOPWallet.Amount = senderOPWallet.Amount + (amount + transactionFee)
-		innerErr := onepay.WalletService.UpdateWallet(senderOPWallet)
-		/* +++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++ */
+	// The sender can only send a currency it holds, while the receiver gets a new wallet for it if needed
+	if _, err := tx.WalletService.FindWallet(senderID, entity.BaseCurrency); err != nil {
+		return nil, errors.New(entity.SenderNotFoundError)
+	}
 
-		if innerErr != nil {
+	senderOPWallet, err := tx.WalletService.FindWallet(senderID, amount.Currency)
+	if err != nil {
+		return nil, errors.New(entity.InsufficientBalanceError)
+	}
 
-			// Adding history for the potential reload
-			onepay.AddUserHistory(senderID, receiverID, entity.MethodTransactionOnePayID, "",
-				amount, time.Now(), time.Now())
+	receiverOPWallet, err := tx.ReceivingWallet(receiverID, amount.Currency)
+	if err != nil {
+		return nil, errors.New(entity.ReceiverNotFoundError)
+	}
 
-			return errors.New(entity.WalletCheckpointError)
-		}
+	if senderOPWallet.Amount.LessThan(amount.Add(transactionFee)) {
+		return nil, errors.New(entity.InsufficientBalanceError)
+	}
 
-		/* +++++ +++++ +++++ checkpoint end +++++ +++++ +++++ */
-		logger.Must(onepay.Logger.RemoveWallet(tempOPWallet))
-		/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
+	err = tx.WalletService.DebitWallet(senderOPWallet, amount.Add(transactionFee))
+	if err != nil {
+		return nil, err
+	}
 
-		return err
+	err = tx.WalletService.CreditWallet(receiverOPWallet, amount)
+	if err != nil {
+		return nil, err
 	}
 
-	/* +++++ +++++ +++++ checkpoint end +++++ +++++ +++++ */
-	logger.Must(onepay.Logger.RemoveWallet(tempOPWallet))
-	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
+	// Recording the transaction in the ledger
+	err = tx.AddJournalEntry(entity.MethodTransactionOnePayID, "", WalletPosting(senderID, amount.Add(transactionFee).Neg()),
+		WalletPosting(receiverID, amount), RevenuePosting(transactionFee))
+	if err != nil {
+		return nil, err
+	}
 
-	// Just updating the users daily transaction limit
-	AddToDailyTransaction(senderID, amount, redisClient)
+	err = tx.CollectFee(transactionFee)
+	if err != nil {
+		return nil, err
+	}
 
 	// Adding history for the given transaction
-	return onepay.AddUserHistory(senderID, receiverID, entity.MethodTransactionOnePayID, "",
-		amount, time.Now(), time.Now())
+	opHistory := new(entity.UserHistory)
+	opHistory.SenderID = senderID
+	opHistory.ReceiverID = receiverID
+	opHistory.Method = entity.MethodTransactionOnePayID
+	opHistory.Amount = amount
+	opHistory.Fee = transactionFee
+	opHistory.SentAt = time.Now()
+	opHistory.ReceivedAt = time.Now()
+
+	err = tx.HistoryService.AddHistory(opHistory)
+	if err != nil {
+		return nil, err
+	}
 
+	return opHistory, nil
 }
@@ -0,0 +1,267 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/tools"
+)
+
+// SettlementDateTolerance is a constant that defines how far the settlement date of a statement line
+// can be from the time its recharge or withdrawal has been made
+const SettlementDateTolerance = time.Hour * 72
+
+// ImportSettlementStatement is a method that imports the settlement statement of an account provider and matches every line of it
+// with the recharge or withdrawal history of the same provider reference. Recharges and withdrawals made through the account provider
+// during the statement period that the statement leaves out are added to the statement as missing items.
+func (onepay *OnePay) ImportSettlementStatement(staffID, accountProviderID, fileName, format string,
+	file io.Reader) (*entity.SettlementStatement, error) {
+
+	accountProvider, err := onepay.AccountProviderService.FindAccountProvider(accountProviderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*entity.SettlementItem
+	switch strings.ToLower(format) {
+	case entity.SettlementFormatCSV:
+		items, err = tools.ParseSettlementCSV(file)
+	case entity.SettlementFormatFixedWidth:
+		items, err = tools.ParseSettlementFixedWidth(file)
+	default:
+		err = errors.New(entity.InvalidSettlementFormatError)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	statement := new(entity.SettlementStatement)
+	statement.AccountProviderID = accountProvider.ID
+	statement.FileName = fileName
+	statement.Format = strings.ToLower(format)
+	statement.ImportedBy = staffID
+	statement.LineCount = len(items)
+	statement.ImportedAt = time.Now()
+
+	// The statement period covers the whole days of the earliest and the latest settlement dates
+	statement.PeriodStart = items[0].SettledAt
+	statement.PeriodEnd = items[0].SettledAt
+	for _, item := range items {
+		if item.SettledAt.Before(statement.PeriodStart) {
+			statement.PeriodStart = item.SettledAt
+		}
+		if item.SettledAt.After(statement.PeriodEnd) {
+			statement.PeriodEnd = item.SettledAt
+		}
+	}
+	statement.PeriodStart = time.Date(statement.PeriodStart.Year(), statement.PeriodStart.Month(),
+		statement.PeriodStart.Day(), 0, 0, 0, 0, time.Local)
+	statement.PeriodEnd = time.Date(statement.PeriodEnd.Year(), statement.PeriodEnd.Month(),
+		statement.PeriodEnd.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+
+	err = onepay.SettlementService.AddStatement(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	// The references the account provider has been sent within reach of the statement period, per reference
+	providerEntries := onepay.providerJournalEntries(accountProvider.ID, items,
+		statement.PeriodStart.Add(-SettlementDateTolerance), statement.PeriodEnd.Add(SettlementDateTolerance))
+
+	settled := make(map[int]bool)
+	references := make(map[string]bool)
+	for _, item := range items {
+
+		item.StatementID = statement.ID
+		item.AccountProviderID = accountProvider.ID
+		references[item.Reference] = true
+
+		onepay.matchSettlementItem(item, providerEntries, settled)
+		if onepay.SettlementService.AddItem(item) != nil {
+			continue
+		}
+
+		switch item.Status {
+		case entity.SettlementStatusMatched:
+			statement.MatchedCount++
+		case entity.SettlementStatusMismatched:
+			statement.MismatchedCount++
+		case entity.SettlementStatusUnmatched:
+			statement.UnmatchedCount++
+		}
+	}
+
+	for _, journalEntry := range providerEntries {
+
+		if references[journalEntry.Code] || journalEntry.CreatedAt.Before(statement.PeriodStart) ||
+			!journalEntry.CreatedAt.Before(statement.PeriodEnd) {
+			continue
+		}
+
+		item := onepay.missingSettlementItem(statement, journalEntry)
+		if item != nil && onepay.SettlementService.AddItem(item) == nil {
+			statement.MissingCount++
+		}
+	}
+
+	err = onepay.SettlementService.UpdateStatement(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+// ResolveSettlementItem is a method that enables a staff member to close a settlement item that hasn't matched
+// once the difference has been sorted out with the account provider
+func (onepay *OnePay) ResolveSettlementItem(staffID string, itemID int64, note string) (*entity.SettlementItem, error) {
+
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, errors.New("resolution note can not be empty")
+	}
+
+	item, err := onepay.SettlementService.FindItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Status == entity.SettlementStatusMatched || item.Status == entity.SettlementStatusResolved {
+		return nil, errors.New(entity.SettlementItemClosedError)
+	}
+
+	resolvedAt := time.Now()
+	item.Status = entity.SettlementStatusResolved
+	item.Note = note
+	item.ResolvedBy = staffID
+	item.ResolvedAt = &resolvedAt
+
+	err = onepay.SettlementService.UpdateItem(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// providerJournalEntries is a method that returns the recharge and withdrawal journal entries that have moved money
+// through the clearing account of a certain account provider in the provided period, keyed by their provider reference.
+// Withdrawals that have been reversed since the account provider rejected them are left out as they never reach the provider.
+func (onepay *OnePay) providerJournalEntries(accountProviderID string, items []*entity.SettlementItem,
+	from, to time.Time) map[string]*entity.JournalEntry {
+
+	currencies := map[string]bool{entity.BaseCurrency: true}
+	for _, item := range items {
+		currencies[item.Amount.Currency] = true
+	}
+
+	providerEntries := make(map[string]*entity.JournalEntry)
+	for currency := range currencies {
+
+		clearingAccountID := tools.CurrencyLedgerAccountID(entity.LedgerAccountProviderClearing, accountProviderID, currency)
+		for _, journalEntry := range onepay.LedgerService.AccountJournalEntries(clearingAccountID, from, to) {
+			if journalEntry.Code == "" ||
+				(journalEntry.Method != entity.MethodRecharged && journalEntry.Method != entity.MethodWithdrawn) {
+				continue
+			}
+
+			if journalEntry.Method == entity.MethodWithdrawn {
+				if _, err := onepay.HistoryService.FindHistoryByCode(journalEntry.Code, entity.MethodReversal); err == nil {
+					continue
+				}
+			}
+
+			providerEntries[journalEntry.Code] = journalEntry
+		}
+	}
+
+	return providerEntries
+}
+
+// matchSettlementItem is a method that matches a settlement item with the history of its provider reference and
+// sets the status of the item. Every history can only be settled once, settled holds the histories settled by the current statement.
+func (onepay *OnePay) matchSettlementItem(item *entity.SettlementItem,
+	providerEntries map[string]*entity.JournalEntry, settled map[int]bool) {
+
+	opHistory, err := onepay.HistoryService.FindHistoryByCode(item.Reference, entity.MethodRecharged, entity.MethodWithdrawn)
+	if err != nil {
+		item.Status = entity.SettlementStatusUnmatched
+		item.Reason = "no recharge or withdrawal has been made with the reference"
+		return
+	}
+
+	item.HistoryID = opHistory.ID
+	item.Status = entity.SettlementStatusMismatched
+
+	previousItems := onepay.SettlementService.HistoryItems(int64(opHistory.ID))
+	alreadySettled := settled[opHistory.ID]
+	for _, previousItem := range previousItems {
+		if previousItem.Status != entity.SettlementStatusMissing {
+			alreadySettled = true
+		}
+	}
+
+	switch {
+	case alreadySettled:
+		item.Reason = "the reference has already been settled"
+	case item.Method != opHistory.Method:
+		item.Reason = fmt.Sprintf("settled as %s but recorded as %s", item.Method, opHistory.Method)
+	case item.Amount.Currency != opHistory.Amount.Currency || item.Amount.Cmp(opHistory.Amount) != 0:
+		item.Reason = fmt.Sprintf("settled amount %s %s doesn't match the recorded amount %s %s",
+			item.Amount, item.Amount.Currency, opHistory.Amount, opHistory.Amount.Currency)
+	case item.SettledAt.Before(opHistory.SentAt.Add(-SettlementDateTolerance)) ||
+		item.SettledAt.After(opHistory.SentAt.Add(SettlementDateTolerance)):
+		item.Reason = fmt.Sprintf("settled on %s but recorded on %s",
+			item.SettledAt.Format("2006-01-02"), opHistory.SentAt.Format("2006-01-02"))
+	case providerEntries[item.Reference] == nil:
+		item.Reason = "the reference hasn't been sent to the account provider"
+	default:
+		item.Status = entity.SettlementStatusMatched
+		item.Reason = ""
+	}
+
+	settled[opHistory.ID] = true
+
+	// A history that has been reported missing by an earlier statement is settled late by this one
+	for _, previousItem := range previousItems {
+		if previousItem.Status == entity.SettlementStatusMissing {
+			resolvedAt := time.Now()
+			previousItem.Status = entity.SettlementStatusResolved
+			previousItem.Note = fmt.Sprintf("settled by settlement statement %d", item.StatementID)
+			previousItem.ResolvedAt = &resolvedAt
+			onepay.SettlementService.UpdateItem(previousItem)
+		}
+	}
+}
+
+// missingSettlementItem is a method that returns a missing settlement item for a recharge or withdrawal journal entry
+// the statement has left out. Nil is returned if the history of the journal entry has already been settled or reported.
+func (onepay *OnePay) missingSettlementItem(statement *entity.SettlementStatement,
+	journalEntry *entity.JournalEntry) *entity.SettlementItem {
+
+	opHistory, err := onepay.HistoryService.FindHistoryByCode(journalEntry.Code, journalEntry.Method)
+	if err != nil {
+		return nil
+	}
+
+	if len(onepay.SettlementService.HistoryItems(int64(opHistory.ID))) > 0 {
+		return nil
+	}
+
+	item := new(entity.SettlementItem)
+	item.StatementID = statement.ID
+	item.AccountProviderID = statement.AccountProviderID
+	item.Reference = journalEntry.Code
+	item.Method = opHistory.Method
+	item.Amount = opHistory.Amount
+	item.SettledAt = opHistory.SentAt
+	item.HistoryID = opHistory.ID
+	item.Status = entity.SettlementStatusMissing
+	item.Reason = fmt.Sprintf("recorded on %s but missing from the statement", opHistory.SentAt.Format("2006-01-02"))
+
+	return item
+}
@@ -1,7 +1,8 @@
 package app
 
 import (
-	"fmt"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -9,57 +10,213 @@ import (
 
 	"github.com/Benyam-S/onepay/entity"
 	"github.com/Benyam-S/onepay/tools"
-	"github.com/go-redis/redis"
 )
 
-// GetTransactionFee is a function that returns the appropriate transaction fee for the provided amount
-func GetTransactionFee(amount float64) float64 {
+// ConfigMoney is a function that reads a money value stored in the environment by the provided name
+func ConfigMoney(name string) entity.Money {
 
-	transactionFee, _ := strconv.ParseFloat(os.Getenv(entity.TransactionFee), 64)
-	return transactionFee
+	value, err := entity.ParseMoney(os.Getenv(name), entity.BaseCurrency)
+	if err != nil {
+		return entity.NewMoney(0, entity.BaseCurrency)
+	}
+	return value
 }
 
-// AboveTransactionBaseLimit is a function that checks if the provided amount is above the transaction base limit
-func AboveTransactionBaseLimit(amount float64) bool {
+// FeeSchedules is a function that returns the fee schedules stored in the environment mapped by their method
+func FeeSchedules() map[string]*entity.FeeSchedule {
+
+	feeSchedules := make(map[string]*entity.FeeSchedule)
+
+	var schedules []*entity.FeeSchedule
+	err := json.Unmarshal([]byte(os.Getenv(entity.FeeSchedules)), &schedules)
+	if err != nil {
+		return feeSchedules
+	}
 
-	transactionBaseLimit, _ := strconv.ParseFloat(os.Getenv(entity.TransactionBaseLimit), 64)
-	if amount >= transactionBaseLimit {
+	for _, schedule := range schedules {
+		feeSchedules[schedule.Method] = schedule
+	}
+
+	return feeSchedules
+}
+
+// QRSigningKeys is a function that returns the qr signing keys stored in the environment, the first key is used for signing
+// while the others are kept so qr codes signed before the keys have been rotated can still be checked
+func QRSigningKeys() []*entity.QRSigningKey {
+
+	var keys []*entity.QRSigningKey
+	err := json.Unmarshal([]byte(os.Getenv(entity.QRSigningKeys)), &keys)
+	if err != nil {
+		return nil
+	}
+
+	return keys
+}
+
+// UnsignedQRCodesAccepted is a function that checks whether scanned values without a signed envelope can be used.
+// They are accepted if no qr signing keys have been configured or if accepting them has been allowed explicitly.
+func UnsignedQRCodesAccepted() bool {
+
+	if len(QRSigningKeys()) == 0 {
 		return true
 	}
-	return false
+
+	accepted, _ := strconv.ParseBool(os.Getenv(entity.QRAcceptUnsignedCodes))
+	return accepted
+}
+
+// MoneyTokenExpiryBounds is a function that returns the shortest and longest expiry a sender can pick for a money token.
+// Bounds that haven't been configured fall back to the default bounds.
+func MoneyTokenExpiryBounds() (time.Duration, time.Duration) {
+
+	minExpiry, maxExpiry := DefaultMinMoneyTokenExpiry, DefaultMaxMoneyTokenExpiry
+
+	minHours, err := strconv.ParseFloat(os.Getenv(entity.MoneyTokenMinExpiry), 64)
+	if err == nil && minHours > 0 {
+		minExpiry = time.Duration(minHours * float64(time.Hour))
+	}
+
+	maxHours, err := strconv.ParseFloat(os.Getenv(entity.MoneyTokenMaxExpiry), 64)
+	if err == nil && maxHours > 0 {
+		maxExpiry = time.Duration(maxHours * float64(time.Hour))
+	}
+
+	return minExpiry, maxExpiry
+}
+
+// GetTransactionFee is a function that returns the appropriate fee of the provided method for the provided amount.
+// The fee is calculated on the base currency value of the amount and converted back to the currency of the amount.
+func GetTransactionFee(method string, amount entity.Money) (entity.Money, error) {
+
+	baseAmount, err := ToBaseCurrency(amount)
+	if err != nil {
+		return entity.Money{}, err
+	}
+
+	return FromBaseCurrency(baseTransactionFee(method, baseAmount), amount.Currency)
+}
+
+// baseTransactionFee is a function that returns the fee of the provided method for a base currency amount.
+// Methods without a fee schedule are charged the flat transaction fee if they are transactions and nothing otherwise.
+func baseTransactionFee(method string, amount entity.Money) entity.Money {
+
+	schedule, ok := FeeSchedules()[method]
+	if ok {
+		return schedule.Fee(amount)
+	}
+
+	switch method {
+	case entity.MethodTransactionQRCode, entity.MethodTransactionOnePayID, entity.MethodPaymentQRCode,
+		entity.MethodHoldCapture:
+		return entity.NewMoney(ConfigMoney(entity.TransactionFee).Minor, amount.Currency)
+	}
+
+	return entity.NewMoney(0, amount.Currency)
+}
+
+// AboveTransactionBaseLimit is a function that checks if the provided amount is above the transaction base limit
+func AboveTransactionBaseLimit(amount entity.Money) bool {
+
+	baseAmount, err := ToBaseCurrency(amount)
+	if err != nil {
+		return false
+	}
+	return !baseAmount.LessThan(ConfigMoney(entity.TransactionBaseLimit))
 }
 
 // AboveWithdrawBaseLimit is a function that checks if the provided amount is above the withdraw base limit
-func AboveWithdrawBaseLimit(amount float64) bool {
+func AboveWithdrawBaseLimit(amount entity.Money) bool {
 
-	withdrawBaseLimit, _ := strconv.ParseFloat(os.Getenv(entity.WithdrawBaseLimit), 64)
-	if amount >= withdrawBaseLimit {
-		return true
+	baseAmount, err := ToBaseCurrency(amount)
+	if err != nil {
+		return false
 	}
-	return false
+	return !baseAmount.LessThan(ConfigMoney(entity.WithdrawBaseLimit))
 }
 
-// AboveDailyTransactionLimit is a method that checks wheather a user has exceeded the daily transaction limit
-func AboveDailyTransactionLimit(userID string, amount float64, redisClient *redis.Client) bool {
+// LimitProfiles is a function that returns the limit profiles stored in the environment
+func LimitProfiles() []*entity.LimitProfile {
 
-	prevAmountString, _ := tools.GetValue(redisClient, "daily_transaction_limit:"+userID)
-	prevAmount, _ := strconv.ParseFloat(prevAmountString, 64)
-	dailyTransactionLimit, _ := strconv.ParseFloat(os.Getenv(entity.DailyTransactionLimit), 64)
-	if (prevAmount + amount) > dailyTransactionLimit {
-		return true
+	var profiles []*entity.LimitProfile
+	err := json.Unmarshal([]byte(os.Getenv(entity.LimitProfiles)), &profiles)
+	if err != nil {
+		return nil
 	}
 
-	return false
+	return profiles
+}
+
+// DefaultLimitProfile is a function that returns the limit profile of users whose tier has no limit profile,
+// it only limits the daily send amount using the global daily send limit
+func DefaultLimitProfile() *entity.LimitProfile {
+
+	profile := new(entity.LimitProfile)
+	profile.Name = "default"
+	profile.Send = &entity.LimitSet{Daily: ConfigMoney(entity.DailyTransactionLimit)}
+	return profile
 }
 
-// AddToDailyTransaction is a method that adds a certain amount to a user daily transaction limit
-func AddToDailyTransaction(userID string, amount float64, redisClient *redis.Client) error {
+// CheckMaxBalance is a method that checks whether a certain user's wallets can take the provided amount
+// without exceeding the maximum wallet balance of the user's limit profile
+func (onepay *OnePay) CheckMaxBalance(userID string, amount entity.Money) error {
 
-	prevAmountString, _ := tools.GetValue(redisClient, "daily_transaction_limit:"+userID)
-	prevAmount, _ := strconv.ParseFloat(prevAmountString, 64)
-	currentAmountString := fmt.Sprintf("%f", prevAmount+amount)
+	baseAmount, err := ToBaseCurrency(amount)
+	if err != nil {
+		return err
+	}
+
+	return onepay.checkMaxBalance(userID, onepay.UserLimitProfile(userID), baseAmount)
+}
+
+// checkMaxBalance is a method that checks the base currency value of the user's wallets, held amounts included,
+// together with the provided base currency amount against the maximum wallet balance of the profile
+func (onepay *OnePay) checkMaxBalance(userID string, profile *entity.LimitProfile, baseAmount entity.Money) error {
+
+	if !profile.MaxBalance.IsPositive() {
+		return nil
+	}
+
+	balance := baseAmount
+	for _, opWallet := range onepay.WalletService.SearchWallets(userID) {
+		baseBalance, err := ToBaseCurrency(opWallet.Amount.Add(opWallet.Held))
+		if err != nil {
+			return err
+		}
+		balance = balance.Add(baseBalance)
+	}
+
+	if aboveLimit(balance, profile.MaxBalance) {
+		return errors.New(entity.MaxBalanceError)
+	}
+
+	return nil
+}
+
+// aboveLimit is a function that checks if the provided amount is above a limit, a zero limit is never exceeded
+func aboveLimit(amount, limit entity.Money) bool {
+	return limit.IsPositive() && amount.GreaterThan(limit)
+}
+
+// limitError is a function that returns the error of an exceeded limit, an empty period refers to the per transaction limit
+func limitError(kind, period string) error {
+
+	switch kind {
+	case entity.LimitReceive:
+		return errors.New(entity.ReceiveLimitError)
+	case entity.LimitWithdraw:
+		return errors.New(entity.WithdrawLimitError)
+	}
+
+	switch period {
+	case entity.LimitDaily:
+		return errors.New(entity.DailyTransactionLimitError)
+	case entity.LimitWeekly:
+		return errors.New(entity.WeeklyTransactionLimitError)
+	case entity.LimitMonthly:
+		return errors.New(entity.MonthlyTransactionLimitError)
+	}
 
-	return tools.SetValue(redisClient, "daily_transaction_limit:"+userID, currentAmountString, time.Hour*24)
+	return errors.New(entity.TransactionLimitError)
 }
 
 // ClosingStatement is a function that generates a file that contain a user histories and linked account information
@@ -110,7 +267,8 @@ func ClosingStatement(opUser *entity.User, histories []*entity.UserHistory,
 	for _, history := range histories {
 		line := "Sender ID: " + history.SenderID + "		Receiver ID: " + history.ReceiverID +
 			"	Sent At: " + history.SentAt.String() + "	Received At: " + history.ReceivedAt.String() +
-			"	Method: " + history.Method + "	Amount:" + strconv.FormatFloat(history.Amount, 'f', 2, 64) + "\n"
+			"	Method: " + history.Method + "	Amount:" + history.Amount.String() + " " + history.Amount.Currency +
+			"	Fee:" + history.Fee.String() + " " + history.Fee.Currency + "\n"
 
 		_, err = file.WriteString(line)
 		if err != nil {
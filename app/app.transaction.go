@@ -0,0 +1,198 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Benyam-S/onepay/dispute"
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/history"
+	"github.com/Benyam-S/onepay/hold"
+	"github.com/Benyam-S/onepay/ledger"
+	"github.com/Benyam-S/onepay/moneytoken"
+	"github.com/Benyam-S/onepay/paymentrequest"
+	"github.com/Benyam-S/onepay/unitofwork"
+	"github.com/Benyam-S/onepay/wallet"
+)
+
+// MaxTransactionAttempts is a constant that defines how many times an operation is tried
+// when it keeps failing because of concurrent wallet changes
+const MaxTransactionAttempts = 5
+
+// Transaction is a type that groups the onepay services that take part in a single database transaction.
+// Every change made through a transaction is either committed together or rolled back together.
+type Transaction struct {
+	uow                   *unitofwork.UnitOfWork
+	WalletService         wallet.IService
+	HistoryService        history.IService
+	MoneyTokenService     moneytoken.IService
+	LedgerService         ledger.IService
+	DisputeService        dispute.IService
+	PaymentRequestService paymentrequest.IService
+	HoldService           hold.IService
+}
+
+// BeginTransaction is a method that starts a new transaction with services bound to it
+func (onepay *OnePay) BeginTransaction() (*Transaction, error) {
+
+	uow, err := onepay.UnitOfWorkManager.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{uow: uow,
+		WalletService:         onepay.WalletService.WithUnitOfWork(uow),
+		HistoryService:        onepay.HistoryService.WithUnitOfWork(uow),
+		MoneyTokenService:     onepay.MoneyTokenService.WithUnitOfWork(uow),
+		LedgerService:         onepay.LedgerService.WithUnitOfWork(uow),
+		DisputeService:        onepay.DisputeService.WithUnitOfWork(uow),
+		PaymentRequestService: onepay.PaymentRequestService.WithUnitOfWork(uow),
+		HoldService:           onepay.HoldService.WithUnitOfWork(uow)}, nil
+}
+
+// PrepareTransaction is a method that runs the provided operation inside a new transaction and returns the still open transaction.
+// If the operation fails because a wallet has been changed concurrently, it is retried with a fresh transaction
+// so the wallets are read again. The caller is responsible for committing or rolling back the returned transaction.
+func (onepay *OnePay) PrepareTransaction(operation func(tx *Transaction) error) (*Transaction, error) {
+
+	var err error
+	for attempt := 1; attempt <= MaxTransactionAttempts; attempt++ {
+
+		var tx *Transaction
+		tx, err = onepay.BeginTransaction()
+		if err != nil {
+			return nil, err
+		}
+
+		err = operation(tx)
+		if err == nil {
+			return tx, nil
+		}
+
+		tx.Rollback()
+		if err.Error() != entity.WalletConflictError {
+			return nil, err
+		}
+
+		// Backing off a little so the competing operation can finish
+		time.Sleep(time.Duration(attempt*attempt) * 10 * time.Millisecond)
+	}
+
+	return nil, err
+}
+
+// RunInTransaction is a method that runs the provided operation inside a transaction and commits it,
+// retrying the operation if it fails because of concurrent wallet changes
+func (onepay *OnePay) RunInTransaction(operation func(tx *Transaction) error) error {
+
+	tx, err := onepay.PrepareTransaction(operation)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	return tx.Commit()
+}
+
+// ReceivingWallet is a method that finds a user's wallet of the provided currency as part of the transaction.
+// If the user doesn't hold the currency yet a new empty wallet is opened, but only for users that have a base currency wallet.
+func (tx *Transaction) ReceivingWallet(userID, currency string) (*entity.UserWallet, error) {
+
+	opWallet, err := tx.WalletService.FindWallet(userID, currency)
+	if err == nil {
+		return opWallet, nil
+	}
+
+	_, err = tx.WalletService.FindWallet(userID, entity.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	opWallet = new(entity.UserWallet)
+	opWallet.UserID = userID
+	opWallet.Currency = currency
+	opWallet.Amount = entity.NewMoney(0, currency)
+
+	err = tx.WalletService.AddWallet(opWallet)
+	if err != nil {
+		return nil, err
+	}
+
+	return opWallet, nil
+}
+
+// CollectFee is a method that credits the provided fee to the system revenue wallet of the fee currency as part of the transaction
+func (tx *Transaction) CollectFee(fee entity.Money) error {
+
+	if !fee.IsPositive() {
+		return nil
+	}
+
+	revenueWallet, err := tx.ReceivingWallet(entity.RevenueWalletID, fee.Currency)
+	if err != nil {
+		return err
+	}
+
+	return tx.WalletService.CreditWallet(revenueWallet, fee)
+}
+
+// ReturnFee is a method that takes a collected fee back from the system revenue wallet of the fee currency as part of the transaction
+func (tx *Transaction) ReturnFee(fee entity.Money) error {
+
+	if !fee.IsPositive() {
+		return nil
+	}
+
+	revenueWallet, err := tx.WalletService.FindWallet(entity.RevenueWalletID, fee.Currency)
+	if err != nil {
+		return err
+	}
+
+	return tx.WalletService.DebitWallet(revenueWallet, fee)
+}
+
+// AddUserHistory is a method that adds a user history as part of the transaction
+func (tx *Transaction) AddUserHistory(senderID, receiverID, method, code string,
+	amount, fee entity.Money, sentAt, receivedAt time.Time) error {
+
+	opHistory := new(entity.UserHistory)
+	opHistory.Amount = amount
+	opHistory.Fee = fee
+	opHistory.Code = code
+	opHistory.Method = method
+	opHistory.SentAt = sentAt
+	opHistory.ReceivedAt = receivedAt
+	opHistory.SenderID = senderID
+	opHistory.ReceiverID = receiverID
+
+	return tx.HistoryService.AddHistory(opHistory)
+}
+
+// AddJournalEntry is a method that records a balanced set of postings in the ledger as part of the transaction
+func (tx *Transaction) AddJournalEntry(method, code string, postings ...*entity.Posting) error {
+
+	journalEntry := new(entity.JournalEntry)
+	journalEntry.Method = method
+	journalEntry.Code = code
+	journalEntry.Postings = postings
+	journalEntry.CreatedAt = time.Now()
+
+	return tx.LedgerService.PostJournalEntry(journalEntry)
+}
+
+// Commit is a method that commits all the changes made through the transaction
+func (tx *Transaction) Commit() error {
+
+	err := tx.uow.Commit()
+	if err != nil {
+		return errors.New(entity.TransactionCommitError)
+	}
+
+	return nil
+}
+
+// Rollback is a method that discards all the changes made through the transaction.
+// It does nothing if the transaction has already been committed so it can safely be deferred.
+func (tx *Transaction) Rollback() {
+	tx.uow.Rollback()
+}
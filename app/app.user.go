@@ -11,18 +11,41 @@ import (
 func (onepay *OnePay) InitDeleteOnePayAccount(userID string) ([]*entity.UserHistory,
 	[]*entity.LinkedAccount, error) {
 
-	opWallet, err := onepay.WalletService.FindWallet(userID)
-	if err != nil {
-		return nil, nil, err
+	opWallets := onepay.WalletService.SearchWallets(userID)
+	if len(opWallets) == 0 {
+		return nil, nil, errors.New("user wallet not found")
 	}
 
-	// checking first if the user wallet is empty
-	if opWallet.Amount > 0 {
-		return nil, nil, errors.New("please empty your wallet before deleting account")
+	// checking first if all the user wallets are empty, including the money that is held
+	for _, opWallet := range opWallets {
+		if opWallet.Amount.IsPositive() || opWallet.Held.IsPositive() {
+			return nil, nil, errors.New("please empty your wallets before deleting account")
+		}
+	}
+
+	// checking if the user has any holds that haven't been captured, voided or expired, either as the user or as the merchant
+	for _, opHold := range onepay.HoldService.SearchHolds(userID) {
+		if opHold.Status == entity.HoldStatusAuthorized {
+			return nil, nil, errors.New("please wait for all holds to be completed before deleting account")
+		}
+	}
+
+	// checking if the user takes part in any dispute that hasn't been resolved yet
+	for _, opDispute := range onepay.DisputeService.SearchDisputes(userID) {
+		if opDispute.IsActive() {
+			return nil, nil, errors.New("please wait for all disputes to be resolved before deleting account")
+		}
+	}
+
+	// checking if the user has any scheduled transfers that will still be made
+	for _, transfer := range onepay.ScheduledTransferService.SearchScheduledTransfers(userID) {
+		if transfer.Status == entity.ScheduledTransferStatusActive || transfer.Status == entity.ScheduledTransferStatusRunning {
+			return nil, nil, errors.New("please cancel all scheduled transfers before deleting account")
+		}
 	}
 
 	// checking first if the user have any money token's that hasn't been reclaim
-	moneyTokens := onepay.MoneyTokenService.SearchMoneyToken(userID)
+	moneyTokens := onepay.MoneyTokenService.SearchMoneyToken(userID, entity.MoneyTokenStatusActive)
 	if len(moneyTokens) > 0 {
 		return nil, nil, errors.New("please delete or reclaim all money tokens that has not been received before deleting account")
 	}
@@ -4,26 +4,27 @@ import (
 	"errors"
 	"time"
 
+	"github.com/go-redis/redis"
+
 	"github.com/Benyam-S/onepay/middleman"
 
 	"github.com/Benyam-S/onepay/entity"
 	"github.com/Benyam-S/onepay/logger"
+	"github.com/Benyam-S/onepay/tools"
 )
 
 // DrainWallet is a method that drains all the cash out your wallet
-func (onepay *OnePay) DrainWallet(userID, linkedAccountID string) error {
+func (onepay *OnePay) DrainWallet(userID, linkedAccountID string, redisClient *redis.Client) error {
 
-	opWallet, err := onepay.WalletService.FindWallet(userID)
+	// Linked accounts are only refilled from the base currency wallet
+	opWallet, err := onepay.WalletService.FindWallet(userID, entity.BaseCurrency)
 	if err != nil {
 		return err
 	}
 
-	amount := opWallet.Amount
-	if amount <= 0 {
+	if !opWallet.Amount.IsPositive() {
 		return errors.New("can not drain empty wallet")
 	}
-	// draining the account
-	opWallet.Amount = 0
 
 	linkedAccount, err := onepay.LinkedAccountService.FindLinkedAccount(linkedAccountID)
 	if err != nil {
@@ -34,49 +35,38 @@ func (onepay *OnePay) DrainWallet(userID, linkedAccountID string) error {
 		return errors.New("linked account doesn't belong to the provided user")
 	}
 
-	err = onepay.WalletService.UpdateWallet(opWallet)
+	// The balance read here is the one drained, so the reserved limit always matches the amount moved
+	drained := opWallet.Amount
+	fee, err := GetTransactionFee(entity.MethodWithdrawn, drained)
 	if err != nil {
 		return err
 	}
 
-	/* ++++ ++++ +++ checkpoint - wallet +++ ++++ ++++ */
-	tempOPWallet := new(entity.UserWallet)
-	tempOPWallet.UserID = opWallet.UserID
-	tempOPWallet.Amount = amount
-	logger.Must(onepay.Logger.LogWallet(tempOPWallet))
-	/* +++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++++ */
+	if !drained.GreaterThan(fee) {
+		return errors.New(entity.FeeExceedsAmountError)
+	}
 
-	err = middleman.RefillAccount(linkedAccount.AccountID, linkedAccount.AccessToken, amount)
+	releaseLimit, err := onepay.ReserveLimit(userID, entity.LimitWithdraw, entity.MethodWithdrawn,
+		drained.Sub(fee), redisClient)
 	if err != nil {
+		return err
+	}
 
-		/* +++++++++++++++++++++++ Undo +++++++++++++++++++++++ */
-		opWallet.Amount += amount
-		innerErr := onepay.WalletService.UpdateWallet(opWallet)
-		/* ++++++++++++++++++++++++++++++++++++++++++++++++++++ */
-
-		if innerErr != nil {
-
-			// Adding history for the potential reload
-			onepay.AddUserHistory(userID, linkedAccount.AccountID, entity.MethodWithdrawn, "",
-				amount, time.Now(), time.Now())
-
-			return errors.New(entity.WalletCheckpointError)
+	// draining the account, the withdraw fee is deducted from the drained amount and the
+	// drain fails if the wallet has been credited or debited since its balance was read
+	err = onepay.refillLinkedAccount(userID, linkedAccount, func(opWallet *entity.UserWallet) (entity.Money, entity.Money, error) {
+		if opWallet.Amount.Cmp(drained) != 0 {
+			return entity.Money{}, entity.Money{}, errors.New(entity.WalletBalanceChangedError)
 		}
+		return drained.Sub(fee), fee, nil
+	})
 
-		/* +++++ +++++ +++++ checkpoint end +++++ +++++ +++++ */
-		logger.Must(onepay.Logger.RemoveWallet(tempOPWallet))
-		/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
-
-		return err
+	// The money only leaves once the account provider has accepted the refill, a rejected refill is reversed
+	if err != nil {
+		releaseLimit()
 	}
 
-	/* +++++ +++++ +++++ checkpoint end +++++ +++++ +++++ */
-	logger.Must(onepay.Logger.RemoveWallet(tempOPWallet))
-	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
-
-	// Adding history for the withdrawal process
-	return onepay.AddUserHistory(userID, linkedAccount.AccountID, entity.MethodWithdrawn, "",
-		amount, time.Now(), time.Now())
+	return err
 }
 
 // MarkWalletAsViewed is a method that marks the wallet change as viewed
@@ -92,10 +82,11 @@ func (onepay *OnePay) MarkWalletAsViewed(userID string) error {
 
 // AddUserHistory is a method that add user history for the onepay app methods
 func (onepay *OnePay) AddUserHistory(senderID, receiverID, method, code string,
-	amount float64, sentAt, receivedAt time.Time) error {
+	amount, fee entity.Money, sentAt, receivedAt time.Time) error {
 
 	opHistory := new(entity.UserHistory)
 	opHistory.Amount = amount
+	opHistory.Fee = fee
 	opHistory.Code = code
 	opHistory.Method = method
 	opHistory.SentAt = sentAt
@@ -103,16 +94,16 @@ func (onepay *OnePay) AddUserHistory(senderID, receiverID, method, code string,
 	opHistory.SenderID = senderID
 	opHistory.ReceiverID = receiverID
 
+	return onepay.addCheckpointedHistory(opHistory)
+}
+
+// addCheckpointedHistory is a method that adds the provided history, logging it first so it can be reloaded if adding it fails
+func (onepay *OnePay) addCheckpointedHistory(opHistory *entity.UserHistory) error {
+
 	/* +++++ +++++ checkpoint - history +++++ ++++++ */
 	// tempHistory is created because the .AddHistory() method wil change some value's of the opHistory object
 	tempHistory := new(entity.UserHistory)
-	tempHistory.Amount = amount
-	tempHistory.Code = code
-	tempHistory.Method = method
-	tempHistory.SentAt = sentAt
-	tempHistory.ReceivedAt = receivedAt
-	tempHistory.SenderID = senderID
-	tempHistory.ReceiverID = receiverID
+	*tempHistory = *opHistory
 	logger.Must(onepay.Logger.LogHistory(tempHistory))
 	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
 
@@ -129,9 +120,9 @@ func (onepay *OnePay) AddUserHistory(senderID, receiverID, method, code string,
 }
 
 // RechargeWallet is a method that recharges user's wallet from external account
-func (onepay *OnePay) RechargeWallet(userID, linkedAccountID string, amount float64) error {
+func (onepay *OnePay) RechargeWallet(userID, linkedAccountID string, amount entity.Money) error {
 
-	opWallet, err := onepay.WalletService.FindWallet(userID)
+	opWallet, err := onepay.WalletService.FindWallet(userID, entity.BaseCurrency)
 	if err != nil {
 		return errors.New("user wallet not found")
 	}
@@ -151,47 +142,100 @@ func (onepay *OnePay) RechargeWallet(userID, linkedAccountID string, amount floa
 		return err
 	}
 
-	if accountInfo.Amount < amount {
+	if accountInfo.Amount.LessThan(amount) {
 		return errors.New("insufficient balance, please recharge your linked account")
 	}
 
-	err = middleman.WithdrawFromAccount(linkedAccount.AccountID, linkedAccount.AccessToken, amount)
+	// The recharge fee is deducted from the amount that reaches the wallet
+	fee, err := GetTransactionFee(entity.MethodRecharged, amount)
+	if err != nil {
+		return err
+	}
+
+	if !amount.GreaterThan(fee) {
+		return errors.New(entity.FeeExceedsAmountError)
+	}
+
+	err = onepay.CheckMaxBalance(userID, amount.Sub(fee))
 	if err != nil {
 		return err
 	}
 
-	opWallet.Amount += amount
+	// The reference is sent to the account provider so the recharge can be matched with its settlement statement
+	reference := tools.GenerateProviderReference()
+	err = middleman.WithdrawFromAccount(linkedAccount.AccountID, linkedAccount.AccessToken, reference, amount)
+	if err != nil {
+		return err
+	}
 
 	/* ++++ ++++ +++ checkpoint - wallet +++ ++++ ++++ */
 	tempOPWallet := new(entity.UserWallet)
 	tempOPWallet.UserID = opWallet.UserID
-	tempOPWallet.Amount = amount
+	tempOPWallet.Amount = amount.Sub(fee)
 	logger.Must(onepay.Logger.LogWallet(tempOPWallet))
+
+	tempRevenueWallet := new(entity.UserWallet)
+	tempRevenueWallet.UserID = entity.RevenueWalletID
+	tempRevenueWallet.Amount = fee
+	if fee.IsPositive() {
+		logger.Must(onepay.Logger.LogWallet(tempRevenueWallet))
+	}
 	/* +++++ +++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
 
-	err = onepay.WalletService.UpdateWallet(opWallet)
+	err = onepay.RunInTransaction(func(tx *Transaction) error {
+
+		opWallet, err := tx.WalletService.FindWallet(userID, entity.BaseCurrency)
+		if err != nil {
+			return err
+		}
+
+		err = tx.WalletService.CreditWallet(opWallet, amount.Sub(fee))
+		if err != nil {
+			return err
+		}
+
+		err = tx.CollectFee(fee)
+		if err != nil {
+			return err
+		}
+
+		// Recording the recharge in the ledger
+		err = tx.AddJournalEntry(entity.MethodRecharged, reference, ClearingPosting(linkedAccount.AccountProviderID, amount.Neg()),
+			WalletPosting(userID, amount.Sub(fee)), RevenuePosting(fee))
+		if err != nil {
+			return err
+		}
+
+		// Adding history for the recharging process
+		return tx.AddUserHistory(linkedAccount.AccountID, userID, entity.MethodRecharged, reference,
+			amount, fee, time.Now(), time.Now())
+	})
 	if err != nil {
 
-		// Adding history for the potential reload
-		onepay.AddUserHistory(linkedAccount.AccountID, userID, entity.MethodRecharged, "",
-			amount, time.Now(), time.Now())
+		// Adding history and journal entry for the potential reload
+		onepay.AddUserHistory(linkedAccount.AccountID, userID, entity.MethodRecharged, reference,
+			amount, fee, time.Now(), time.Now())
+		onepay.AddJournalEntry(entity.MethodRecharged, reference, ClearingPosting(linkedAccount.AccountProviderID, amount.Neg()),
+			WalletPosting(userID, amount.Sub(fee)), RevenuePosting(fee))
 
 		return errors.New(entity.WalletCheckpointError)
 	}
 
 	/* +++++ +++++ +++++ checkpoint end +++++ +++++ +++++ */
 	logger.Must(onepay.Logger.RemoveWallet(tempOPWallet))
+	if fee.IsPositive() {
+		logger.Must(onepay.Logger.RemoveWallet(tempRevenueWallet))
+	}
 	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
 
-	// Adding history for the recharging process
-	return onepay.AddUserHistory(linkedAccount.AccountID, userID, entity.MethodRecharged, "",
-		amount, time.Now(), time.Now())
+	return nil
 }
 
 // WithdrawFromWallet is a method that enables user's to withdraw money from onepay account/wallet
-func (onepay *OnePay) WithdrawFromWallet(userID, linkedAccountID string, amount float64) error {
+func (onepay *OnePay) WithdrawFromWallet(userID, linkedAccountID string, amount entity.Money,
+	redisClient *redis.Client) error {
 
-	opWallet, err := onepay.WalletService.FindWallet(userID)
+	opWallet, err := onepay.WalletService.FindWallet(userID, entity.BaseCurrency)
 	if err != nil {
 		return errors.New("user wallet not found")
 	}
@@ -209,53 +253,187 @@ func (onepay *OnePay) WithdrawFromWallet(userID, linkedAccountID string, amount
 		return errors.New(entity.WithdrawBaseLimitError)
 	}
 
-	if opWallet.Amount < amount {
+	// The withdraw fee is charged on top of the withdrawn amount
+	fee, err := GetTransactionFee(entity.MethodWithdrawn, amount)
+	if err != nil {
+		return err
+	}
+
+	if opWallet.Amount.LessThan(amount.Add(fee)) {
 		return errors.New(entity.InsufficientBalanceError)
 	}
 
-	opWallet.Amount -= amount
+	releaseLimit, err := onepay.ReserveLimit(userID, entity.LimitWithdraw, entity.MethodWithdrawn, amount, redisClient)
+	if err != nil {
+		return err
+	}
+
+	err = onepay.refillLinkedAccount(userID, linkedAccount, func(opWallet *entity.UserWallet) (entity.Money, entity.Money, error) {
+		if opWallet.Amount.LessThan(amount.Add(fee)) {
+			return entity.Money{}, entity.Money{}, errors.New(entity.InsufficientBalanceError)
+		}
+		return amount, fee, nil
+	})
+
+	// The money only leaves once the account provider has accepted the refill, a rejected refill is reversed
+	if err != nil {
+		releaseLimit()
+	}
+
+	return err
+}
+
+// refillLinkedAccount is a method that moves money from a user's wallet to the linked account.
+// The refilled amount and the fee are decided by withdrawAmount using the wallet that is read inside the transaction.
+// The withdrawal is committed before the account provider is called so no transaction is kept open during the call,
+// if the account provider rejects the refill the withdrawal is reversed in a second transaction.
+func (onepay *OnePay) refillLinkedAccount(userID string, linkedAccount *entity.LinkedAccount,
+	withdrawAmount func(opWallet *entity.UserWallet) (entity.Money, entity.Money, error)) error {
+
+	// The reference is sent to the account provider so the refill can be matched with its settlement statement
+	reference := tools.GenerateProviderReference()
+
+	opHistory := new(entity.UserHistory)
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+		opWallet, err := tx.WalletService.FindWallet(userID, entity.BaseCurrency)
+		if err != nil {
+			return err
+		}
+
+		amount, fee, err := withdrawAmount(opWallet)
+		if err != nil {
+			return err
+		}
+
+		err = tx.WalletService.DebitWallet(opWallet, amount.Add(fee))
+		if err != nil {
+			return err
+		}
+
+		err = tx.CollectFee(fee)
+		if err != nil {
+			return err
+		}
+
+		// Recording the withdrawal in the ledger
+		err = tx.AddJournalEntry(entity.MethodWithdrawn, reference, WalletPosting(userID, amount.Add(fee).Neg()),
+			ClearingPosting(linkedAccount.AccountProviderID, amount), RevenuePosting(fee))
+		if err != nil {
+			return err
+		}
+
+		// Adding history for the withdrawal process
+		opHistory = new(entity.UserHistory)
+		opHistory.SenderID = userID
+		opHistory.ReceiverID = linkedAccount.AccountID
+		opHistory.Method = entity.MethodWithdrawn
+		opHistory.Code = reference
+		opHistory.Amount = amount
+		opHistory.Fee = fee
+		opHistory.SentAt = time.Now()
+		opHistory.ReceivedAt = time.Now()
+
+		return tx.HistoryService.AddHistory(opHistory)
+	})
+	if err != nil {
+		return err
+	}
 
-	err = onepay.WalletService.UpdateWallet(opWallet)
+	err = middleman.RefillAccount(linkedAccount.AccountID, linkedAccount.AccessToken, reference, opHistory.Amount)
 	if err != nil {
+		reverseErr := onepay.reverseWithdrawal(linkedAccount, opHistory)
+		if reverseErr != nil {
+			return reverseErr
+		}
 		return err
 	}
 
+	return nil
+}
+
+// reverseWithdrawal is a method that returns the money and the fee of a withdrawal the account provider has rejected
+// to the user's wallet and records it as a reversal of the withdrawal history.
+// The returned fee is recorded as a negative fee so it is taken off the collected fees.
+func (onepay *OnePay) reverseWithdrawal(linkedAccount *entity.LinkedAccount, withdrawal *entity.UserHistory) error {
+
+	userID := withdrawal.SenderID
+	amount := withdrawal.Amount
+	fee := withdrawal.Fee
+
 	/* ++++ ++++ +++ checkpoint - wallet +++ ++++ ++++ */
 	tempOPWallet := new(entity.UserWallet)
-	tempOPWallet.UserID = opWallet.UserID
-	tempOPWallet.Amount = amount
+	tempOPWallet.UserID = userID
+	tempOPWallet.Amount = amount.Add(fee)
 	logger.Must(onepay.Logger.LogWallet(tempOPWallet))
+
+	tempRevenueWallet := new(entity.UserWallet)
+	tempRevenueWallet.UserID = entity.RevenueWalletID
+	tempRevenueWallet.Amount = fee.Neg()
+	if fee.IsPositive() {
+		logger.Must(onepay.Logger.LogWallet(tempRevenueWallet))
+	}
 	/* +++++ +++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
 
-	err = middleman.RefillAccount(linkedAccount.AccountID, linkedAccount.AccessToken, amount)
-	if err != nil {
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
 
-		/* +++++++++++++++++++++++ Undo +++++++++++++++++++++++ */
-		opWallet.Amount += amount
-		innerErr := onepay.WalletService.UpdateWallet(opWallet)
-		/* ++++++++++++++++++++++++++++++++++++++++++++++++++++ */
+		opWallet, err := tx.WalletService.FindWallet(userID, entity.BaseCurrency)
+		if err != nil {
+			return err
+		}
 
-		if innerErr != nil {
+		err = tx.WalletService.CreditWallet(opWallet, amount.Add(fee))
+		if err != nil {
+			return err
+		}
 
-			// Adding history for the potential reload
-			onepay.AddUserHistory(userID, linkedAccount.AccountID, entity.MethodWithdrawn, "",
-				amount, time.Now(), time.Now())
+		err = tx.ReturnFee(fee)
+		if err != nil {
+			return err
+		}
 
-			return errors.New(entity.WalletCheckpointError)
+		// Recording the reversed withdrawal in the ledger
+		err = tx.AddJournalEntry(entity.MethodReversal, withdrawal.Code, ClearingPosting(linkedAccount.AccountProviderID, amount.Neg()),
+			WalletPosting(userID, amount.Add(fee)), RevenuePosting(fee.Neg()))
+		if err != nil {
+			return err
 		}
 
-		/* +++++ +++++ +++++ checkpoint end +++++ +++++ +++++ */
-		logger.Must(onepay.Logger.RemoveWallet(tempOPWallet))
-		/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
+		return tx.HistoryService.AddHistory(withdrawalReversal(linkedAccount, withdrawal))
+	})
+	if err != nil {
 
-		return err
+		// Adding history and journal entry for the potential reload
+		onepay.addCheckpointedHistory(withdrawalReversal(linkedAccount, withdrawal))
+		onepay.AddJournalEntry(entity.MethodReversal, withdrawal.Code, ClearingPosting(linkedAccount.AccountProviderID, amount.Neg()),
+			WalletPosting(userID, amount.Add(fee)), RevenuePosting(fee.Neg()))
+
+		return errors.New(entity.WalletCheckpointError)
 	}
 
 	/* +++++ +++++ +++++ checkpoint end +++++ +++++ +++++ */
 	logger.Must(onepay.Logger.RemoveWallet(tempOPWallet))
+	if fee.IsPositive() {
+		logger.Must(onepay.Logger.RemoveWallet(tempRevenueWallet))
+	}
 	/* ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ ++++ +++++ */
 
-	// Adding history for the withdrawal process
-	return onepay.AddUserHistory(userID, linkedAccount.AccountID, entity.MethodWithdrawn, "",
-		amount, time.Now(), time.Now())
+	return nil
+}
+
+// withdrawalReversal is a function that returns the history that reverses a withdrawal, it references the withdrawal history
+func withdrawalReversal(linkedAccount *entity.LinkedAccount, withdrawal *entity.UserHistory) *entity.UserHistory {
+
+	reversalHistory := new(entity.UserHistory)
+	reversalHistory.SenderID = linkedAccount.AccountID
+	reversalHistory.ReceiverID = withdrawal.SenderID
+	reversalHistory.Method = entity.MethodReversal
+	reversalHistory.Code = withdrawal.Code
+	reversalHistory.Amount = withdrawal.Amount
+	reversalHistory.Fee = withdrawal.Fee.Neg()
+	reversalHistory.ParentID = withdrawal.ID
+	reversalHistory.SentAt = time.Now()
+	reversalHistory.ReceivedAt = time.Now()
+
+	return reversalHistory
 }
@@ -0,0 +1,369 @@
+package app
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// HoldDefaultExpiry is a constant that defines how long money stays held when no expiry is provided
+const HoldDefaultExpiry = time.Hour * 24 * 7
+
+// HoldMaxExpiry is a constant that defines the longest money can stay held
+const HoldMaxExpiry = time.Hour * 24 * 30
+
+// AuthorizeHold is a method that reserves money on a user's wallet for a merchant through a third party api client.
+// The held money is no longer available to the user but stays in the user's wallet until it is captured, voided or expires.
+func (onepay *OnePay) AuthorizeHold(userID, merchantID, apiKey string, amount entity.Money, reference string,
+	expiry time.Duration, redisClient *redis.Client) (*entity.WalletHold, error) {
+
+	if !AboveTransactionBaseLimit(amount) {
+		return nil, errors.New(entity.TransactionBaseLimitError)
+	}
+
+	if userID == merchantID {
+		return nil, errors.New(entity.TransactionWSelfError)
+	}
+
+	if merchantID == entity.RevenueWalletID {
+		return nil, errors.New(entity.ReceiverNotFoundError)
+	}
+
+	if _, err := onepay.WalletService.FindWallet(merchantID, entity.BaseCurrency); err != nil {
+		return nil, errors.New(entity.ReceiverNotFoundError)
+	}
+
+	// The held money is counted as sent by the user and received by the merchant from the moment it is held,
+	// the reservation is kept on the hold so the money that is never captured can be given back
+	reservation, err := onepay.reserveHoldLimits(userID, merchantID, amount, redisClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiry <= 0 {
+		expiry = HoldDefaultExpiry
+	}
+
+	if expiry > HoldMaxExpiry {
+		expiry = HoldMaxExpiry
+	}
+
+	opHold := new(entity.WalletHold)
+	err = onepay.RunInTransaction(func(tx *Transaction) error {
+
+		opWallet, err := tx.WalletService.FindWallet(userID, amount.Currency)
+		if err != nil {
+			return errors.New(entity.InsufficientBalanceError)
+		}
+
+		if opWallet.Amount.LessThan(amount) {
+			return errors.New(entity.InsufficientBalanceError)
+		}
+
+		err = tx.WalletService.HoldWallet(opWallet, amount)
+		if err != nil {
+			return err
+		}
+
+		// Recording the held money in the ledger
+		err = tx.AddJournalEntry(entity.MethodHoldAuthorization, reference,
+			WalletPosting(userID, amount.Neg()), WalletHoldPosting(userID, amount))
+		if err != nil {
+			return err
+		}
+
+		opHistory := new(entity.UserHistory)
+		opHistory.SenderID = userID
+		opHistory.ReceiverID = merchantID
+		opHistory.Method = entity.MethodHoldAuthorization
+		opHistory.Code = reference
+		opHistory.Amount = amount
+		opHistory.Fee = entity.NewMoney(0, amount.Currency)
+		opHistory.SentAt = time.Now()
+		opHistory.ReceivedAt = time.Now()
+
+		err = tx.HistoryService.AddHistory(opHistory)
+		if err != nil {
+			return err
+		}
+
+		opHold = new(entity.WalletHold)
+		opHold.UserID = userID
+		opHold.MerchantID = merchantID
+		opHold.APIKey = apiKey
+		opHold.Amount = amount
+		opHold.Reference = reference
+		opHold.HistoryID = opHistory.ID
+		opHold.ExpiresAt = time.Now().Add(expiry)
+		opHold.LimitKeys = strings.Join(reservation.Keys, ",")
+		opHold.LimitAmount = reservation.Amount
+
+		return tx.HoldService.AddHold(opHold)
+	})
+	if err != nil {
+		ReleaseLimit(reservation, reservation.Amount, redisClient)
+		return nil, err
+	}
+
+	return opHold, nil
+}
+
+// CaptureHold is a method that moves all or part of the held money to the merchant it was held for.
+// A zero amount captures the whole held amount, any part that isn't captured is released back to the user and to the limits.
+// The transaction fee is deducted from the captured amount since the merchant has initiated the payment.
+func (onepay *OnePay) CaptureHold(userID, apiKey string, holdID int64, amount entity.Money,
+	redisClient *redis.Client) (*entity.WalletHold, error) {
+
+	if amount.IsNegative() {
+		return nil, errors.New("invalid amount used")
+	}
+
+	opHold := new(entity.WalletHold)
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+		lockedHold, err := tx.lockAuthorizedHold(userID, apiKey, holdID)
+		if err != nil {
+			return err
+		}
+
+		currency := lockedHold.Amount.Currency
+		if !amount.IsZero() && amount.Currency != currency {
+			return errors.New("amount currency doesn't match the hold currency")
+		}
+
+		captureAmount := entity.NewMoney(amount.Minor, currency)
+		if captureAmount.IsZero() {
+			captureAmount = lockedHold.Amount
+		}
+
+		if captureAmount.GreaterThan(lockedHold.Amount) {
+			return errors.New(entity.CaptureExceedsHoldError)
+		}
+
+		transactionFee, err := GetTransactionFee(entity.MethodHoldCapture, captureAmount)
+		if err != nil {
+			return err
+		}
+
+		if !captureAmount.GreaterThan(transactionFee) {
+			return errors.New(entity.FeeExceedsAmountError)
+		}
+
+		userOPWallet, err := tx.WalletService.FindWallet(lockedHold.UserID, currency)
+		if err != nil {
+			return err
+		}
+
+		merchantOPWallet, err := tx.ReceivingWallet(lockedHold.MerchantID, currency)
+		if err != nil {
+			return errors.New(entity.ReceiverNotFoundError)
+		}
+
+		err = tx.WalletService.DebitHeldWallet(userOPWallet, captureAmount)
+		if err != nil {
+			return err
+		}
+
+		err = tx.WalletService.CreditWallet(merchantOPWallet, captureAmount.Sub(transactionFee))
+		if err != nil {
+			return err
+		}
+
+		// Recording the captured money in the ledger
+		err = tx.AddJournalEntry(entity.MethodHoldCapture, lockedHold.Reference,
+			WalletHoldPosting(lockedHold.UserID, captureAmount.Neg()),
+			WalletPosting(lockedHold.MerchantID, captureAmount.Sub(transactionFee)), RevenuePosting(transactionFee))
+		if err != nil {
+			return err
+		}
+
+		err = tx.CollectFee(transactionFee)
+		if err != nil {
+			return err
+		}
+
+		err = tx.addHoldHistory(lockedHold, lockedHold.UserID, lockedHold.MerchantID,
+			entity.MethodHoldCapture, captureAmount, transactionFee)
+		if err != nil {
+			return err
+		}
+
+		// Releasing what hasn't been captured
+		err = tx.releaseHold(lockedHold, userOPWallet, lockedHold.Amount.Sub(captureAmount))
+		if err != nil {
+			return err
+		}
+
+		completedAt := time.Now()
+		lockedHold.Captured = captureAmount
+		lockedHold.Status = entity.HoldStatusCaptured
+		lockedHold.CompletedAt = &completedAt
+		opHold = lockedHold
+
+		return tx.HoldService.UpdateHold(lockedHold)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	releaseHoldLimits(opHold, redisClient)
+	return opHold, nil
+}
+
+// VoidHold is a method that cancels a hold before it is captured and releases the whole held money back to the user
+// and to the limits of both parties
+func (onepay *OnePay) VoidHold(userID, apiKey string, holdID int64, redisClient *redis.Client) (*entity.WalletHold, error) {
+
+	opHold := new(entity.WalletHold)
+	err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+		lockedHold, err := tx.lockAuthorizedHold(userID, apiKey, holdID)
+		if err != nil {
+			return err
+		}
+
+		opHold, err = tx.closeHold(lockedHold, entity.HoldStatusVoided)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	releaseHoldLimits(opHold, redisClient)
+	return opHold, nil
+}
+
+// ExpireHolds is a method that releases the money of every authorized hold whose expiry has passed back to its user
+// and to the limits of both parties
+func (onepay *OnePay) ExpireHolds(redisClient *redis.Client) {
+
+	for _, expiredHold := range onepay.HoldService.ExpiredHolds(time.Now()) {
+
+		holdID := int64(expiredHold.ID)
+		var closedHold *entity.WalletHold
+		err := onepay.RunInTransaction(func(tx *Transaction) error {
+
+			lockedHold, err := tx.HoldService.LockHold(holdID)
+			if err != nil {
+				return err
+			}
+
+			// The hold may have been captured or voided since it was listed
+			if lockedHold.Status != entity.HoldStatusAuthorized {
+				return nil
+			}
+
+			closedHold, err = tx.closeHold(lockedHold, entity.HoldStatusExpired)
+			return err
+		})
+		if err == nil && closedHold != nil {
+			releaseHoldLimits(closedHold, redisClient)
+		}
+	}
+}
+
+// lockAuthorizedHold is a method that locks a hold that can still be captured or voided by the requesting user and api client.
+// Only the api client that has authorized the hold can complete it, on behalf of either the user or the merchant.
+func (tx *Transaction) lockAuthorizedHold(userID, apiKey string, holdID int64) (*entity.WalletHold, error) {
+
+	lockedHold, err := tx.HoldService.LockHold(holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	if lockedHold.APIKey != apiKey || (lockedHold.UserID != userID && lockedHold.MerchantID != userID) {
+		return nil, errors.New("hold not found")
+	}
+
+	if lockedHold.Status != entity.HoldStatusAuthorized || time.Now().After(lockedHold.ExpiresAt) {
+		return nil, errors.New(entity.ClosedHoldError)
+	}
+
+	return lockedHold, nil
+}
+
+// closeHold is a method that releases the whole money of a hold back to its user and closes it with the provided status
+func (tx *Transaction) closeHold(lockedHold *entity.WalletHold, status string) (*entity.WalletHold, error) {
+
+	userOPWallet, err := tx.WalletService.FindWallet(lockedHold.UserID, lockedHold.Amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.releaseHold(lockedHold, userOPWallet, lockedHold.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	completedAt := time.Now()
+	lockedHold.Status = status
+	lockedHold.CompletedAt = &completedAt
+
+	err = tx.HoldService.UpdateHold(lockedHold)
+	if err != nil {
+		return nil, err
+	}
+
+	return lockedHold, nil
+}
+
+// releaseHold is a method that moves the provided amount of a hold from the held back to the available amount of the user's wallet
+func (tx *Transaction) releaseHold(lockedHold *entity.WalletHold, userOPWallet *entity.UserWallet, amount entity.Money) error {
+
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	err := tx.WalletService.ReleaseWallet(userOPWallet, amount)
+	if err != nil {
+		return err
+	}
+
+	err = tx.AddJournalEntry(entity.MethodHoldRelease, lockedHold.Reference,
+		WalletHoldPosting(lockedHold.UserID, amount.Neg()), WalletPosting(lockedHold.UserID, amount))
+	if err != nil {
+		return err
+	}
+
+	return tx.addHoldHistory(lockedHold, lockedHold.MerchantID, lockedHold.UserID,
+		entity.MethodHoldRelease, amount, entity.NewMoney(0, amount.Currency))
+}
+
+// releaseHoldLimits is a function that gives back the part of a closed hold's limit reservation that hasn't been captured.
+// The part is taken in proportion to the held amount so a change in the exchange rate doesn't change what is given back.
+func releaseHoldLimits(opHold *entity.WalletHold, redisClient *redis.Client) {
+
+	if opHold.LimitKeys == "" || !opHold.Amount.IsPositive() {
+		return
+	}
+
+	uncaptured := opHold.Amount.Sub(opHold.Captured)
+	releasedMinor := new(big.Int).Div(new(big.Int).Mul(big.NewInt(opHold.LimitAmount.Minor), big.NewInt(uncaptured.Minor)),
+		big.NewInt(opHold.Amount.Minor))
+
+	reservation := &entity.LimitReservation{Keys: strings.Split(opHold.LimitKeys, ","), Amount: opHold.LimitAmount}
+	ReleaseLimit(reservation, entity.NewMoney(releasedMinor.Int64(), entity.BaseCurrency), redisClient)
+}
+
+// addHoldHistory is a method that adds a history that references the authorization history of a hold
+func (tx *Transaction) addHoldHistory(lockedHold *entity.WalletHold, senderID, receiverID, method string,
+	amount, fee entity.Money) error {
+
+	opHistory := new(entity.UserHistory)
+	opHistory.SenderID = senderID
+	opHistory.ReceiverID = receiverID
+	opHistory.Method = method
+	opHistory.Code = lockedHold.Reference
+	opHistory.Amount = amount
+	opHistory.Fee = fee
+	opHistory.ParentID = lockedHold.HistoryID
+	opHistory.SentAt = time.Now()
+	opHistory.ReceivedAt = time.Now()
+
+	return tx.HistoryService.AddHistory(opHistory)
+}
@@ -0,0 +1,17 @@
+-- Converts the float amount columns to integer minor units (santim for ETB).
+-- The server runs the same steps on start up for any amount column that is still a float.
+ALTER TABLE user_wallets MODIFY amount DOUBLE NOT NULL;
+UPDATE user_wallets SET amount = ROUND(amount * 100);
+ALTER TABLE user_wallets MODIFY amount BIGINT NOT NULL;
+
+ALTER TABLE user_history MODIFY amount DOUBLE NOT NULL;
+UPDATE user_history SET amount = ROUND(amount * 100);
+ALTER TABLE user_history MODIFY amount BIGINT NOT NULL;
+
+ALTER TABLE money_tokens MODIFY amount DOUBLE NOT NULL;
+UPDATE money_tokens SET amount = ROUND(amount * 100);
+ALTER TABLE money_tokens MODIFY amount BIGINT NOT NULL;
+
+ALTER TABLE postings MODIFY amount DOUBLE NOT NULL;
+UPDATE postings SET amount = ROUND(amount * 100);
+ALTER TABLE postings MODIFY amount BIGINT NOT NULL;
@@ -0,0 +1,8 @@
+CREATE TABLE dispute_evidences (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    dispute_id INT NOT NULL,
+    submitter_id VARCHAR(255) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255), -- the attachment stored in assets/disputes
+    created_at DATETIME
+);
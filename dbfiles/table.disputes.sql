@@ -0,0 +1,18 @@
+CREATE TABLE disputes (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    history_id INT NOT NULL, -- the disputed user history
+    opener_id VARCHAR(255) NOT NULL,
+    counterparty_id VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    held_amount BIGINT NOT NULL DEFAULT 0, -- the part of the amount held from the counterparty's wallet
+    recovered_amount BIGINT NOT NULL DEFAULT 0, -- the part of the amount returned to the opener of a won dispute
+    currency VARCHAR(255) NOT NULL DEFAULT 'ETB',
+    reason VARCHAR(255) NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    resolution VARCHAR(255),
+    resolved_by VARCHAR(255),
+    deadline DATETIME,
+    resolved_at DATETIME,
+    created_at DATETIME,
+    updated_at DATETIME
+);
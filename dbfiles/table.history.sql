@@ -6,7 +6,18 @@ CREATE TABLE user_history (
     received_at VARCHAR,
     method VARCHAR,
     code VARCHAR,
-    amount FLOAT,
+    amount BIGINT,
+    fee BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR NOT NULL DEFAULT 'ETB',
+    converted_amount BIGINT NOT NULL DEFAULT 0, -- only set when money has been converted to another currency
+    converted_currency VARCHAR NOT NULL DEFAULT '',
+    exchange_rate DOUBLE NOT NULL DEFAULT 0,
+    spread DOUBLE NOT NULL DEFAULT 0,
     sender_seen BOOLEAN,
-    receiver_seen BOOLEAN
+    receiver_seen BOOLEAN,
+    parent_id INT NOT NULL DEFAULT 0, -- the history a refund or a reversal belongs to
+    order_id VARCHAR NOT NULL DEFAULT '', -- the invoice of a payment token, copied to the history of its payment
+    description VARCHAR NOT NULL DEFAULT '',
+    callback_ref VARCHAR NOT NULL DEFAULT '',
+    line_items TEXT -- json array of the invoice line items
 );
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE job_runs (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    job_name VARCHAR(255) NOT NULL,
+    instance VARCHAR(255) NOT NULL, -- the server instance that has run the job
+    status VARCHAR(255) NOT NULL,
+    error VARCHAR(255),
+    started_at DATETIME,
+    finished_at DATETIME
+);
@@ -0,0 +1,6 @@
+CREATE TABLE journal_entries (
+    id INT PRIMARY KEY,
+    method VARCHAR,
+    code VARCHAR,
+    created_at DATETIME
+);
@@ -0,0 +1,7 @@
+CREATE TABLE ledger_accounts (
+    id VARCHAR PRIMARY KEY,
+    type VARCHAR,
+    owner_id VARCHAR,
+    currency VARCHAR NOT NULL DEFAULT 'ETB',
+    created_at DATETIME
+);
@@ -0,0 +1,10 @@
+CREATE TABLE money_token_claims (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    code VARCHAR(255) NOT NULL,
+    claimant_id VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    fee BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR(255) NOT NULL DEFAULT 'ETB',
+    claimed_at DATETIME,
+    UNIQUE KEY idx_money_token_claimant (code, claimant_id) -- a user can claim a money token only once
+);
@@ -2,7 +2,29 @@ CREATE TABLE money_tokens(
     code VARCHAR PRIMARY KEY,
     sender_id VARCHAR,
     sent_at DATETIME,
-    amount FLOAT,
+    amount BIGINT,
+    fee BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR NOT NULL DEFAULT 'ETB',
     expiration_date DATETIME,
-    method VARCHAR
-);
\ No newline at end of file
+    method VARCHAR,
+    claim_mode VARCHAR NOT NULL DEFAULT 'Fixed', -- Fixed gives every claimant the amount, Split splits the amount between the claimants
+    max_claims INT NOT NULL DEFAULT 1,
+    claim_count INT NOT NULL DEFAULT 0,
+    remaining BIGINT NOT NULL DEFAULT 0, -- the part of the amount that hasn't been claimed yet
+    remaining_fee BIGINT NOT NULL DEFAULT 0,
+    protected BOOLEAN NOT NULL DEFAULT FALSE, -- protected money tokens can only be claimed with the sender's secret
+    secret VARCHAR, -- the bcrypt hash of the secret
+    secret_salt VARCHAR,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    locked BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR NOT NULL DEFAULT 'Active', -- Active, Claimed, Reclaimed, Expired or Cancelled
+    claimant_id VARCHAR, -- the user who made the latest claim
+    claimed_at DATETIME,
+    reclaimed_at DATETIME,
+    expired_at DATETIME,
+    cancelled_at DATETIME,
+    order_id VARCHAR NOT NULL DEFAULT '', -- the invoice of a payment token, copied to the history of its payment
+    description VARCHAR NOT NULL DEFAULT '',
+    callback_ref VARCHAR NOT NULL DEFAULT '',
+    line_items TEXT -- json array of the invoice line items
+);
@@ -0,0 +1,11 @@
+CREATE TABLE payment_groups (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    initiator_id VARCHAR(255) NOT NULL,
+    total BIGINT NOT NULL, -- the amount that has been split
+    currency VARCHAR(255) NOT NULL DEFAULT 'ETB',
+    description VARCHAR(255),
+    status VARCHAR(255) NOT NULL,
+    last_reminded_at DATETIME,
+    created_at DATETIME,
+    updated_at DATETIME
+);
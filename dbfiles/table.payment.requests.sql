@@ -0,0 +1,15 @@
+CREATE TABLE payment_requests (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    requester_id VARCHAR(255) NOT NULL, -- the user who receives the money
+    payer_id VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(255) NOT NULL DEFAULT 'ETB',
+    note VARCHAR(255),
+    status VARCHAR(255) NOT NULL,
+    history_id INT NOT NULL DEFAULT 0, -- the history of the transfer that paid the request
+    group_id INT NOT NULL DEFAULT 0, -- the payment group the request is a share of
+    expires_at DATETIME,
+    responded_at DATETIME,
+    created_at DATETIME,
+    updated_at DATETIME
+);
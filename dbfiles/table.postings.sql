@@ -0,0 +1,7 @@
+CREATE TABLE postings (
+    id INT PRIMARY KEY,
+    journal_entry_id INT,
+    account_id VARCHAR, -- the ledger account the money moved in or out of
+    amount BIGINT,
+    currency VARCHAR NOT NULL DEFAULT 'ETB'
+);
@@ -1,4 +1,5 @@
 CREATE TABLE user_preferences (
     user_id VARCHAR,
     two_step_verification BOOLEAN,
+    time_zone VARCHAR(255) NOT NULL DEFAULT 'Africa/Addis_Ababa', -- the daily limits reset at midnight in this time zone
 );
\ No newline at end of file
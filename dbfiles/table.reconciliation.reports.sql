@@ -0,0 +1,9 @@
+CREATE TABLE reconciliation_reports (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    triggered_by VARCHAR(255) NOT NULL, -- the staff member who has run the reconciliation or 'Scheduled'
+    wallet_count INT NOT NULL DEFAULT 0,
+    discrepancy_count INT NOT NULL DEFAULT 0,
+    quarantine_count INT NOT NULL DEFAULT 0,
+    started_at DATETIME,
+    finished_at DATETIME
+);
@@ -0,0 +1,20 @@
+CREATE TABLE scheduled_transfers (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    sender_id VARCHAR(255) NOT NULL,
+    receiver_id VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(255) NOT NULL DEFAULT 'ETB',
+    frequency VARCHAR(255) NOT NULL, -- once, daily, weekly or monthly
+    start_at DATETIME,
+    end_date DATETIME,
+    occurrences INT NOT NULL DEFAULT 0, -- the number of occurrences that have been made or skipped
+    scheduled_at DATETIME, -- the occurrence the next run is made for
+    next_run_at DATETIME, -- differs from scheduled_at while a failed run is retried
+    attempts INT NOT NULL DEFAULT 0,
+    status VARCHAR(255) NOT NULL,
+    last_error VARCHAR(255),
+    last_run_at DATETIME,
+    claimed_at DATETIME, -- when the scheduler has claimed the running transfer, a stale claim is requeued
+    created_at DATETIME,
+    updated_at DATETIME
+);
@@ -0,0 +1,16 @@
+CREATE TABLE settlement_items (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    statement_id INT NOT NULL,
+    account_provider_id VARCHAR(255) NOT NULL,
+    reference VARCHAR(255) NOT NULL, -- the provider reference, the code of the recharge or withdrawal history
+    method VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(255) NOT NULL DEFAULT 'ETB',
+    settled_at DATETIME,
+    history_id INT NOT NULL DEFAULT 0,
+    status VARCHAR(255) NOT NULL,
+    reason VARCHAR(255),
+    note VARCHAR(255),
+    resolved_by VARCHAR(255),
+    resolved_at DATETIME
+);
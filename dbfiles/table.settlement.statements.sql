@@ -0,0 +1,15 @@
+CREATE TABLE settlement_statements (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    account_provider_id VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    format VARCHAR(255) NOT NULL, -- 'csv' or 'fixed'
+    imported_by VARCHAR(255) NOT NULL, -- the staff member who has imported the statement
+    line_count INT NOT NULL DEFAULT 0,
+    matched_count INT NOT NULL DEFAULT 0,
+    mismatched_count INT NOT NULL DEFAULT 0,
+    unmatched_count INT NOT NULL DEFAULT 0,
+    missing_count INT NOT NULL DEFAULT 0,
+    period_start DATETIME,
+    period_end DATETIME,
+    imported_at DATETIME
+);
@@ -0,0 +1,11 @@
+CREATE TABLE staffs (
+    user_id VARCHAR(255) PRIMARY KEY UNIQUE NOT NULL, -- the onepay user id of the staff member
+    first_name VARCHAR(255) NOT NULL,
+    last_name VARCHAR(255) NOT NULL,
+    phone_number VARCHAR(255) UNIQUE NOT NULL,
+    email VARCHAR(255) UNIQUE NOT NULL,
+    profile_pic VARCHAR(255) NOT NULL,
+    role VARCHAR(255) NOT NULL,
+    created_at DATETIME,
+    updated_at DATETIME
+);
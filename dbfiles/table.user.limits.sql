@@ -0,0 +1,7 @@
+CREATE TABLE user_limits (
+    user_id VARCHAR(255) PRIMARY KEY UNIQUE NOT NULL,
+    tier VARCHAR(255) NOT NULL DEFAULT 'unverified',
+    profile VARCHAR(255), -- the limit profile a staff member has assigned, it overrides the profile of the tier
+    staff_id VARCHAR(255),
+    updated_at DATETIME
+);
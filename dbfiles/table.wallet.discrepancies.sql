@@ -0,0 +1,12 @@
+CREATE TABLE wallet_discrepancies (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    report_id INT NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    currency VARCHAR(255) NOT NULL DEFAULT 'ETB',
+    expected_amount BIGINT NOT NULL, -- the amount recomputed from the user histories
+    actual_amount BIGINT NOT NULL,
+    expected_held BIGINT NOT NULL DEFAULT 0,
+    actual_held BIGINT NOT NULL DEFAULT 0,
+    quarantined BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME
+);
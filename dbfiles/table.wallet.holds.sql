@@ -0,0 +1,18 @@
+CREATE TABLE wallet_holds (
+    id INT PRIMARY KEY UNIQUE NOT NULL AUTO_INCREMENT,
+    user_id VARCHAR(255) NOT NULL, -- the user whose money is held
+    merchant_id VARCHAR(255) NOT NULL,
+    api_key VARCHAR(255) NOT NULL, -- the api client that authorized the hold
+    amount BIGINT NOT NULL,
+    captured BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR(255) NOT NULL DEFAULT 'ETB',
+    reference VARCHAR(255),
+    status VARCHAR(255) NOT NULL,
+    history_id INT NOT NULL DEFAULT 0, -- the history of the authorization
+    expires_at DATETIME,
+    completed_at DATETIME,
+    created_at DATETIME,
+    updated_at DATETIME,
+    limit_keys TEXT, -- the limit usage keys the held amount has been added to
+    limit_amount BIGINT NOT NULL DEFAULT 0 -- the held amount counted against the limits in base currency
+);
@@ -1,6 +1,13 @@
 CREATE TABLE user_wallets (
     user_id VARCHAR,
-    amount FLOAT,
+    currency VARCHAR NOT NULL DEFAULT 'ETB',
+    amount BIGINT, -- the available amount
+    held BIGINT NOT NULL DEFAULT 0, -- the amount held for merchants, not available to the user
     seen BOOLEAN,
+    quarantined BOOLEAN NOT NULL DEFAULT FALSE, -- no money can leave a quarantined wallet
+    version BIGINT NOT NULL DEFAULT 0,
     updated_at DATETIME,
-);
\ No newline at end of file
+    PRIMARY KEY (user_id, currency)
+);
+-- Existing databases that keyed the wallets by user_id only are migrated on start up, the equivalent is
+-- ALTER TABLE user_wallets DROP INDEX user_id, DROP PRIMARY KEY, ADD PRIMARY KEY (user_id, currency);
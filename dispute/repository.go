@@ -0,0 +1,26 @@
+package dispute
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// IDisputeRepository is an interface that defines all the repository methods of a dispute struct
+type IDisputeRepository interface {
+	Create(newDispute *entity.Dispute) error
+	Find(identifier int64) (*entity.Dispute, error)
+	FindForUpdate(identifier int64) (*entity.Dispute, error)
+	Search(userID string) []*entity.Dispute
+	SearchByStatus(statuses []string) []*entity.Dispute
+	Active(historyID int64) (*entity.Dispute, error)
+	Update(dispute *entity.Dispute) error
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IDisputeRepository
+}
+
+// IEvidenceRepository is an interface that defines all the repository methods of a dispute evidence struct
+type IEvidenceRepository interface {
+	Create(newEvidence *entity.DisputeEvidence) error
+	Find(identifier int64) (*entity.DisputeEvidence, error)
+	Search(disputeID int64) []*entity.DisputeEvidence
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IEvidenceRepository
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"github.com/Benyam-S/onepay/dispute"
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+	"github.com/jinzhu/gorm"
+)
+
+// EvidenceRepository is a type that defines a dispute evidence repository
+type EvidenceRepository struct {
+	conn *gorm.DB
+}
+
+// NewEvidenceRepository is a function that returns a new dispute evidence repository
+func NewEvidenceRepository(connection *gorm.DB) dispute.IEvidenceRepository {
+	return &EvidenceRepository{conn: connection}
+}
+
+// Create is a method that adds a new dispute evidence to the database
+func (repo *EvidenceRepository) Create(newEvidence *entity.DisputeEvidence) error {
+
+	err := repo.conn.Create(newEvidence).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds a certain dispute evidence from the database using an identifier.
+// In Find() id is only used as a key
+func (repo *EvidenceRepository) Find(identifier int64) (*entity.DisputeEvidence, error) {
+	evidence := new(entity.DisputeEvidence)
+	err := repo.conn.Model(evidence).
+		Where("id = ?", identifier).First(evidence).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return evidence, nil
+}
+
+// Search is a method that returns all the evidences submitted for a certain dispute
+func (repo *EvidenceRepository) Search(disputeID int64) []*entity.DisputeEvidence {
+	var evidences []*entity.DisputeEvidence
+	err := repo.conn.Model(entity.DisputeEvidence{}).
+		Where("dispute_id = ?", disputeID).
+		Order("id").Find(&evidences).Error
+
+	if err != nil {
+		return []*entity.DisputeEvidence{}
+	}
+	return evidences
+}
+
+// WithUnitOfWork is a method that returns a dispute evidence repository that runs its queries inside the provided unit of work
+func (repo *EvidenceRepository) WithUnitOfWork(uow *unitofwork.UnitOfWork) dispute.IEvidenceRepository {
+	return &EvidenceRepository{conn: uow.Conn()}
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"github.com/Benyam-S/onepay/dispute"
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+	"github.com/jinzhu/gorm"
+)
+
+// DisputeRepository is a type that defines a dispute repository
+type DisputeRepository struct {
+	conn *gorm.DB
+}
+
+// NewDisputeRepository is a function that returns a new dispute repository
+func NewDisputeRepository(connection *gorm.DB) dispute.IDisputeRepository {
+	return &DisputeRepository{conn: connection}
+}
+
+// Create is a method that adds a new dispute to the database
+func (repo *DisputeRepository) Create(newDispute *entity.Dispute) error {
+
+	err := repo.conn.Create(newDispute).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds a certain dispute from the database using an identifier.
+// In Find() id is only used as a key
+func (repo *DisputeRepository) Find(identifier int64) (*entity.Dispute, error) {
+	opDispute := new(entity.Dispute)
+	err := repo.conn.Model(opDispute).
+		Where("id = ?", identifier).First(opDispute).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return opDispute, nil
+}
+
+// FindForUpdate is a method that finds a certain dispute from the database using an identifier and locks it
+// until the transaction it is read in ends
+func (repo *DisputeRepository) FindForUpdate(identifier int64) (*entity.Dispute, error) {
+	opDispute := new(entity.Dispute)
+	err := repo.conn.Set("gorm:query_option", "FOR UPDATE").Model(opDispute).
+		Where("id = ?", identifier).First(opDispute).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return opDispute, nil
+}
+
+// Search is a method that returns all the disputes a certain user has opened or is the counterparty of
+func (repo *DisputeRepository) Search(userID string) []*entity.Dispute {
+	var opDisputes []*entity.Dispute
+	err := repo.conn.Model(entity.Dispute{}).
+		Where("opener_id = ? || counterparty_id = ?", userID, userID).
+		Order("id DESC").Find(&opDisputes).Error
+
+	if err != nil {
+		return []*entity.Dispute{}
+	}
+	return opDisputes
+}
+
+// SearchByStatus is a method that returns all the disputes that have one of the provided statuses, the oldest deadline first
+func (repo *DisputeRepository) SearchByStatus(statuses []string) []*entity.Dispute {
+	var opDisputes []*entity.Dispute
+	err := repo.conn.Model(entity.Dispute{}).
+		Where("status IN (?)", statuses).
+		Order("deadline").Find(&opDisputes).Error
+
+	if err != nil {
+		return []*entity.Dispute{}
+	}
+	return opDisputes
+}
+
+// Active is a method that finds the dispute of a certain history that hasn't been resolved yet
+func (repo *DisputeRepository) Active(historyID int64) (*entity.Dispute, error) {
+	opDispute := new(entity.Dispute)
+	err := repo.conn.Model(opDispute).
+		Where("history_id = ? AND status IN (?)", historyID,
+			[]string{entity.DisputeStatusOpen, entity.DisputeStatusUnderReview}).
+		First(opDispute).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return opDispute, nil
+}
+
+// Update is a method that updates a certain dispute value in the database
+func (repo *DisputeRepository) Update(opDispute *entity.Dispute) error {
+
+	prevDispute := new(entity.Dispute)
+	err := repo.conn.Model(prevDispute).Where("id = ?", opDispute.ID).First(prevDispute).Error
+
+	if err != nil {
+		return err
+	}
+
+	err = repo.conn.Save(opDispute).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// WithUnitOfWork is a method that returns a dispute repository that runs its queries inside the provided unit of work
+func (repo *DisputeRepository) WithUnitOfWork(uow *unitofwork.UnitOfWork) dispute.IDisputeRepository {
+	return &DisputeRepository{conn: uow.Conn()}
+}
@@ -0,0 +1,23 @@
+package dispute
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// IService is an interface that defines all the service methods of a dispute struct
+type IService interface {
+	AddDispute(newDispute *entity.Dispute) error
+	FindDispute(identifier int64) (*entity.Dispute, error)
+	LockDispute(identifier int64) (*entity.Dispute, error)
+	SearchDisputes(userID string) []*entity.Dispute
+	DisputesByStatus(statuses ...string) []*entity.Dispute
+	ActiveDispute(historyID int64) (*entity.Dispute, error)
+	UpdateDispute(dispute *entity.Dispute) error
+
+	AddEvidence(newEvidence *entity.DisputeEvidence) error
+	FindEvidence(identifier int64) (*entity.DisputeEvidence, error)
+	SearchEvidences(disputeID int64) []*entity.DisputeEvidence
+
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IService
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/Benyam-S/onepay/dispute"
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// Service is a type that defines dispute service
+type Service struct {
+	disputeRepo  dispute.IDisputeRepository
+	evidenceRepo dispute.IEvidenceRepository
+}
+
+// NewDisputeService is a function that returns a new dispute service
+func NewDisputeService(disputeRepository dispute.IDisputeRepository,
+	evidenceRepository dispute.IEvidenceRepository) dispute.IService {
+	return &Service{disputeRepo: disputeRepository, evidenceRepo: evidenceRepository}
+}
+
+// AddDispute is a method that adds a new dispute to the system
+func (service *Service) AddDispute(newDispute *entity.Dispute) error {
+
+	empty, _ := regexp.MatchString(`^\s*$`, newDispute.Reason)
+	if empty {
+		return errors.New("dispute reason can not be empty")
+	}
+
+	newDispute.Reason = strings.TrimSpace(newDispute.Reason)
+	newDispute.Status = entity.DisputeStatusOpen
+
+	err := service.disputeRepo.Create(newDispute)
+	if err != nil {
+		return errors.New("unable to add new dispute")
+	}
+	return nil
+}
+
+// FindDispute is a method that finds a certain dispute using the identifier
+func (service *Service) FindDispute(identifier int64) (*entity.Dispute, error) {
+
+	opDispute, err := service.disputeRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("dispute not found")
+	}
+	return opDispute, nil
+}
+
+// LockDispute is a method that finds a certain dispute using the identifier and locks it for the rest of the unit of work
+func (service *Service) LockDispute(identifier int64) (*entity.Dispute, error) {
+
+	opDispute, err := service.disputeRepo.FindForUpdate(identifier)
+	if err != nil {
+		return nil, errors.New("dispute not found")
+	}
+	return opDispute, nil
+}
+
+// SearchDisputes is a method that returns all the disputes a certain user is part of
+func (service *Service) SearchDisputes(userID string) []*entity.Dispute {
+
+	empty, _ := regexp.MatchString(`^\s*$`, userID)
+	if empty {
+		return []*entity.Dispute{}
+	}
+
+	return service.disputeRepo.Search(userID)
+}
+
+// DisputesByStatus is a method that returns all the disputes that have one of the provided statuses
+func (service *Service) DisputesByStatus(statuses ...string) []*entity.Dispute {
+
+	if len(statuses) == 0 {
+		return []*entity.Dispute{}
+	}
+
+	return service.disputeRepo.SearchByStatus(statuses)
+}
+
+// ActiveDispute is a method that finds the dispute of a certain history that hasn't been resolved yet
+func (service *Service) ActiveDispute(historyID int64) (*entity.Dispute, error) {
+
+	opDispute, err := service.disputeRepo.Active(historyID)
+	if err != nil {
+		return nil, errors.New("dispute not found")
+	}
+	return opDispute, nil
+}
+
+// UpdateDispute is a method that updates a certain dispute
+func (service *Service) UpdateDispute(opDispute *entity.Dispute) error {
+
+	err := service.disputeRepo.Update(opDispute)
+	if err != nil {
+		return errors.New("unable to update dispute")
+	}
+	return nil
+}
+
+// AddEvidence is a method that adds a new evidence to a dispute
+func (service *Service) AddEvidence(newEvidence *entity.DisputeEvidence) error {
+
+	empty, _ := regexp.MatchString(`^\s*$`, newEvidence.Description)
+	if empty {
+		return errors.New("evidence description can not be empty")
+	}
+
+	newEvidence.Description = strings.TrimSpace(newEvidence.Description)
+
+	err := service.evidenceRepo.Create(newEvidence)
+	if err != nil {
+		return errors.New("unable to add new evidence")
+	}
+	return nil
+}
+
+// FindEvidence is a method that finds a certain dispute evidence using the identifier
+func (service *Service) FindEvidence(identifier int64) (*entity.DisputeEvidence, error) {
+
+	evidence, err := service.evidenceRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("evidence not found")
+	}
+	return evidence, nil
+}
+
+// SearchEvidences is a method that returns all the evidences of a certain dispute
+func (service *Service) SearchEvidences(disputeID int64) []*entity.DisputeEvidence {
+	return service.evidenceRepo.Search(disputeID)
+}
+
+// WithUnitOfWork is a method that returns a dispute service whose changes are made inside the provided unit of work
+func (service *Service) WithUnitOfWork(uow *unitofwork.UnitOfWork) dispute.IService {
+	return &Service{disputeRepo: service.disputeRepo.WithUnitOfWork(uow),
+		evidenceRepo: service.evidenceRepo.WithUnitOfWork(uow)}
+}
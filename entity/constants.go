@@ -30,15 +30,145 @@ const MethodPaymentQRCode = "Payment Via QR Code"
 // MethodTransactionOnePayID is a constant that defines a transaction via OnePay id
 const MethodTransactionOnePayID = "Transaction Via OnePay ID"
 
+// MethodCurrencyConversion is a constant that defines money has been converted from one currency to another
+const MethodCurrencyConversion = "Currency Conversion"
+
+// MethodHoldAuthorization is a constant that defines money has been held on the wallet of a user for a merchant
+const MethodHoldAuthorization = "Hold Authorization"
+
+// MethodHoldCapture is a constant that defines held money has been captured by the merchant it was held for
+const MethodHoldCapture = "Hold Capture"
+
+// MethodHoldRelease is a constant that defines held money has been released back to the wallet of the user
+const MethodHoldRelease = "Hold Release"
+
+// MethodRefund is a constant that defines money has been returned to the sender by the receiver of a transaction
+const MethodRefund = "Refund"
+
+// MethodReversal is a constant that defines money of a transaction has been returned to the sender by a staff member
+const MethodReversal = "Reversal"
+
 // MethodRecharged is a constant that defines an account has been recharged
 const MethodRecharged = "Recharged"
 
 // MethodWithdrawn is a constant that defines money has been withdrawn from account
 const MethodWithdrawn = "Withdrawn"
 
+// RevenueWalletID is a constant that defines the user id of the system wallet that collects all the fees
+const RevenueWalletID = "OnePay"
+
+// LedgerAccountWallet is a constant that defines a ledger account type for a user wallet
+const LedgerAccountWallet = "wallet"
+
+// LedgerAccountFeeIncome is a constant that defines a ledger account type for the collected transaction fees
+const LedgerAccountFeeIncome = "fee_income"
+
+// LedgerAccountProviderClearing is a constant that defines a ledger account type for an account provider's clearing account
+const LedgerAccountProviderClearing = "provider_clearing"
+
+// LedgerAccountMoneyTokenHolding is a constant that defines a ledger account type for money locked in unclaimed money tokens
+const LedgerAccountMoneyTokenHolding = "money_token_holding"
+
+// LedgerAccountOpeningBalance is a constant that defines a ledger account type for balances that existed before the ledger
+const LedgerAccountOpeningBalance = "opening_balance"
+
+// LedgerAccountCurrencyExchange is a constant that defines a ledger account type that takes the opposite side of currency conversions
+const LedgerAccountCurrencyExchange = "currency_exchange"
+
+// FrequencyOnce is a constant that defines a scheduled transfer that is made only once
+const FrequencyOnce = "once"
+
+// FrequencyDaily is a constant that defines a scheduled transfer that is made every day
+const FrequencyDaily = "daily"
+
+// FrequencyWeekly is a constant that defines a scheduled transfer that is made every week
+const FrequencyWeekly = "weekly"
+
+// FrequencyMonthly is a constant that defines a scheduled transfer that is made every month
+const FrequencyMonthly = "monthly"
+
+// ScheduledTransferStatusActive is a constant that defines a scheduled transfer that is waiting for its next run
+const ScheduledTransferStatusActive = "Active"
+
+// ScheduledTransferStatusRunning is a constant that defines a scheduled transfer that is being made by the scheduler
+const ScheduledTransferStatusRunning = "Running"
+
+// ScheduledTransferStatusCompleted is a constant that defines a scheduled transfer that has made all of its runs
+const ScheduledTransferStatusCompleted = "Completed"
+
+// ScheduledTransferStatusFailed is a constant that defines a one-off scheduled transfer that has failed all of its attempts
+const ScheduledTransferStatusFailed = "Failed"
+
+// ScheduledTransferStatusCancelled is a constant that defines a scheduled transfer that has been cancelled by its sender
+const ScheduledTransferStatusCancelled = "Cancelled"
+
+// PaymentRequestStatusPending is a constant that defines a payment request that waits for the payer's response
+const PaymentRequestStatusPending = "Pending"
+
+// PaymentRequestStatusPaid is a constant that defines a payment request that has been paid by the payer
+const PaymentRequestStatusPaid = "Paid"
+
+// PaymentRequestStatusDeclined is a constant that defines a payment request that has been declined by the payer
+const PaymentRequestStatusDeclined = "Declined"
+
+// PaymentRequestStatusCancelled is a constant that defines a payment request that has been cancelled by the requester
+const PaymentRequestStatusCancelled = "Cancelled"
+
+// PaymentRequestStatusExpired is a constant that defines a payment request that hasn't been responded to before its expiry
+const PaymentRequestStatusExpired = "Expired"
+
+// PaymentGroupStatusOpen is a constant that defines a payment group that still has unpaid shares
+const PaymentGroupStatusOpen = "Open"
+
+// PaymentGroupStatusCompleted is a constant that defines a payment group whose shares have all been paid
+const PaymentGroupStatusCompleted = "Completed"
+
+// PaymentGroupStatusIncomplete is a constant that defines a payment group that can't be completed anymore
+// since the share of one of its participants has been declined, cancelled or has expired
+const PaymentGroupStatusIncomplete = "Incomplete"
+
+// LedgerAccountWalletHold is a constant that defines a ledger account type that holds the money merchants have reserved on a user's wallet
+const LedgerAccountWalletHold = "wallet_hold"
+
+// HoldStatusAuthorized is a constant that defines a hold whose money is still reserved
+const HoldStatusAuthorized = "Authorized"
+
+// HoldStatusCaptured is a constant that defines a hold whose money has been captured, fully or partially, by the merchant
+const HoldStatusCaptured = "Captured"
+
+// HoldStatusVoided is a constant that defines a hold that has been cancelled before it was captured
+const HoldStatusVoided = "Voided"
+
+// HoldStatusExpired is a constant that defines a hold that hasn't been captured before its expiry
+const HoldStatusExpired = "Expired"
+
+// LedgerAccountDisputeHolding is a constant that defines a ledger account type that holds the money of open disputes
+const LedgerAccountDisputeHolding = "dispute_holding"
+
+// DisputeStatusOpen is a constant that defines a dispute that has been opened and waits for review
+const DisputeStatusOpen = "Open"
+
+// DisputeStatusUnderReview is a constant that defines a dispute that is being reviewed by a staff member
+const DisputeStatusUnderReview = "Under Review"
+
+// DisputeStatusWon is a constant that defines a dispute that has been resolved in favour of the user who opened it
+const DisputeStatusWon = "Won"
+
+// DisputeStatusLost is a constant that defines a dispute that has been resolved in favour of the counterparty
+const DisputeStatusLost = "Lost"
+
+// MethodOpeningBalance is a constant that defines a journal entry that opens a wallet's ledger account
+const MethodOpeningBalance = "Opening Balance"
+
 // TransactionFee is a constant for holding the transaction_fee name
 const TransactionFee = "transaction_fee"
 
+// FeeSchedules is a constant for holding the fee_schedules name
+const FeeSchedules = "fee_schedules"
+
+// CurrencySpread is a constant for holding the currency_spread name
+const CurrencySpread = "currency_spread"
+
 // TransactionBaseLimit is a constant for holding the transaction_base_limit name
 const TransactionBaseLimit = "transaction_base_limit"
 
@@ -48,8 +178,27 @@ const WithdrawBaseLimit = "withdraw_base_limit"
 // DailyTransactionLimit is a constant for holding the daily_transaction_limit name
 const DailyTransactionLimit = "daily_transaction_limit"
 
+// DefaultTimeZone is a constant that holds the time zone of users that haven't set their own time zone
+const DefaultTimeZone = "Africa/Addis_Ababa"
+
+// LimitProfiles is a constant for holding the limit_profiles name
+const LimitProfiles = "limit_profiles"
+
+// MoneyTokenMinExpiry is a constant for holding the money_token_min_expiry name
+const MoneyTokenMinExpiry = "money_token_min_expiry"
+
+// MoneyTokenMaxExpiry is a constant for holding the money_token_max_expiry name
+const MoneyTokenMaxExpiry = "money_token_max_expiry"
+
+// QRSigningKeys is a constant for holding the qr_signing_keys name
+const QRSigningKeys = "qr_signing_keys"
+
+// QRAcceptUnsignedCodes is a constant for holding the qr_accept_unsigned_codes name
+const QRAcceptUnsignedCodes = "qr_accept_unsigned_codes"
+
 // ScopeAll is a constant that holds all usable scope values
-const ScopeAll = "profile, session, send, receive, pay, wallet, history, linkedaccount, moneytoken"
+// The staff scope is only given to internal api clients, staff routes also require the user to be a staff member.
+const ScopeAll = "profile, session, send, receive, pay, wallet, history, linkedaccount, moneytoken, hold, staff"
 
 // PasswordFault is a constant that holds the value password_fault-
 const PasswordFault = "password_fault-"
@@ -57,6 +206,15 @@ const PasswordFault = "password_fault-"
 // ReceiveFault is a constant that holds the value receive_fault-
 const ReceiveFault = "receive_fault-"
 
+// IdempotencyKeyPrefix is a constant that holds the value idempotency_key-
+const IdempotencyKeyPrefix = "idempotency_key-"
+
+// IdempotencyKeyHeader is a constant that holds the name of the header used for sending an idempotency key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// JobLockPrefix is a constant that holds the value job_lock-
+const JobLockPrefix = "job_lock-"
+
 // MessageIDPrefix is a constant that holds the value message_id-
 const MessageIDPrefix = "message_id-"
 
@@ -80,3 +238,115 @@ const MessageResetEmail = "/message.email.reset.json"
 
 // MessageResetSMS is a constant that defines a message tempalate path for resetting password message sent through sms
 const MessageResetSMS = "/message.sms.reset.json"
+
+// ReconciliationTriggerScheduled is a constant that defines a reconciliation report that has been made by the scheduled job
+// rather than by a staff member
+const ReconciliationTriggerScheduled = "Scheduled"
+
+// SettlementFormatCSV is a constant that defines a settlement statement in comma separated values
+const SettlementFormatCSV = "csv"
+
+// SettlementFormatFixedWidth is a constant that defines a settlement statement in the fixed width bank format
+const SettlementFormatFixedWidth = "fixed"
+
+// SettlementStatusMatched is a constant that defines a settlement item that agrees with its history
+const SettlementStatusMatched = "Matched"
+
+// SettlementStatusMismatched is a constant that defines a settlement item whose history differs in amount, type or date
+const SettlementStatusMismatched = "Mismatched"
+
+// SettlementStatusUnmatched is a constant that defines a settlement item that has no history with its reference
+const SettlementStatusUnmatched = "Unmatched"
+
+// SettlementStatusMissing is a constant that defines a recharge or withdrawal history the account provider hasn't settled
+const SettlementStatusMissing = "Missing"
+
+// SettlementStatusResolved is a constant that defines a settlement item that has been resolved by a staff member
+const SettlementStatusResolved = "Resolved"
+
+// MoneyTokenClaimFixed is a constant that defines a money token whose every claim receives the same fixed amount
+const MoneyTokenClaimFixed = "Fixed"
+
+// MoneyTokenClaimSplit is a constant that defines a money token whose amount is split between its claimants
+const MoneyTokenClaimSplit = "Split"
+
+// MoneyTokenStatusActive is a constant that defines a money token that can still be claimed
+const MoneyTokenStatusActive = "Active"
+
+// MoneyTokenStatusClaimed is a constant that defines a money token that has been fully claimed or paid
+const MoneyTokenStatusClaimed = "Claimed"
+
+// MoneyTokenStatusReclaimed is a constant that defines a money token whose money has been reclaimed by its sender
+const MoneyTokenStatusReclaimed = "Reclaimed"
+
+// MoneyTokenStatusExpired is a constant that defines a money token that hasn't been claimed before its expiration date
+const MoneyTokenStatusExpired = "Expired"
+
+// MoneyTokenStatusCancelled is a constant that defines a payment money token that has been removed by its sender
+const MoneyTokenStatusCancelled = "Cancelled"
+
+// JobRunStatusRunning is a constant that defines a job run that hasn't finished yet
+const JobRunStatusRunning = "Running"
+
+// JobRunStatusSucceeded is a constant that defines a job run that has finished without an error
+const JobRunStatusSucceeded = "Succeeded"
+
+// JobRunStatusFailed is a constant that defines a job run that has finished with an error
+const JobRunStatusFailed = "Failed"
+
+// JobReclaimMoneyTokens is a constant that holds the name of the job that reclaims expired money tokens
+const JobReclaimMoneyTokens = "reclaim_money_tokens"
+
+// JobScheduledTransfers is a constant that holds the name of the job that makes the due scheduled transfers
+const JobScheduledTransfers = "scheduled_transfers"
+
+// JobExpireHolds is a constant that holds the name of the job that releases the expired wallet holds
+const JobExpireHolds = "expire_holds"
+
+// JobReconcileWallets is a constant that holds the name of the job that reconciles the wallets with the user histories
+const JobReconcileWallets = "reconcile_wallets"
+
+// JobAuditLedger is a constant that holds the name of the job that audits the ledger and the wallets against it
+const JobAuditLedger = "audit_ledger"
+
+// JobPruneJobRuns is a constant that holds the name of the job that deletes the job runs older than the retention period
+const JobPruneJobRuns = "prune_job_runs"
+
+// QRPayloadScheme is a constant that holds the scheme of the payload encoded in OnePay qr codes
+const QRPayloadScheme = "onepay"
+
+// QRPayloadReceive is a constant that defines the payload type of a transaction money token, the scanner receives the money
+const QRPayloadReceive = "receive"
+
+// QRPayloadPay is a constant that defines the payload type of a payment money token, the scanner pays the money
+const QRPayloadPay = "pay"
+
+// QRCodeFormatPNG is a constant that defines a qr code rendered as a png image
+const QRCodeFormatPNG = "png"
+
+// QRCodeFormatSVG is a constant that defines a qr code rendered as a svg image
+const QRCodeFormatSVG = "svg"
+
+// QRPayloadFormatNative is a constant that defines a qr code that encodes the OnePay payload
+const QRPayloadFormatNative = "native"
+
+// QRPayloadFormatEMV is a constant that defines a qr code that encodes an EMVCo merchant presented payload
+const QRPayloadFormatEMV = "emv"
+
+// EMVStaticQRCode is a constant that holds the point of initiation method of a static EMVCo qr code
+const EMVStaticQRCode = "11"
+
+// EMVDynamicQRCode is a constant that holds the point of initiation method of a dynamic EMVCo qr code
+const EMVDynamicQRCode = "12"
+
+// EMVMerchantAccountGUI is a constant that holds the globally unique identifier of OnePay in the merchant account information of EMVCo qr codes
+const EMVMerchantAccountGUI = "et.onepay"
+
+// EMVMerchantCategoryCode is a constant that holds the merchant category code used in EMVCo qr codes of OnePay merchants
+const EMVMerchantCategoryCode = "0000"
+
+// EMVCountryCode is a constant that holds the country code used in EMVCo qr codes of OnePay merchants
+const EMVCountryCode = "ET"
+
+// EMVMerchantCity is a constant that holds the merchant city used in EMVCo qr codes of OnePay merchants
+const EMVMerchantCity = "Addis Ababa"
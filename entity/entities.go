@@ -39,10 +39,16 @@ type UserPassword struct {
 
 // UserWallet is a type that defines a OnePay user wallet
 type UserWallet struct {
-	UserID    string  `gorm:"primary_key; unique; not null"`
-	Amount    float64 `gorm:"not null"`
-	Seen      bool    `gorm:"default: true;"`
+	UserID    string `gorm:"primary_key; not null"`
+	Currency  string `gorm:"primary_key; not null; default: 'ETB'"`
+	Amount    Money  `gorm:"type:bigint; not null"`
+	Held      Money  `gorm:"type:bigint; not null; default: 0"`
+	Seen      bool   `gorm:"default: true;"`
+	Version   int64  `gorm:"not null; default: 0"`
 	UpdatedAt time.Time
+
+	// Quarantined wallets can still receive money but no money can leave them until a staff member releases them
+	Quarantined bool `gorm:"not null; default: false"`
 }
 
 // UserHistory is a type that defines a OnePay user's history
@@ -52,17 +58,33 @@ type UserHistory struct {
 	ReceiverID   string `gorm:"not null"`
 	SentAt       time.Time
 	ReceivedAt   time.Time
-	Method       string  `gorm:"not null"`
-	Code         string  `gorm:"not null"`
-	Amount       float64 `gorm:"not null"`
-	SenderSeen   bool    `gorm:"default: false;"`
-	ReceiverSeen bool    `gorm:"default: false;"`
+	Method       string `gorm:"not null"`
+	Code         string `gorm:"not null"`
+	Amount       Money  `gorm:"type:bigint; not null"`
+	Fee          Money  `gorm:"type:bigint; not null; default: 0"`
+	Currency     string `gorm:"not null; default: 'ETB'"`
+	SenderSeen   bool   `gorm:"default: false;"`
+	ReceiverSeen bool   `gorm:"default: false;"`
+
+	// ParentID references the history a refund or a reversal returns money of, or the authorization a hold capture or release completes.
+	// It is zero for any other history
+	ParentID int `gorm:"not null; default: 0"`
+
+	// Currency conversion values, only set for histories of converted money
+	ConvertedAmount   Money   `gorm:"type:bigint; not null; default: 0"`
+	ConvertedCurrency string  `gorm:"not null; default: ''"`
+	ExchangeRate      float64 `gorm:"not null; default: 0"`
+	Spread            float64 `gorm:"not null; default: 0"`
+
+	// Invoice details of the payment token that has been paid, empty for any other history
+	Invoice
 }
 
 // UserPreference is a type that defines a OnePay user preference
 type UserPreference struct {
 	UserID              string `gorm:"primary_key; unique; not null"`
 	TwoStepVerification bool   `gorm:"not null; default: false"`
+	TimeZone            string `gorm:"not null; default: 'Africa/Addis_Ababa'"`
 }
 
 // MoneyToken is a type that defines a token generated for qr code
@@ -70,9 +92,39 @@ type MoneyToken struct {
 	Code           string `gorm:"primary_key; unique; not null"`
 	SenderID       string `gorm:"not null"`
 	SentAt         time.Time
-	Amount         float64 `gorm:"not null"`
+	Amount         Money  `gorm:"type:bigint; not null"`
+	Fee            Money  `gorm:"type:bigint; not null; default: 0"`
+	Currency       string `gorm:"not null; default: 'ETB'"`
 	ExpirationDate time.Time
 	Method         string `gorm:"not null"`
+
+	// A money token can be claimed by up to MaxClaims different users, Remaining and RemainingFee hold the
+	// part of the amount and fee that is still locked in the token for the claims that haven't been made yet
+	ClaimMode    string `gorm:"not null; default: 'Fixed'"`
+	MaxClaims    int    `gorm:"not null; default: 1"`
+	ClaimCount   int    `gorm:"not null; default: 0"`
+	Remaining    Money  `gorm:"type:bigint; not null; default: 0"`
+	RemainingFee Money  `gorm:"type:bigint; not null; default: 0"`
+
+	// Protected money tokens can only be claimed with the secret attached by the sender, the secret is stored hashed
+	// and the money token gets locked after too many failed attempts
+	Protected      bool   `gorm:"not null; default: false"`
+	Secret         string `json:"-" xml:"-"`
+	SecretSalt     string `json:"-" xml:"-"`
+	FailedAttempts int    `gorm:"not null; default: 0"`
+	Locked         bool   `gorm:"not null; default: false"`
+
+	// Money tokens are kept once they are closed so their senders can see what happened to them.
+	// ClaimantID and ClaimedAt hold the latest claim, every claim is also recorded as a money token claim.
+	Status      string `gorm:"not null; default: 'Active'"`
+	ClaimantID  string
+	ClaimedAt   *time.Time
+	ReclaimedAt *time.Time
+	ExpiredAt   *time.Time
+	CancelledAt *time.Time
+
+	// Payment money tokens can carry the details of the merchant order they are paying for
+	Invoice
 }
 
 // LinkedAccount is a type that defines an account that is linked with OnePay account
@@ -91,6 +143,251 @@ type AccountProvider struct {
 	CreatedAt time.Time
 }
 
+// LedgerAccount is a type that defines an account in the OnePay double-entry ledger
+type LedgerAccount struct {
+	ID        string `gorm:"primary_key; unique; not null"`
+	Type      string `gorm:"not null"`
+	OwnerID   string `gorm:"not null"`
+	Currency  string `gorm:"not null; default: 'ETB'"`
+	CreatedAt time.Time
+}
+
+// JournalEntry is a type that defines a balanced set of postings recorded in the ledger
+type JournalEntry struct {
+	ID        int        `gorm:"primary_key; unique; not null"`
+	Method    string     `gorm:"not null"`
+	Code      string     `gorm:"not null"`
+	Postings  []*Posting `gorm:"foreignkey:JournalEntryID"`
+	CreatedAt time.Time
+}
+
+// Posting is a type that defines a single movement of money into or out of a ledger account.
+// A positive amount increases the account balance while a negative amount decreases it.
+type Posting struct {
+	ID             int    `gorm:"primary_key; unique; not null"`
+	JournalEntryID int    `gorm:"not null"`
+	AccountID      string `gorm:"not null"`
+	Amount         Money  `gorm:"type:bigint; not null"`
+	Currency       string `gorm:"not null; default: 'ETB'"`
+}
+
+// Dispute is a type that defines a user's claim against a payment the user did not authorise.
+// While a dispute is open the disputed amount is held from the counterparty's wallet.
+type Dispute struct {
+	ID              int    `gorm:"primary_key; unique; not null"`
+	HistoryID       int    `gorm:"not null"`
+	OpenerID        string `gorm:"not null"`
+	CounterpartyID  string `gorm:"not null"`
+	Amount          Money  `gorm:"type:bigint; not null"`
+	HeldAmount      Money  `gorm:"type:bigint; not null; default: 0"`
+	RecoveredAmount Money  `gorm:"type:bigint; not null; default: 0"`
+	Currency        string `gorm:"not null; default: 'ETB'"`
+	Reason          string `gorm:"not null"`
+	Status          string `gorm:"not null"`
+	Resolution      string
+	ResolvedBy      string
+	Deadline        time.Time
+	ResolvedAt      *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// ScheduledTransfer is a type that defines a transfer via onepay id that is made automatically at a future time,
+// either once or repeatedly until its end date
+type ScheduledTransfer struct {
+	ID          int    `gorm:"primary_key; unique; not null"`
+	SenderID    string `gorm:"not null"`
+	ReceiverID  string `gorm:"not null"`
+	Amount      Money  `gorm:"type:bigint; not null"`
+	Currency    string `gorm:"not null; default: 'ETB'"`
+	Frequency   string `gorm:"not null"`
+	StartAt     time.Time
+	EndDate     *time.Time
+	Occurrences int `gorm:"not null; default: 0"`
+	ScheduledAt time.Time
+	NextRunAt   time.Time
+	Attempts    int    `gorm:"not null; default: 0"`
+	Status      string `gorm:"not null"`
+	LastError   string
+	LastRunAt   *time.Time
+	ClaimedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// PaymentRequest is a type that defines a user's request for money from another user, the payer can pay or decline it until it expires
+type PaymentRequest struct {
+	ID          int    `gorm:"primary_key; unique; not null"`
+	RequesterID string `gorm:"not null"`
+	PayerID     string `gorm:"not null"`
+	Amount      Money  `gorm:"type:bigint; not null"`
+	Currency    string `gorm:"not null; default: 'ETB'"`
+	Note        string
+	Status      string `gorm:"not null"`
+	HistoryID   int    `gorm:"not null; default: 0"`
+	GroupID     int    `gorm:"not null; default: 0"`
+	ExpiresAt   time.Time
+	RespondedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// PaymentGroup is a type that defines a bill split between a group of users.
+// Each participant gets a payment request for its share and the group is completed once every share has been paid.
+type PaymentGroup struct {
+	ID             int    `gorm:"primary_key; unique; not null"`
+	InitiatorID    string `gorm:"not null"`
+	Total          Money  `gorm:"type:bigint; not null"`
+	Currency       string `gorm:"not null; default: 'ETB'"`
+	Description    string
+	Status         string `gorm:"not null"`
+	LastRemindedAt *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// WalletHold is a type that defines money a merchant has reserved on a user's wallet at checkout.
+// The held money can later be captured by the merchant, fully or partially, or released back to the user.
+type WalletHold struct {
+	ID          int    `gorm:"primary_key; unique; not null"`
+	UserID      string `gorm:"not null"`
+	MerchantID  string `gorm:"not null"`
+	APIKey      string `gorm:"not null"`
+	Amount      Money  `gorm:"type:bigint; not null"`
+	Captured    Money  `gorm:"type:bigint; not null; default: 0"`
+	Currency    string `gorm:"not null; default: 'ETB'"`
+	Reference   string
+	Status      string `gorm:"not null"`
+	HistoryID   int    `gorm:"not null; default: 0"`
+	ExpiresAt   time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// The usage keys and the base currency amount the hold has counted against the limits of the user and the merchant,
+	// so the money that isn't captured can be given back to the limits
+	LimitKeys   string `gorm:"type:text" json:"-" xml:"-"`
+	LimitAmount Money  `gorm:"type:bigint; not null; default: 0" json:"-" xml:"-"`
+}
+
+// UserLimit is a type that defines the verification tier of a user and the limit profile a staff member has assigned to it.
+// An empty profile means the user gets the limit profile of its tier.
+type UserLimit struct {
+	UserID    string `gorm:"primary_key; unique; not null"`
+	Tier      string `gorm:"not null; default: 'unverified'"`
+	Profile   string
+	StaffID   string
+	UpdatedAt time.Time
+}
+
+// ReconciliationReport is a type that defines a single run of the wallet reconciliation
+type ReconciliationReport struct {
+	ID               int    `gorm:"primary_key; unique; not null"`
+	TriggeredBy      string `gorm:"not null"`
+	WalletCount      int    `gorm:"not null; default: 0"`
+	DiscrepancyCount int    `gorm:"not null; default: 0"`
+	QuarantineCount  int    `gorm:"not null; default: 0"`
+	StartedAt        time.Time
+	FinishedAt       *time.Time
+}
+
+// WalletDiscrepancy is a type that defines a wallet whose balance doesn't match the balance expected from its history
+type WalletDiscrepancy struct {
+	ID             int    `gorm:"primary_key; unique; not null"`
+	ReportID       int    `gorm:"not null"`
+	UserID         string `gorm:"not null"`
+	Currency       string `gorm:"not null; default: 'ETB'"`
+	ExpectedAmount Money  `gorm:"type:bigint; not null"`
+	ActualAmount   Money  `gorm:"type:bigint; not null"`
+	ExpectedHeld   Money  `gorm:"type:bigint; not null; default: 0"`
+	ActualHeld     Money  `gorm:"type:bigint; not null; default: 0"`
+	Quarantined    bool   `gorm:"not null; default: false"`
+	CreatedAt      time.Time
+}
+
+// SettlementStatement is a type that defines a settlement file of an account provider that has been imported for reconciliation
+type SettlementStatement struct {
+	ID                int    `gorm:"primary_key; unique; not null"`
+	AccountProviderID string `gorm:"not null"`
+	FileName          string `gorm:"not null"`
+	Format            string `gorm:"not null"`
+	ImportedBy        string `gorm:"not null"`
+	LineCount         int    `gorm:"not null; default: 0"`
+	MatchedCount      int    `gorm:"not null; default: 0"`
+	MismatchedCount   int    `gorm:"not null; default: 0"`
+	UnmatchedCount    int    `gorm:"not null; default: 0"`
+	MissingCount      int    `gorm:"not null; default: 0"`
+	PeriodStart       time.Time
+	PeriodEnd         time.Time
+	ImportedAt        time.Time
+}
+
+// SettlementItem is a type that defines a single line of a settlement statement, or a recharge or withdrawal history
+// that the account provider has left out of its statement, together with the result of matching it
+type SettlementItem struct {
+	ID                int    `gorm:"primary_key; unique; not null"`
+	StatementID       int    `gorm:"not null"`
+	AccountProviderID string `gorm:"not null"`
+	Reference         string `gorm:"not null"`
+	Method            string `gorm:"not null"`
+	Amount            Money  `gorm:"type:bigint; not null"`
+	Currency          string `gorm:"not null; default: 'ETB'"`
+	SettledAt         time.Time
+	HistoryID         int    `gorm:"not null; default: 0"`
+	Status            string `gorm:"not null"`
+	Reason            string
+	Note              string
+	ResolvedBy        string
+	ResolvedAt        *time.Time
+}
+
+// MoneyTokenClaim is a type that defines a single claim made on a money token, a user can claim a money token only once
+type MoneyTokenClaim struct {
+	ID         int    `gorm:"primary_key; unique; not null"`
+	Code       string `gorm:"not null; unique_index:idx_money_token_claimant"`
+	ClaimantID string `gorm:"not null; unique_index:idx_money_token_claimant"`
+	Amount     Money  `gorm:"type:bigint; not null"`
+	Fee        Money  `gorm:"type:bigint; not null; default: 0"`
+	Currency   string `gorm:"not null; default: 'ETB'"`
+	ClaimedAt  time.Time
+}
+
+// JobRun is a type that defines a single run of a scheduled job by one of the server instances
+type JobRun struct {
+	ID         int    `gorm:"primary_key; unique; not null"`
+	JobName    string `gorm:"not null"`
+	Instance   string `gorm:"not null"`
+	Status     string `gorm:"not null"`
+	Error      string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+}
+
+// DisputeEvidence is a type that defines a statement and an optional attachment submitted for a dispute
+type DisputeEvidence struct {
+	ID          int    `gorm:"primary_key; unique; not null"`
+	DisputeID   int    `gorm:"not null"`
+	SubmitterID string `gorm:"not null"`
+	Description string `gorm:"not null"`
+	FileName    string
+	CreatedAt   time.Time
+}
+
+// WalletAudit is a type that defines the result of comparing a user wallet with its ledger account
+type WalletAudit struct {
+	UserID        string
+	WalletAmount  Money
+	LedgerBalance Money
+}
+
+// LedgerAudit is a type that defines the result of auditing the ledger, whether all of its postings sum up to zero
+// and the wallets whose amount doesn't match their ledger account
+type LedgerAudit struct {
+	Balanced  bool
+	Wallets   []*WalletAudit
+	AuditedAt time.Time
+}
+
 // Extras is a type that defines values that are required but extra in definition
 type Extras struct {
 	TotalUsersCount int
@@ -143,7 +440,7 @@ func (UserHistory) TableName() string {
 
 // AccountInfo is type that defines an external account information
 type AccountInfo struct {
-	Amount            float64
+	Amount            Money
 	AccountID         string
 	AccountProviderID string
 }
@@ -183,6 +480,15 @@ type CurrencyRate struct {
 	Dates        []time.Time
 }
 
+// IdempotentResponse is a struct that defines the stored outcome of a request made with an idempotency key
+type IdempotentResponse struct {
+	Fingerprint string
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
 // Key is a type that defines a key type that can be used a key value in context
 type Key string
 
@@ -213,8 +519,9 @@ func (history *UserHistory) Equal(opHistory *UserHistory) bool {
 	check6 := history.SentAt.Unix() == opHistory.SentAt.Unix()
 	check7 := history.ReceiverID == opHistory.ReceiverID
 	check8 := history.SenderID == opHistory.SenderID
+	check9 := history.Fee == opHistory.Fee
 
-	return check1 && check2 && check3 && check4 && check5 && check6 && check7 && check8
+	return check1 && check2 && check3 && check4 && check5 && check6 && check7 && check8 && check9
 }
 
 // Equal is a method that checks if the two history objects are identical
@@ -235,6 +542,220 @@ func (moneyToken *MoneyToken) Equal(opMoneyToken *MoneyToken) bool {
 	check4 := moneyToken.SentAt.Unix() == opMoneyToken.SentAt.Unix()
 	check5 := moneyToken.ExpirationDate.Unix() == opMoneyToken.ExpirationDate.Unix()
 	check6 := moneyToken.Method == opMoneyToken.Method
+	check7 := moneyToken.Fee == opMoneyToken.Fee
+
+	return check1 && check2 && check3 && check4 && check5 && check6 && check7
+}
+
+// Equal is a method that checks if the two journal entry objects are identical
+func (journalEntry *JournalEntry) Equal(opJournalEntry *JournalEntry) bool {
+
+	if len(journalEntry.Postings) != len(opJournalEntry.Postings) {
+		return false
+	}
+
+	for index, posting := range journalEntry.Postings {
+		opPosting := opJournalEntry.Postings[index]
+		if posting.AccountID != opPosting.AccountID || posting.Amount != opPosting.Amount {
+			return false
+		}
+	}
+
+	check1 := journalEntry.Method == opJournalEntry.Method
+	check2 := journalEntry.Code == opJournalEntry.Code
+	check3 := journalEntry.CreatedAt.Unix() == opJournalEntry.CreatedAt.Unix()
+
+	return check1 && check2 && check3
+}
+
+// BeforeSave is a gorm hook that keeps the wallet currency column and the currency of its amount the same
+func (wallet *UserWallet) BeforeSave() error {
+	if wallet.Currency == "" {
+		wallet.Currency = NewMoney(0, wallet.Amount.Currency).Currency
+	}
+	wallet.Amount.Currency = wallet.Currency
+	wallet.Held.Currency = wallet.Currency
+	return nil
+}
+
+// AfterFind is a gorm hook that sets the currency of the wallet amounts from the currency column
+func (wallet *UserWallet) AfterFind() error {
+	wallet.Amount.Currency = wallet.Currency
+	wallet.Held.Currency = wallet.Currency
+	return nil
+}
+
+// BeforeSave is a gorm hook that stores the currency of the history amount in the currency columns
+func (history *UserHistory) BeforeSave() error {
+	history.Currency = NewMoney(0, history.Amount.Currency).Currency
+	if !history.ConvertedAmount.IsZero() {
+		history.ConvertedCurrency = history.ConvertedAmount.Currency
+	}
+	return nil
+}
+
+// AfterFind is a gorm hook that sets the currency of the history amounts from the currency columns
+func (history *UserHistory) AfterFind() error {
+	history.Amount.Currency = history.Currency
+	history.Fee.Currency = history.Currency
+	history.ConvertedAmount.Currency = history.ConvertedCurrency
+	return nil
+}
+
+// BeforeSave is a gorm hook that stores the currency of the money token amount in the currency column
+func (moneyToken *MoneyToken) BeforeSave() error {
+	moneyToken.Currency = NewMoney(0, moneyToken.Amount.Currency).Currency
+	return nil
+}
+
+// AfterFind is a gorm hook that sets the currency of the money token amounts from the currency column
+func (moneyToken *MoneyToken) AfterFind() error {
+	moneyToken.Amount.Currency = moneyToken.Currency
+	moneyToken.Fee.Currency = moneyToken.Currency
+	moneyToken.Remaining.Currency = moneyToken.Currency
+	moneyToken.RemainingFee.Currency = moneyToken.Currency
+	return nil
+}
+
+// IsActive is a method that checks whether the money token can still be claimed, reclaimed or refreshed
+func (moneyToken *MoneyToken) IsActive() bool {
+	return moneyToken.Status == MoneyTokenStatusActive
+}
+
+// ClaimsLeft is a method that returns the number of claims that can still be made on the money token
+func (moneyToken *MoneyToken) ClaimsLeft() int {
+	return moneyToken.MaxClaims - moneyToken.ClaimCount
+}
+
+// NextClaim is a method that returns the amount and fee of the next claim made on the money token.
+// The remaining money is divided equally between the claims left and the last claim takes whatever is left over.
+func (moneyToken *MoneyToken) NextClaim() (Money, Money) {
+
+	claimsLeft := int64(moneyToken.ClaimsLeft())
+	if claimsLeft <= 1 {
+		return moneyToken.Remaining, moneyToken.RemainingFee
+	}
+
+	return Money{Minor: moneyToken.Remaining.Minor / claimsLeft, Currency: moneyToken.Remaining.Currency},
+		Money{Minor: moneyToken.RemainingFee.Minor / claimsLeft, Currency: moneyToken.RemainingFee.Currency}
+}
+
+// BeforeSave is a gorm hook that stores the currency of the posting amount in the currency column
+func (posting *Posting) BeforeSave() error {
+	posting.Currency = NewMoney(0, posting.Amount.Currency).Currency
+	return nil
+}
+
+// AfterFind is a gorm hook that sets the currency of the posting amount from the currency column
+func (posting *Posting) AfterFind() error {
+	posting.Amount.Currency = posting.Currency
+	return nil
+}
+
+// BeforeSave is a gorm hook that stores the currency of the dispute amount in the currency column
+func (dispute *Dispute) BeforeSave() error {
+	dispute.Currency = NewMoney(0, dispute.Amount.Currency).Currency
+	return nil
+}
+
+// AfterFind is a gorm hook that sets the currency of the dispute amounts from the currency column
+func (dispute *Dispute) AfterFind() error {
+	dispute.Amount.Currency = dispute.Currency
+	dispute.HeldAmount.Currency = dispute.Currency
+	dispute.RecoveredAmount.Currency = dispute.Currency
+	return nil
+}
+
+// IsActive is a method that checks whether the dispute is still waiting for a resolution
+func (dispute *Dispute) IsActive() bool {
+	return dispute.Status == DisputeStatusOpen || dispute.Status == DisputeStatusUnderReview
+}
+
+// BeforeSave is a gorm hook that stores the currency of the scheduled transfer amount in the currency column
+func (transfer *ScheduledTransfer) BeforeSave() error {
+	transfer.Currency = NewMoney(0, transfer.Amount.Currency).Currency
+	return nil
+}
+
+// AfterFind is a gorm hook that sets the currency of the scheduled transfer amount from the currency column
+func (transfer *ScheduledTransfer) AfterFind() error {
+	transfer.Amount.Currency = transfer.Currency
+	return nil
+}
+
+// BeforeSave is a gorm hook that stores the currency of the payment request amount in the currency column
+func (request *PaymentRequest) BeforeSave() error {
+	request.Currency = NewMoney(0, request.Amount.Currency).Currency
+	return nil
+}
+
+// AfterFind is a gorm hook that sets the currency of the payment request amount from the currency column
+func (request *PaymentRequest) AfterFind() error {
+	request.Amount.Currency = request.Currency
+	return nil
+}
+
+// BeforeSave is a gorm hook that stores the currency of the payment group total in the currency column
+func (group *PaymentGroup) BeforeSave() error {
+	group.Currency = NewMoney(0, group.Total.Currency).Currency
+	return nil
+}
+
+// AfterFind is a gorm hook that sets the currency of the payment group total from the currency column
+func (group *PaymentGroup) AfterFind() error {
+	group.Total.Currency = group.Currency
+	return nil
+}
+
+// BeforeSave is a gorm hook that stores the currency of the hold amount in the currency column
+func (hold *WalletHold) BeforeSave() error {
+	hold.Currency = NewMoney(0, hold.Amount.Currency).Currency
+	return nil
+}
+
+// AfterFind is a gorm hook that sets the currency of the hold amounts from the currency column
+func (hold *WalletHold) AfterFind() error {
+	hold.Amount.Currency = hold.Currency
+	hold.Captured.Currency = hold.Currency
+	return nil
+}
+
+// BeforeSave is a gorm hook that stores the currency of the discrepancy amounts in the currency column
+func (discrepancy *WalletDiscrepancy) BeforeSave() error {
+	discrepancy.Currency = NewMoney(0, discrepancy.ActualAmount.Currency).Currency
+	return nil
+}
+
+// AfterFind is a gorm hook that sets the currency of the discrepancy amounts from the currency column
+func (discrepancy *WalletDiscrepancy) AfterFind() error {
+	discrepancy.ExpectedAmount.Currency = discrepancy.Currency
+	discrepancy.ActualAmount.Currency = discrepancy.Currency
+	discrepancy.ExpectedHeld.Currency = discrepancy.Currency
+	discrepancy.ActualHeld.Currency = discrepancy.Currency
+	return nil
+}
+
+// BeforeSave is a gorm hook that stores the currency of the settlement item amount in the currency column
+func (item *SettlementItem) BeforeSave() error {
+	item.Currency = NewMoney(0, item.Amount.Currency).Currency
+	return nil
+}
+
+// AfterFind is a gorm hook that sets the currency of the settlement item amount from the currency column
+func (item *SettlementItem) AfterFind() error {
+	item.Amount.Currency = item.Currency
+	return nil
+}
+
+// BeforeSave is a gorm hook that stores the currency of the money token claim amounts in the currency column
+func (claim *MoneyTokenClaim) BeforeSave() error {
+	claim.Currency = NewMoney(0, claim.Amount.Currency).Currency
+	return nil
+}
 
-	return check1 && check2 && check3 && check4 && check5 && check6
+// AfterFind is a gorm hook that sets the currency of the money token claim amounts from the currency column
+func (claim *MoneyTokenClaim) AfterFind() error {
+	claim.Amount.Currency = claim.Currency
+	claim.Fee.Currency = claim.Currency
+	return nil
 }
@@ -9,9 +9,81 @@ const MoneyTokenCheckpointError = "money token checkpoint error"
 // HistoryCheckpointError is a constant for holding the error value 'history checkpoint error'
 const HistoryCheckpointError = "history checkpoint error"
 
+// JournalEntryCheckpointError is a constant for holding the error value 'journal entry checkpoint error'
+const JournalEntryCheckpointError = "journal entry checkpoint error"
+
+// UnbalancedJournalEntryError is a constant that holds journal entry postings doesn't balance error
+const UnbalancedJournalEntryError = "journal entry postings do not balance"
+
+// WalletConflictError is a constant that holds wallet has been changed by another operation error
+const WalletConflictError = "wallet has been changed by another operation"
+
+// TransactionCommitError is a constant that holds unable to commit transaction error
+const TransactionCommitError = "unable to commit transaction"
+
+// IdempotencyKeyReusedError is a constant that holds idempotency key has been used with a different request error
+const IdempotencyKeyReusedError = "idempotency key has already been used with a different request"
+
+// IdempotentRequestInProgressError is a constant that holds request with the same idempotency key is in progress error
+const IdempotentRequestInProgressError = "a request with the same idempotency key is still being processed"
+
 // TransactionBaseLimitError is a constant that holds transaction base limit error
 const TransactionBaseLimitError = "amount is less than transaction base limit"
 
+// FeeExceedsAmountError is a constant that holds amount doesn't cover the fee error
+const FeeExceedsAmountError = "amount is not enough to cover the fee"
+
+// UnsupportedCurrencyError is a constant that holds unsupported currency error
+const UnsupportedCurrencyError = "unsupported currency"
+
+// ExchangeRateNotFoundError is a constant that holds exchange rate not found error
+const ExchangeRateNotFoundError = "exchange rate is not available for the provided currencies"
+
+// SameCurrencyConversionError is a constant that holds converting to the same currency error
+const SameCurrencyConversionError = "can not convert money to the same currency"
+
+// NonRefundableHistoryError is a constant that holds history can not be refunded error
+const NonRefundableHistoryError = "history can not be refunded"
+
+// RefundExceedsAmountError is a constant that holds refund exceeds the refundable amount error
+const RefundExceedsAmountError = "amount exceeds the refundable amount of the history"
+
+// FullyRefundedError is a constant that holds history has already been fully refunded error
+const FullyRefundedError = "history has already been fully refunded"
+
+// InvalidFrequencyError is a constant that holds invalid frequency used error
+const InvalidFrequencyError = "invalid frequency used"
+
+// InvalidScheduleError is a constant that holds invalid schedule used error
+const InvalidScheduleError = "invalid schedule used, start time should be in the future and before the end date"
+
+// ExpiredPaymentRequestError is a constant that holds payment request has expired error
+const ExpiredPaymentRequestError = "payment request has expired"
+
+// ClosedPaymentRequestError is a constant that holds payment request is no longer pending error
+const ClosedPaymentRequestError = "payment request is no longer pending"
+
+// InvalidSharesError is a constant that holds invalid shares used error
+const InvalidSharesError = "invalid shares used, every participant should have a positive share"
+
+// ClosedHoldError is a constant that holds hold is no longer authorized error
+const ClosedHoldError = "hold is no longer authorized"
+
+// CaptureExceedsHoldError is a constant that holds capture amount exceeds the held amount error
+const CaptureExceedsHoldError = "capture amount exceeds the held amount"
+
+// DisputedHistoryError is a constant that holds history has an active dispute error
+const DisputedHistoryError = "history has an active dispute"
+
+// NonDisputableHistoryError is a constant that holds history can not be disputed error
+const NonDisputableHistoryError = "history can not be disputed"
+
+// DisputeClosedError is a constant that holds dispute has already been resolved error
+const DisputeClosedError = "dispute has already been resolved"
+
+// DisputeDeadlineError is a constant that holds dispute deadline has passed error
+const DisputeDeadlineError = "dispute deadline has passed"
+
 // WithdrawBaseLimitError is a constant that holds withdraw base limit error
 const WithdrawBaseLimitError = "amount is less than the withdraw base limit"
 
@@ -62,3 +134,120 @@ const InvalidMethodError = "invalid method, code not found"
 
 // InvalidMoneyTokenError is a constant that holds invalid money token used error
 const InvalidMoneyTokenError = "invalid money token used"
+
+// TransactionLimitError is a constant that holds transaction limit error
+const TransactionLimitError = "amount is above the transaction limit"
+
+// WeeklyTransactionLimitError is a constant that holds weekly transaction limit error
+const WeeklyTransactionLimitError = "user has exceeded weekly transaction limit"
+
+// MonthlyTransactionLimitError is a constant that holds monthly transaction limit error
+const MonthlyTransactionLimitError = "user has exceeded monthly transaction limit"
+
+// ReceiveLimitError is a constant that holds receive limit error
+const ReceiveLimitError = "receiver has exceeded its receive limit"
+
+// WithdrawLimitError is a constant that holds withdraw limit error
+const WithdrawLimitError = "user has exceeded withdraw limit"
+
+// MaxBalanceError is a constant that holds maximum wallet balance error
+const MaxBalanceError = "wallet balance would exceed the maximum balance limit"
+
+// InvalidTierError is a constant that holds invalid verification tier error
+const InvalidTierError = "invalid verification tier used"
+
+// LimitProfileNotFoundError is a constant that holds limit profile not found error
+const LimitProfileNotFoundError = "limit profile not found"
+
+// QuarantinedWalletError is a constant that holds quarantined wallet error
+const QuarantinedWalletError = "wallet has been quarantined for reconciliation"
+
+// InvalidSettlementFormatError is a constant that holds invalid settlement statement format error
+const InvalidSettlementFormatError = "invalid settlement statement format used"
+
+// InvalidSettlementLineError is a constant that holds invalid settlement statement line error
+const InvalidSettlementLineError = "settlement statement has an invalid line"
+
+// EmptySettlementError is a constant that holds empty settlement statement error
+const EmptySettlementError = "settlement statement has no line"
+
+// SettlementItemClosedError is a constant that holds settlement item that doesn't need to be resolved error
+const SettlementItemClosedError = "settlement item doesn't need to be resolved"
+
+// InvalidClaimModeError is a constant that holds invalid money token claim mode error
+const InvalidClaimModeError = "invalid money token claim mode used"
+
+// InvalidMaxClaimsError is a constant that holds invalid money token max claims error
+const InvalidMaxClaimsError = "invalid number of money token claims used"
+
+// MoneyTokenClaimedError is a constant that holds money token already claimed by the user error
+const MoneyTokenClaimedError = "money token has already been claimed by the user"
+
+// InvalidSecretFormatError is a constant that holds invalid money token secret format error
+const InvalidSecretFormatError = "money token secret should be 4 to 12 letters or digits"
+
+// InvalidMoneyTokenSecretError is a constant that holds invalid money token secret used error
+const InvalidMoneyTokenSecretError = "invalid money token secret used"
+
+// LockedMoneyTokenError is a constant that holds money token locked after too many failed attempts error
+const LockedMoneyTokenError = "money token has been locked after too many failed attempts"
+
+// InactiveMoneyTokenError is a constant that holds money token no longer active error
+const InactiveMoneyTokenError = "money token is no longer active"
+
+// InvalidExpiryError is a constant that holds money token expiry out of the allowed bounds error
+const InvalidExpiryError = "money token expiry is out of the allowed bounds"
+
+// InvalidQRCodeSizeError is a constant that holds qr code size out of the allowed bounds error
+const InvalidQRCodeSizeError = "qr code size is out of the allowed bounds"
+
+// InvalidQRCodeLevelError is a constant that holds invalid qr code error correction level error
+const InvalidQRCodeLevelError = "invalid qr code error correction level used"
+
+// QRCodeLogoNotFoundError is a constant that holds qr code logo not found error
+const QRCodeLogoNotFoundError = "qr code logo not found"
+
+// InvalidQRSignatureError is a constant that holds invalid qr code payload signature error
+const InvalidQRSignatureError = "qr code signature is invalid"
+
+// QRPayloadMismatchError is a constant that holds qr code payload not matching its money token error
+const QRPayloadMismatchError = "qr code payload doesn't match the money token"
+
+// InvalidEMVQRCodeError is a constant that holds invalid EMVCo qr code error
+const InvalidEMVQRCodeError = "invalid emv qr code"
+
+// ForeignEMVQRCodeError is a constant that holds EMVCo qr code that doesn't belong to a OnePay merchant error
+const ForeignEMVQRCodeError = "emv qr code doesn't belong to a OnePay merchant"
+
+// StaticQRCodeAmountError is a constant that holds static merchant qr code paid without an amount error
+const StaticQRCodeAmountError = "amount is needed to pay a static merchant qr code"
+
+// QRCodeCurrencyError is a constant that holds amount not in the currency of the qr code error
+const QRCodeCurrencyError = "amount currency doesn't match the qr code currency"
+
+// InvalidQRPayloadFormatError is a constant that holds invalid qr code payload format error
+const InvalidQRPayloadFormatError = "invalid qr code payload format used"
+
+// InvalidOrderIDError is a constant that holds invalid invoice order id error
+const InvalidOrderIDError = "invalid order id used"
+
+// InvalidDescriptionError is a constant that holds invalid invoice description error
+const InvalidDescriptionError = "invalid description used"
+
+// InvalidCallbackRefError is a constant that holds invalid invoice callback reference error
+const InvalidCallbackRefError = "invalid callback reference used"
+
+// InvalidLineItemsError is a constant that holds invalid invoice line items error
+const InvalidLineItemsError = "invalid line items used"
+
+// LineItemsTotalError is a constant that holds invoice line items not adding up to the amount error
+const LineItemsTotalError = "line items don't add up to the amount"
+
+// ScheduledTransferInterruptedError is a constant that holds the error of a scheduled transfer run that never finished
+const ScheduledTransferInterruptedError = "scheduled transfer run has been interrupted"
+
+// UnbalancedLedgerError is a constant that holds the error of a ledger whose postings don't sum up to zero
+const UnbalancedLedgerError = "ledger postings don't sum up to zero"
+
+// WalletBalanceChangedError is a constant that holds the error of a wallet balance that changed during an operation
+const WalletBalanceChangedError = "wallet balance has changed, please try again"
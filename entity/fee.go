@@ -0,0 +1,66 @@
+package entity
+
+// FeeTypeFlat is a constant that defines a fee schedule that charges the same amount for any transaction
+const FeeTypeFlat = "flat"
+
+// FeeTypePercentage is a constant that defines a fee schedule that charges a percentage of the transaction amount
+const FeeTypePercentage = "percentage"
+
+// FeeTypeTiered is a constant that defines a fee schedule that charges differently based on the transaction amount range
+const FeeTypeTiered = "tiered"
+
+// FeeTypeCapped is a constant that defines a fee schedule that charges a percentage of the transaction amount
+// but never less than the minimum or more than the maximum fee
+const FeeTypeCapped = "capped"
+
+// FeeSchedule is a type that defines how the fee of a certain method is calculated
+type FeeSchedule struct {
+	Method     string
+	Type       string
+	Flat       Money
+	Percentage float64
+	Tiers      []*FeeTier
+	Minimum    Money
+	Maximum    Money
+}
+
+// FeeTier is a type that defines the fee charged for transactions whose amount is up to a certain limit.
+// A tier with a zero limit applies to any amount above the previous tiers.
+type FeeTier struct {
+	UpTo       Money
+	Flat       Money
+	Percentage float64
+}
+
+// Fee is a method that calculates the fee the schedule charges for the provided amount
+func (schedule *FeeSchedule) Fee(amount Money) Money {
+
+	fee := NewMoney(0, amount.Currency)
+
+	switch schedule.Type {
+	case FeeTypeFlat:
+		fee = fee.Add(schedule.Flat)
+
+	case FeeTypePercentage, FeeTypeCapped:
+		fee = fee.Add(amount.Mul(schedule.Percentage / 100))
+
+	case FeeTypeTiered:
+		for _, tier := range schedule.Tiers {
+			if tier.UpTo.IsZero() || !amount.GreaterThan(tier.UpTo) {
+				fee = fee.Add(tier.Flat).Add(amount.Mul(tier.Percentage / 100))
+				break
+			}
+		}
+	}
+
+	// The minimum and maximum bounds are applied to any type of schedule, if they are set
+	if schedule.Minimum.IsPositive() && fee.LessThan(schedule.Minimum) {
+		fee = NewMoney(schedule.Minimum.Minor, amount.Currency)
+	}
+
+	if schedule.Maximum.IsPositive() && fee.GreaterThan(schedule.Maximum) {
+		fee = NewMoney(schedule.Maximum.Minor, amount.Currency)
+	}
+
+	return fee
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// Invoice is a type that defines the merchant order details a payment token can carry,
+// they are copied to the history of the payment so the merchant and the payer can match it to the order
+type Invoice struct {
+	OrderID     string    `gorm:"not null; default: ''"`
+	Description string    `gorm:"not null; default: ''"`
+	CallbackRef string    `gorm:"not null; default: ''"`
+	LineItems   LineItems `gorm:"type:text"`
+}
+
+// LineItem is a type that defines a single item of an invoice
+type LineItem struct {
+	Name      string
+	Quantity  int
+	UnitPrice Money
+}
+
+// LineItems is a type that defines the items of an invoice, they are stored as json in a single column
+type LineItems []*LineItem
+
+// Total is a method that returns the price of the line item for its quantity
+func (lineItem *LineItem) Total() Money {
+	return Money{Minor: lineItem.UnitPrice.Minor * int64(lineItem.Quantity), Currency: lineItem.UnitPrice.Currency}
+}
+
+// Value is a method that maps the line items to a database column holding their json, no line items are stored as null
+func (lineItems LineItems) Value() (driver.Value, error) {
+
+	if len(lineItems) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal([]*LineItem(lineItems))
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Scan is a method that reads the line items from the json stored in a database column
+func (lineItems *LineItems) Scan(value interface{}) error {
+
+	var data []byte
+	switch value := value.(type) {
+	case nil:
+		*lineItems = nil
+		return nil
+	case string:
+		data = []byte(value)
+	case []byte:
+		data = value
+	default:
+		return errors.New("unable to scan line items")
+	}
+
+	if len(data) == 0 {
+		*lineItems = nil
+		return nil
+	}
+
+	var items []*LineItem
+	err := json.Unmarshal(data, &items)
+	if err != nil {
+		return err
+	}
+
+	*lineItems = items
+	return nil
+}
@@ -0,0 +1,109 @@
+package entity
+
+import "time"
+
+// TierUnverified is a constant that defines the verification tier of a user that hasn't been verified yet
+const TierUnverified = "unverified"
+
+// TierVerified is a constant that defines the verification tier of a user whose identity has been verified
+const TierVerified = "verified"
+
+// TierMerchant is a constant that defines the verification tier of a verified business account
+const TierMerchant = "merchant"
+
+// LimitSend is a constant that defines the limits applied to the money a user sends or pays
+const LimitSend = "send"
+
+// LimitReceive is a constant that defines the limits applied to the money a user receives
+const LimitReceive = "receive"
+
+// LimitWithdraw is a constant that defines the limits applied to the money a user withdraws to a linked account
+const LimitWithdraw = "withdraw"
+
+// LimitDaily is a constant that defines the period of the limits that reset every day
+const LimitDaily = "daily"
+
+// LimitWeekly is a constant that defines the period of the limits that reset every week
+const LimitWeekly = "weekly"
+
+// LimitMonthly is a constant that defines the period of the limits that reset every month
+const LimitMonthly = "monthly"
+
+// LimitProfile is a type that defines the limits applied to every user assigned to the profile.
+// All the limits are in base currency and a zero limit means the limit isn't applied.
+type LimitProfile struct {
+	Name       string
+	Tier       string
+	Send       *LimitSet
+	Receive    *LimitSet
+	Withdraw   *LimitSet
+	MaxBalance Money
+}
+
+// LimitSet is a type that defines the per transaction and the periodic limits of a certain kind of transaction
+type LimitSet struct {
+	Transaction Money
+	Daily       Money
+	Weekly      Money
+	Monthly     Money
+}
+
+// RemainingLimit is a type that defines how much of a periodic limit a user has used in the current period
+// and how much is left, all in base currency
+type RemainingLimit struct {
+	Kind      string
+	Period    string
+	Limit     Money
+	Used      Money
+	Remaining Money
+	Unlimited bool
+	Methods   []*MethodUsage
+	ResetsAt  time.Time
+}
+
+// LimitReservation is a type that defines an amount that has been counted against the periodic limits of a user,
+// the amount is in base currency and the keys hold the usage it has been added to
+type LimitReservation struct {
+	Keys   []string
+	Amount Money
+}
+
+// MethodUsage is a type that defines how much of a periodic limit a user has used through a certain method
+type MethodUsage struct {
+	Method string
+	Used   Money
+}
+
+// Limits is a method that returns the limit set of the provided kind of transaction
+func (profile *LimitProfile) Limits(kind string) *LimitSet {
+
+	var limits *LimitSet
+	switch kind {
+	case LimitSend:
+		limits = profile.Send
+	case LimitReceive:
+		limits = profile.Receive
+	case LimitWithdraw:
+		limits = profile.Withdraw
+	}
+
+	if limits == nil {
+		return new(LimitSet)
+	}
+	return limits
+}
+
+// Period is a method that returns the limit of the provided period
+func (limits *LimitSet) Period(period string) Money {
+
+	switch period {
+	case LimitDaily:
+		return limits.Daily
+	case LimitWeekly:
+		return limits.Weekly
+	case LimitMonthly:
+		return limits.Monthly
+	}
+
+	return Money{}
+}
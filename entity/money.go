@@ -0,0 +1,330 @@
+package entity
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// BaseCurrency is a constant that defines the currency used when no currency is provided
+const BaseCurrency = "ETB"
+
+// currencyExponents holds the number of minor unit digits for each of the supported currencies
+var currencyExponents = map[string]int{"ETB": 2, "USD": 2, "EUR": 2}
+
+// currencyNumericCodes holds the ISO 4217 numeric code of each of the supported currencies
+var currencyNumericCodes = map[string]string{"ETB": "230", "USD": "840", "EUR": "978"}
+
+// Money is a type that defines an amount of money stored in integer minor units of a certain currency.
+// For example 12.50 ETB is stored as 1250 santim.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// moneyContainer is a type that defines how money is represented in a json body
+type moneyContainer struct {
+	Value    json.Number
+	Currency string
+}
+
+// CurrencyExponent is a function that returns the number of minor unit digits the provided currency has
+func CurrencyExponent(currency string) int {
+	exponent, ok := currencyExponents[strings.ToUpper(currency)]
+	if !ok {
+		return 2
+	}
+	return exponent
+}
+
+// IsSupportedCurrency is a function that checks whether the provided currency can be held in a wallet
+func IsSupportedCurrency(currency string) bool {
+	_, ok := currencyExponents[strings.ToUpper(currency)]
+	return ok
+}
+
+// CurrencyNumericCode is a function that returns the ISO 4217 numeric code of the provided currency
+func CurrencyNumericCode(currency string) (string, bool) {
+	code, ok := currencyNumericCodes[strings.ToUpper(currency)]
+	return code, ok
+}
+
+// CurrencyFromNumericCode is a function that returns the supported currency with the provided ISO 4217 numeric code
+func CurrencyFromNumericCode(code string) (string, bool) {
+	for currency, numericCode := range currencyNumericCodes {
+		if numericCode == code {
+			return currency, true
+		}
+	}
+	return "", false
+}
+
+// NewMoney is a function that returns a new money value from the provided minor units and currency
+func NewMoney(minor int64, currency string) Money {
+	if currency == "" {
+		currency = BaseCurrency
+	}
+	return Money{Minor: minor, Currency: strings.ToUpper(currency)}
+}
+
+// MoneyFromFloat is a function that converts a float amount to money by rounding it to the nearest minor unit.
+// It should only be used for values that are float by nature such as configuration values or exchange rates.
+func MoneyFromFloat(amount float64, currency string) Money {
+	scale := math.Pow10(CurrencyExponent(currency))
+	return NewMoney(int64(math.Round(amount*scale)), currency)
+}
+
+// ParseMoney is a function that parses a decimal string like '12.50' to money without going through a float
+func ParseMoney(value, currency string) (Money, error) {
+
+	value = strings.TrimSpace(value)
+	exponent := CurrencyExponent(currency)
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+
+	parts := strings.Split(value, ".")
+	if len(parts) > 2 || (parts[0] == "" && (len(parts) == 1 || parts[1] == "")) {
+		return Money{}, errors.New(AmountParsingError)
+	}
+
+	fraction := ""
+	if len(parts) == 2 {
+		fraction = parts[1]
+	}
+
+	if len(fraction) > exponent {
+		return Money{}, errors.New(AmountParsingError)
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	digits := parts[0] + fraction
+	for _, digit := range digits {
+		if digit < '0' || digit > '9' {
+			return Money{}, errors.New(AmountParsingError)
+		}
+	}
+
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, errors.New(AmountParsingError)
+	}
+
+	if negative {
+		minor = -minor
+	}
+
+	return NewMoney(minor, currency), nil
+}
+
+// String is a method that returns the decimal representation of the money value without the currency
+func (money Money) String() string {
+
+	exponent := CurrencyExponent(money.Currency)
+	minor := money.Minor
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+
+	if exponent == 0 {
+		return fmt.Sprintf("%s%d", sign, minor)
+	}
+
+	scale := int64(math.Pow10(exponent))
+	return fmt.Sprintf("%s%d.%0*d", sign, minor/scale, exponent, minor%scale)
+}
+
+// Float64 is a method that returns the money value as a float, it should only be used for display or rate calculations
+func (money Money) Float64() float64 {
+	return float64(money.Minor) / math.Pow10(CurrencyExponent(money.Currency))
+}
+
+// mustMatch is a method that panics if the two money values are of different currencies.
+// An empty currency is considered as a zero value and matches any currency.
+func (money Money) mustMatch(opMoney Money) string {
+
+	if money.Currency == "" {
+		return opMoney.Currency
+	}
+
+	if opMoney.Currency != "" && money.Currency != opMoney.Currency {
+		panic(fmt.Sprintf("money currency mismatch %s and %s", money.Currency, opMoney.Currency))
+	}
+
+	return money.Currency
+}
+
+// Add is a method that returns the sum of the two money values
+func (money Money) Add(opMoney Money) Money {
+	return Money{Minor: money.Minor + opMoney.Minor, Currency: money.mustMatch(opMoney)}
+}
+
+// Sub is a method that returns the difference of the two money values
+func (money Money) Sub(opMoney Money) Money {
+	return Money{Minor: money.Minor - opMoney.Minor, Currency: money.mustMatch(opMoney)}
+}
+
+// Neg is a method that returns the negated money value
+func (money Money) Neg() Money {
+	return Money{Minor: -money.Minor, Currency: money.Currency}
+}
+
+// Mul is a method that multiplies the money value by the provided factor, rounding to the nearest minor unit
+func (money Money) Mul(factor float64) Money {
+	return Money{Minor: int64(math.Round(float64(money.Minor) * factor)), Currency: money.Currency}
+}
+
+// Convert is a method that converts the money value to the provided currency using the provided exchange rate,
+// rounding to the nearest minor unit of the new currency
+func (money Money) Convert(rate float64, currency string) Money {
+	return MoneyFromFloat(money.Float64()*rate, currency)
+}
+
+// Cmp is a method that compares the two money values and returns -1, 0 or +1
+func (money Money) Cmp(opMoney Money) int {
+
+	money.mustMatch(opMoney)
+	switch {
+	case money.Minor < opMoney.Minor:
+		return -1
+	case money.Minor > opMoney.Minor:
+		return 1
+	}
+
+	return 0
+}
+
+// LessThan is a method that checks if the money value is less than the provided one
+func (money Money) LessThan(opMoney Money) bool {
+	return money.Cmp(opMoney) < 0
+}
+
+// GreaterThan is a method that checks if the money value is greater than the provided one
+func (money Money) GreaterThan(opMoney Money) bool {
+	return money.Cmp(opMoney) > 0
+}
+
+// IsZero is a method that checks if the money value is zero
+func (money Money) IsZero() bool {
+	return money.Minor == 0
+}
+
+// IsPositive is a method that checks if the money value is greater than zero
+func (money Money) IsPositive() bool {
+	return money.Minor > 0
+}
+
+// IsNegative is a method that checks if the money value is less than zero
+func (money Money) IsNegative() bool {
+	return money.Minor < 0
+}
+
+// MarshalJSON is a method that marshals the money value to a json object holding its decimal value and currency
+func (money Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyContainer{Value: json.Number(money.String()), Currency: money.Currency})
+}
+
+// UnmarshalJSON is a method that unmarshals a money value from a json object, number or string
+func (money *Money) UnmarshalJSON(data []byte) error {
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+
+		container := new(moneyContainer)
+		err := json.Unmarshal(data, container)
+		if err != nil {
+			return err
+		}
+
+		parsedMoney, err := ParseMoney(container.Value.String(), container.Currency)
+		if err != nil {
+			return err
+		}
+
+		*money = parsedMoney
+		return nil
+	}
+
+	parsedMoney, err := ParseMoney(strings.Trim(string(data), `"`), BaseCurrency)
+	if err != nil {
+		return err
+	}
+
+	*money = parsedMoney
+	return nil
+}
+
+// MarshalXML is a method that marshals the money value to an xml element with the currency as an attribute
+func (money Money) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "currency"}, Value: money.Currency})
+	return e.EncodeElement(money.String(), start)
+}
+
+// UnmarshalXML is a method that unmarshals a money value from an xml element
+func (money *Money) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+
+	var value string
+	err := d.DecodeElement(&value, &start)
+	if err != nil {
+		return err
+	}
+
+	currency := BaseCurrency
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "currency" {
+			currency = attr.Value
+		}
+	}
+
+	parsedMoney, err := ParseMoney(value, currency)
+	if err != nil {
+		return err
+	}
+
+	*money = parsedMoney
+	return nil
+}
+
+// Value is a method that maps the money value to a database column holding the minor units
+func (money Money) Value() (driver.Value, error) {
+	return money.Minor, nil
+}
+
+// Scan is a method that reads the money minor units from a database column.
+// The currency is set to the base currency, entities that hold other currencies should set it after reading.
+func (money *Money) Scan(value interface{}) error {
+
+	var minor int64
+	switch value := value.(type) {
+	case nil:
+		minor = 0
+	case int64:
+		minor = value
+	case float64:
+		minor = int64(math.Round(value))
+	case []byte:
+		parsedMinor, err := strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			return err
+		}
+		minor = parsedMinor
+	default:
+		return errors.New("unable to scan money value")
+	}
+
+	currency := money.Currency
+	if currency == "" {
+		currency = BaseCurrency
+	}
+
+	*money = Money{Minor: minor, Currency: currency}
+	return nil
+}
@@ -0,0 +1,26 @@
+package entity
+
+// QRSigningKey is a type that defines a key used for signing the payload of qr codes.
+// The private key is an ECDSA P-256 key in PEM format, its public key is published so terminals can check signatures offline.
+type QRSigningKey struct {
+	KeyID      string `json:"key_id"`
+	PrivateKey string `json:"private_key"`
+}
+
+// EMVQRCode is a type that defines an EMVCo merchant presented qr code.
+// A static qr code only identifies the merchant while a dynamic qr code also carries the amount and the payment token code as its reference.
+type EMVQRCode struct {
+	PointOfInitiation    string
+	MerchantID           string
+	MerchantCategoryCode string
+	Amount               Money
+	CountryCode          string
+	MerchantName         string
+	MerchantCity         string
+	ReferenceLabel       string
+}
+
+// IsDynamic is a method that checks whether the qr code is a dynamic qr code
+func (emvQRCode *EMVQRCode) IsDynamic() bool {
+	return emvQRCode.PointOfInitiation == EMVDynamicQRCode
+}
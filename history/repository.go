@@ -1,14 +1,22 @@
 package history
 
-import "github.com/Benyam-S/onepay/entity"
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
 
 // IHistoryRepository is an interface that defines all the repository methods of a user history struct
 type IHistoryRepository interface {
 	Create(newOPHistory *entity.UserHistory) error
 	Find(identifier int64) (*entity.UserHistory, error)
+	FindForUpdate(identifier int64) (*entity.UserHistory, error)
+	Children(parentID int64, methods []string) []*entity.UserHistory
+	FindByCode(code string, methods []string) (*entity.UserHistory, error)
 	Search(key, orderBy string, methods []string, pageNum int64, columns ...string) ([]*entity.UserHistory, int64)
 	All(identifier string) []*entity.UserHistory
+	FeeTotals() []entity.Money
 	Update(opHistory *entity.UserHistory) error
 	MarkAsSeen(userID string) error
 	Delete(identifier int64) (*entity.UserHistory, error)
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IHistoryRepository
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Benyam-S/onepay/entity"
 	"github.com/Benyam-S/onepay/history"
+	"github.com/Benyam-S/onepay/unitofwork"
 	"github.com/jinzhu/gorm"
 )
 
@@ -43,6 +44,44 @@ func (repo *HistoryRepository) Find(identifier int64) (*entity.UserHistory, erro
 	return opHistory, nil
 }
 
+// FindForUpdate is a method that finds a certain user history from the database using an identifier and locks it
+// until the transaction it is read in ends, so histories that depend on it can be added one at a time
+func (repo *HistoryRepository) FindForUpdate(identifier int64) (*entity.UserHistory, error) {
+	opHistory := new(entity.UserHistory)
+	err := repo.conn.Set("gorm:query_option", "FOR UPDATE").Model(opHistory).
+		Where("id = ?", identifier).First(opHistory).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return opHistory, nil
+}
+
+// Children is a method that returns the histories that reference the provided parent history and have one of the provided methods
+func (repo *HistoryRepository) Children(parentID int64, methods []string) []*entity.UserHistory {
+
+	var opHistories []*entity.UserHistory
+	err := repo.conn.Model(entity.UserHistory{}).
+		Where("parent_id = ? AND method IN (?)", parentID, methods).Order("id").Find(&opHistories).Error
+
+	if err != nil {
+		return []*entity.UserHistory{}
+	}
+	return opHistories
+}
+
+// FindByCode is a method that finds the first user history with the provided code and one of the provided methods
+func (repo *HistoryRepository) FindByCode(code string, methods []string) (*entity.UserHistory, error) {
+	opHistory := new(entity.UserHistory)
+	err := repo.conn.Model(opHistory).
+		Where("code = ? AND method IN (?)", code, methods).Order("id").First(opHistory).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return opHistory, nil
+}
+
 // Search is a method that search and returns a set of user histories from the database using an identifier.
 func (repo *HistoryRepository) Search(key, orderBy string, methods []string, pageNum int64, columns ...string) ([]*entity.UserHistory, int64) {
 
@@ -83,6 +122,26 @@ func (repo *HistoryRepository) All(identifier string) []*entity.UserHistory {
 	return opHistories
 }
 
+// FeeTotals is a method that returns the sum of the fees of all the user histories, one for each currency
+func (repo *HistoryRepository) FeeTotals() []entity.Money {
+
+	var feeTotals []struct {
+		Currency string
+		Fee      int64
+	}
+
+	err := repo.conn.Raw("SELECT currency, SUM(fee) AS fee FROM user_history GROUP BY currency").Scan(&feeTotals).Error
+	if err != nil {
+		return []entity.Money{}
+	}
+
+	totals := make([]entity.Money, 0, len(feeTotals))
+	for _, feeTotal := range feeTotals {
+		totals = append(totals, entity.NewMoney(feeTotal.Fee, feeTotal.Currency))
+	}
+	return totals
+}
+
 // Update is a method that updates a certain user history value in the database
 func (repo *HistoryRepository) Update(opHistory *entity.UserHistory) error {
 
@@ -135,3 +194,8 @@ func (repo *HistoryRepository) Delete(identifier int64) (*entity.UserHistory, er
 	repo.conn.Delete(opHistory)
 	return opHistory, nil
 }
+
+// WithUnitOfWork is a method that returns a user history repository that runs its queries inside the provided unit of work
+func (repo *HistoryRepository) WithUnitOfWork(uow *unitofwork.UnitOfWork) history.IHistoryRepository {
+	return &HistoryRepository{conn: uow.Conn()}
+}
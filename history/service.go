@@ -1,12 +1,20 @@
 package history
 
-import "github.com/Benyam-S/onepay/entity"
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
 
 // IService is an interface that defines all the service methods of a history struct
 type IService interface {
 	AddHistory(newOPHistory *entity.UserHistory) error
 	SearchHistories(key, orderBy string, methods []string, pageNum int64, columns ...string) ([]*entity.UserHistory, int64)
 	FindHistory(identifier int64) (*entity.UserHistory, error)
+	LockHistory(identifier int64) (*entity.UserHistory, error)
+	ChildHistories(parentID int64, methods ...string) []*entity.UserHistory
+	FindHistoryByCode(code string, methods ...string) (*entity.UserHistory, error)
 	AllUserHistories(userID string) []*entity.UserHistory
+	TotalFees() []entity.Money
 	MarkUserHistoriesAsSeen(userID string) error
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IService
 }
@@ -7,12 +7,14 @@ import (
 	"github.com/Benyam-S/onepay/entity"
 	"github.com/Benyam-S/onepay/history"
 	"github.com/Benyam-S/onepay/notifier"
+	"github.com/Benyam-S/onepay/unitofwork"
 )
 
 // Service is a type that defines history service
 type Service struct {
 	historyRepo history.IHistoryRepository
 	notifier    *notifier.Notifier
+	uow         *unitofwork.UnitOfWork
 }
 
 // NewHistoryService is a function that returns a new history service
@@ -29,7 +31,7 @@ func (service *Service) AddHistory(newOPHistory *entity.UserHistory) error {
 	}
 
 	/* ++++++++++++++ NOTIFYING CHANGE +++++++++++++++ */
-	service.notifier.NotifyHistoryChange(newOPHistory)
+	service.notify(func() { service.notifier.NotifyHistoryChange(newOPHistory) })
 	/* +++++++++++++++++++++++++++++++++++++++++++++++ */
 
 	return nil
@@ -51,6 +53,11 @@ func (service *Service) AllUserHistories(userID string) []*entity.UserHistory {
 	return service.historyRepo.All(userID)
 }
 
+// TotalFees is a method that returns the sum of the fees of all the histories in the system, one for each currency
+func (service *Service) TotalFees() []entity.Money {
+	return service.historyRepo.FeeTotals()
+}
+
 // FindHistory is a method that finds a certain history from the system using the identifer
 func (service *Service) FindHistory(identifier int64) (*entity.UserHistory, error) {
 
@@ -61,6 +68,36 @@ func (service *Service) FindHistory(identifier int64) (*entity.UserHistory, erro
 	return history, nil
 }
 
+// LockHistory is a method that finds a certain history using the identifier and locks it for the rest of the unit of work
+func (service *Service) LockHistory(identifier int64) (*entity.UserHistory, error) {
+
+	history, err := service.historyRepo.FindForUpdate(identifier)
+	if err != nil {
+		return nil, errors.New("history not found")
+	}
+	return history, nil
+}
+
+// ChildHistories is a method that returns the histories that reference the provided parent history with one of the provided methods
+func (service *Service) ChildHistories(parentID int64, methods ...string) []*entity.UserHistory {
+	return service.historyRepo.Children(parentID, methods)
+}
+
+// FindHistoryByCode is a method that finds a history using its code, only histories with one of the provided methods are considered
+func (service *Service) FindHistoryByCode(code string, methods ...string) (*entity.UserHistory, error) {
+
+	empty, _ := regexp.MatchString(`^\s*$`, code)
+	if empty {
+		return nil, errors.New("history not found")
+	}
+
+	opHistory, err := service.historyRepo.FindByCode(code, methods)
+	if err != nil {
+		return nil, errors.New("history not found")
+	}
+	return opHistory, nil
+}
+
 // MarkUserHistoriesAsSeen is a method that marks a certain user's histories as seen
 func (service *Service) MarkUserHistoriesAsSeen(userID string) error {
 
@@ -70,3 +107,18 @@ func (service *Service) MarkUserHistoriesAsSeen(userID string) error {
 	}
 	return nil
 }
+
+// WithUnitOfWork is a method that returns a history service whose changes are made inside the provided unit of work
+func (service *Service) WithUnitOfWork(uow *unitofwork.UnitOfWork) history.IService {
+	return &Service{historyRepo: service.historyRepo.WithUnitOfWork(uow), notifier: service.notifier, uow: uow}
+}
+
+// notify is a method that runs the provided notification immediately or,
+// if the service is bound to a unit of work, once the unit of work has been committed
+func (service *Service) notify(f func()) {
+	if service.uow != nil {
+		service.uow.AfterCommit(f)
+		return
+	}
+	f()
+}
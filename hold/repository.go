@@ -0,0 +1,19 @@
+package hold
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// IHoldRepository is an interface that defines all the repository methods of a wallet hold struct
+type IHoldRepository interface {
+	Create(newHold *entity.WalletHold) error
+	Find(identifier int64) (*entity.WalletHold, error)
+	FindForUpdate(identifier int64) (*entity.WalletHold, error)
+	Search(userID string) []*entity.WalletHold
+	Expired(now time.Time) []*entity.WalletHold
+	Update(hold *entity.WalletHold) error
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IHoldRepository
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/hold"
+	"github.com/Benyam-S/onepay/unitofwork"
+	"github.com/jinzhu/gorm"
+)
+
+// HoldRepository is a type that defines a wallet hold repository
+type HoldRepository struct {
+	conn *gorm.DB
+}
+
+// NewHoldRepository is a function that returns a new wallet hold repository
+func NewHoldRepository(connection *gorm.DB) hold.IHoldRepository {
+	return &HoldRepository{conn: connection}
+}
+
+// Create is a method that adds a new wallet hold to the database
+func (repo *HoldRepository) Create(newHold *entity.WalletHold) error {
+
+	err := repo.conn.Create(newHold).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds a certain wallet hold from the database using an identifier.
+// In Find() id is only used as a key
+func (repo *HoldRepository) Find(identifier int64) (*entity.WalletHold, error) {
+	opHold := new(entity.WalletHold)
+	err := repo.conn.Model(opHold).
+		Where("id = ?", identifier).First(opHold).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return opHold, nil
+}
+
+// FindForUpdate is a method that finds a certain wallet hold from the database using an identifier and locks it
+// until the transaction it is read in ends
+func (repo *HoldRepository) FindForUpdate(identifier int64) (*entity.WalletHold, error) {
+	opHold := new(entity.WalletHold)
+	err := repo.conn.Set("gorm:query_option", "FOR UPDATE").Model(opHold).
+		Where("id = ?", identifier).First(opHold).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return opHold, nil
+}
+
+// Search is a method that returns all the wallet holds a certain user is either the holder or the merchant of
+func (repo *HoldRepository) Search(userID string) []*entity.WalletHold {
+	var opHolds []*entity.WalletHold
+	err := repo.conn.Model(entity.WalletHold{}).
+		Where("user_id = ? || merchant_id = ?", userID, userID).
+		Order("id DESC").Find(&opHolds).Error
+
+	if err != nil {
+		return []*entity.WalletHold{}
+	}
+	return opHolds
+}
+
+// Expired is a method that returns all the authorized wallet holds whose expiry has passed
+func (repo *HoldRepository) Expired(now time.Time) []*entity.WalletHold {
+	var opHolds []*entity.WalletHold
+	err := repo.conn.Model(entity.WalletHold{}).
+		Where("status = ? AND expires_at < ?", entity.HoldStatusAuthorized, now).
+		Find(&opHolds).Error
+
+	if err != nil {
+		return []*entity.WalletHold{}
+	}
+	return opHolds
+}
+
+// Update is a method that updates a certain wallet hold value in the database
+func (repo *HoldRepository) Update(opHold *entity.WalletHold) error {
+
+	prevHold := new(entity.WalletHold)
+	err := repo.conn.Model(prevHold).Where("id = ?", opHold.ID).First(prevHold).Error
+
+	if err != nil {
+		return err
+	}
+
+	err = repo.conn.Save(opHold).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// WithUnitOfWork is a method that returns a wallet hold repository that runs its queries inside the provided unit of work
+func (repo *HoldRepository) WithUnitOfWork(uow *unitofwork.UnitOfWork) hold.IHoldRepository {
+	return &HoldRepository{conn: uow.Conn()}
+}
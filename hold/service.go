@@ -0,0 +1,19 @@
+package hold
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// IService is an interface that defines all the service methods of a wallet hold struct
+type IService interface {
+	AddHold(newHold *entity.WalletHold) error
+	FindHold(identifier int64) (*entity.WalletHold, error)
+	LockHold(identifier int64) (*entity.WalletHold, error)
+	SearchHolds(userID string) []*entity.WalletHold
+	ExpiredHolds(now time.Time) []*entity.WalletHold
+	UpdateHold(hold *entity.WalletHold) error
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IService
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/hold"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// Service is a type that defines wallet hold service
+type Service struct {
+	holdRepo hold.IHoldRepository
+}
+
+// NewHoldService is a function that returns a new wallet hold service
+func NewHoldService(holdRepository hold.IHoldRepository) hold.IService {
+	return &Service{holdRepo: holdRepository}
+}
+
+// AddHold is a method that adds a new wallet hold to the system
+func (service *Service) AddHold(newHold *entity.WalletHold) error {
+
+	if !newHold.Amount.IsPositive() {
+		return errors.New(entity.AmountParsingError)
+	}
+
+	newHold.Reference = strings.TrimSpace(newHold.Reference)
+	newHold.Captured = entity.NewMoney(0, newHold.Amount.Currency)
+	newHold.Status = entity.HoldStatusAuthorized
+
+	err := service.holdRepo.Create(newHold)
+	if err != nil {
+		return errors.New("unable to add new hold")
+	}
+	return nil
+}
+
+// FindHold is a method that finds a certain wallet hold using the identifier
+func (service *Service) FindHold(identifier int64) (*entity.WalletHold, error) {
+
+	opHold, err := service.holdRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("hold not found")
+	}
+	return opHold, nil
+}
+
+// LockHold is a method that finds a certain wallet hold using the identifier and locks it for the rest of the unit of work
+func (service *Service) LockHold(identifier int64) (*entity.WalletHold, error) {
+
+	opHold, err := service.holdRepo.FindForUpdate(identifier)
+	if err != nil {
+		return nil, errors.New("hold not found")
+	}
+	return opHold, nil
+}
+
+// SearchHolds is a method that returns all the wallet holds a certain user is part of
+func (service *Service) SearchHolds(userID string) []*entity.WalletHold {
+
+	empty, _ := regexp.MatchString(`^\s*$`, userID)
+	if empty {
+		return []*entity.WalletHold{}
+	}
+
+	return service.holdRepo.Search(userID)
+}
+
+// ExpiredHolds is a method that returns all the authorized wallet holds whose expiry has passed
+func (service *Service) ExpiredHolds(now time.Time) []*entity.WalletHold {
+	return service.holdRepo.Expired(now)
+}
+
+// UpdateHold is a method that updates a certain wallet hold
+func (service *Service) UpdateHold(opHold *entity.WalletHold) error {
+
+	err := service.holdRepo.Update(opHold)
+	if err != nil {
+		return errors.New("unable to update hold")
+	}
+	return nil
+}
+
+// WithUnitOfWork is a method that returns a wallet hold service whose changes are made inside the provided unit of work
+func (service *Service) WithUnitOfWork(uow *unitofwork.UnitOfWork) hold.IService {
+	return &Service{holdRepo: service.holdRepo.WithUnitOfWork(uow)}
+}
@@ -0,0 +1,16 @@
+package job
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// IJobRunRepository is an interface that defines all the repository methods of a job run struct
+type IJobRunRepository interface {
+	Create(newRun *entity.JobRun) error
+	Last(jobName string) (*entity.JobRun, error)
+	Search(jobName string, pageNum int64) ([]*entity.JobRun, int64)
+	Update(run *entity.JobRun) error
+	DeleteBefore(before time.Time) error
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"math"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/job"
+	"github.com/jinzhu/gorm"
+)
+
+// JobRunRepository is a type that defines a job run repository
+type JobRunRepository struct {
+	conn *gorm.DB
+}
+
+// NewJobRunRepository is a function that returns a new job run repository
+func NewJobRunRepository(connection *gorm.DB) job.IJobRunRepository {
+	return &JobRunRepository{conn: connection}
+}
+
+// Create is a method that adds a new job run to the database
+func (repo *JobRunRepository) Create(newRun *entity.JobRun) error {
+
+	err := repo.conn.Create(newRun).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Last is a method that finds the latest run of a certain job
+func (repo *JobRunRepository) Last(jobName string) (*entity.JobRun, error) {
+	run := new(entity.JobRun)
+	err := repo.conn.Model(run).
+		Where("job_name = ?", jobName).
+		Order("id DESC").First(run).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// Search is a method that returns a page of the job runs, the latest first, along with the number of pages.
+// If the job name is empty the runs of all the jobs are returned.
+func (repo *JobRunRepository) Search(jobName string, pageNum int64) ([]*entity.JobRun, int64) {
+
+	var runs []*entity.JobRun
+	var count float64
+
+	query := repo.conn.Model(entity.JobRun{})
+	if jobName != "" {
+		query = query.Where("job_name = ?", jobName)
+	}
+
+	query.Count(&count)
+	err := query.Order("id DESC").Limit(10).Offset(pageNum * 10).Find(&runs).Error
+
+	if err != nil {
+		return []*entity.JobRun{}, 0
+	}
+
+	var pageCount int64 = int64(math.Ceil(count / 10.0))
+	return runs, pageCount
+}
+
+// Update is a method that updates a certain job run value in the database
+func (repo *JobRunRepository) Update(run *entity.JobRun) error {
+
+	prevRun := new(entity.JobRun)
+	err := repo.conn.Model(prevRun).Where("id = ?", run.ID).First(prevRun).Error
+
+	if err != nil {
+		return err
+	}
+
+	err = repo.conn.Save(run).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteBefore is a method that deletes the job runs that have started before the provided time.
+// The latest run of each job is always kept, since it tells when the job becomes due again.
+func (repo *JobRunRepository) DeleteBefore(before time.Time) error {
+
+	// The latest runs are selected through a derived table, as mysql doesn't allow selecting from the table being deleted from
+	latestRuns := repo.conn.Raw("SELECT id FROM (SELECT MAX(id) AS id FROM job_runs GROUP BY job_name) AS latest_runs").QueryExpr()
+
+	err := repo.conn.Where("started_at < ? AND id NOT IN (?)", before, latestRuns).Delete(entity.JobRun{}).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package job
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// IService is an interface that defines all the service methods of a job run struct
+type IService interface {
+	AddJobRun(newRun *entity.JobRun) error
+	LastJobRun(jobName string) (*entity.JobRun, error)
+	SearchJobRuns(jobName string, pageNum int64) ([]*entity.JobRun, int64)
+	UpdateJobRun(run *entity.JobRun) error
+	PruneJobRuns(before time.Time) error
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/job"
+)
+
+// Service is a type that defines job service
+type Service struct {
+	runRepo job.IJobRunRepository
+}
+
+// NewJobService is a function that returns a new job service
+func NewJobService(runRepository job.IJobRunRepository) job.IService {
+	return &Service{runRepo: runRepository}
+}
+
+// AddJobRun is a method that records a new run of a job
+func (service *Service) AddJobRun(newRun *entity.JobRun) error {
+
+	err := service.runRepo.Create(newRun)
+	if err != nil {
+		return errors.New("unable to add new job run")
+	}
+	return nil
+}
+
+// LastJobRun is a method that finds the latest run of a certain job
+func (service *Service) LastJobRun(jobName string) (*entity.JobRun, error) {
+
+	run, err := service.runRepo.Last(jobName)
+	if err != nil {
+		return nil, errors.New("job run not found")
+	}
+	return run, nil
+}
+
+// SearchJobRuns is a method that returns a page of the job runs along with the number of pages
+func (service *Service) SearchJobRuns(jobName string, pageNum int64) ([]*entity.JobRun, int64) {
+
+	if pageNum < 0 {
+		pageNum = 0
+	}
+
+	return service.runRepo.Search(jobName, pageNum)
+}
+
+// UpdateJobRun is a method that updates a certain job run
+func (service *Service) UpdateJobRun(run *entity.JobRun) error {
+
+	err := service.runRepo.Update(run)
+	if err != nil {
+		return errors.New("unable to update job run")
+	}
+	return nil
+}
+
+// PruneJobRuns is a method that deletes the job runs that have started before the provided time, keeping the latest run of each job
+func (service *Service) PruneJobRuns(before time.Time) error {
+
+	err := service.runRepo.DeleteBefore(before)
+	if err != nil {
+		return errors.New("unable to prune job runs")
+	}
+	return nil
+}
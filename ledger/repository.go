@@ -0,0 +1,27 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// ILedgerAccountRepository is an interface that defines all the repository methods of a ledger account struct
+type ILedgerAccountRepository interface {
+	Create(newLedgerAccount *entity.LedgerAccount) error
+	Find(identifier string) (*entity.LedgerAccount, error)
+	Search(accountType string) []*entity.LedgerAccount
+	Balance(identifier string) (entity.Money, error)
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) ILedgerAccountRepository
+}
+
+// IJournalEntryRepository is an interface that defines all the repository methods of a journal entry struct
+type IJournalEntryRepository interface {
+	Create(newJournalEntry *entity.JournalEntry) error
+	Find(identifier int64) (*entity.JournalEntry, error)
+	Postings(accountID string) []*entity.Posting
+	SearchByAccount(accountID string, from, to time.Time) []*entity.JournalEntry
+	Total() (entity.Money, error)
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IJournalEntryRepository
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/ledger"
+	"github.com/Benyam-S/onepay/unitofwork"
+	"github.com/jinzhu/gorm"
+)
+
+// JournalEntryRepository is a type that defines a journal entry repository
+type JournalEntryRepository struct {
+	conn *gorm.DB
+}
+
+// NewJournalEntryRepository is a function that returns a new journal entry repository
+func NewJournalEntryRepository(connection *gorm.DB) ledger.IJournalEntryRepository {
+	return &JournalEntryRepository{conn: connection}
+}
+
+// Create is a method that adds a new journal entry together with its postings to the database
+func (repo *JournalEntryRepository) Create(newJournalEntry *entity.JournalEntry) error {
+
+	err := repo.conn.Create(newJournalEntry).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds a certain journal entry with its postings from the database using an identifier.
+// In Find() id is only used as a key
+func (repo *JournalEntryRepository) Find(identifier int64) (*entity.JournalEntry, error) {
+	journalEntry := new(entity.JournalEntry)
+	err := repo.conn.Model(journalEntry).Preload("Postings").
+		Where("id = ?", identifier).
+		First(journalEntry).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return journalEntry, nil
+}
+
+// Postings is a method that returns all the postings made to a certain ledger account
+func (repo *JournalEntryRepository) Postings(accountID string) []*entity.Posting {
+	var postings []*entity.Posting
+	err := repo.conn.Model(entity.Posting{}).
+		Where("account_id = ?", accountID).Order("id").
+		Find(&postings).Error
+
+	if err != nil {
+		return []*entity.Posting{}
+	}
+	return postings
+}
+
+// SearchByAccount is a method that returns the journal entries with a posting to a certain ledger account
+// that have been recorded in the provided period
+func (repo *JournalEntryRepository) SearchByAccount(accountID string, from, to time.Time) []*entity.JournalEntry {
+	var journalEntries []*entity.JournalEntry
+	err := repo.conn.Model(entity.JournalEntry{}).Preload("Postings").
+		Where("created_at >= ? AND created_at < ? AND id IN (?)", from, to,
+			repo.conn.Model(entity.Posting{}).Select("journal_entry_id").Where("account_id = ?", accountID).QueryExpr()).
+		Order("id").Find(&journalEntries).Error
+
+	if err != nil {
+		return []*entity.JournalEntry{}
+	}
+	return journalEntries
+}
+
+// Total is a method that returns the sum of every posting in the ledger, which should always be zero
+func (repo *JournalEntryRepository) Total() (entity.Money, error) {
+
+	var total entity.Money
+	err := repo.conn.Model(entity.Posting{}).Select("COALESCE(SUM(amount), 0)").Row().Scan(&total)
+
+	if err != nil {
+		return entity.Money{}, err
+	}
+	return total, nil
+}
+
+// WithUnitOfWork is a method that returns a journal entry repository that runs its queries inside the provided unit of work
+func (repo *JournalEntryRepository) WithUnitOfWork(uow *unitofwork.UnitOfWork) ledger.IJournalEntryRepository {
+	return &JournalEntryRepository{conn: uow.Conn()}
+}
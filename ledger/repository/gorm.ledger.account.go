@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/ledger"
+	"github.com/Benyam-S/onepay/unitofwork"
+	"github.com/jinzhu/gorm"
+)
+
+// LedgerAccountRepository is a type that defines a ledger account repository
+type LedgerAccountRepository struct {
+	conn *gorm.DB
+}
+
+// NewLedgerAccountRepository is a function that returns a new ledger account repository
+func NewLedgerAccountRepository(connection *gorm.DB) ledger.ILedgerAccountRepository {
+	return &LedgerAccountRepository{conn: connection}
+}
+
+// Create is a method that adds a new ledger account to the database
+func (repo *LedgerAccountRepository) Create(newLedgerAccount *entity.LedgerAccount) error {
+
+	err := repo.conn.Create(newLedgerAccount).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds a certain ledger account from the database using an identifier.
+// In Find() id is only used as a key
+func (repo *LedgerAccountRepository) Find(identifier string) (*entity.LedgerAccount, error) {
+	ledgerAccount := new(entity.LedgerAccount)
+	err := repo.conn.Model(ledgerAccount).
+		Where("id = ?", identifier).
+		First(ledgerAccount).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return ledgerAccount, nil
+}
+
+// Search is a method that returns all the ledger accounts of the provided account type
+func (repo *LedgerAccountRepository) Search(accountType string) []*entity.LedgerAccount {
+	var ledgerAccounts []*entity.LedgerAccount
+	err := repo.conn.Model(entity.LedgerAccount{}).
+		Where("type = ?", accountType).
+		Find(&ledgerAccounts).Error
+
+	if err != nil {
+		return []*entity.LedgerAccount{}
+	}
+	return ledgerAccounts
+}
+
+// Balance is a method that computes a certain ledger account balance from all of its postings
+func (repo *LedgerAccountRepository) Balance(identifier string) (entity.Money, error) {
+
+	var balance entity.Money
+	err := repo.conn.Model(entity.Posting{}).Where("account_id = ?", identifier).
+		Select("COALESCE(SUM(amount), 0)").Row().Scan(&balance)
+
+	if err != nil {
+		return entity.Money{}, err
+	}
+	return balance, nil
+}
+
+// WithUnitOfWork is a method that returns a ledger account repository that runs its queries inside the provided unit of work
+func (repo *LedgerAccountRepository) WithUnitOfWork(uow *unitofwork.UnitOfWork) ledger.ILedgerAccountRepository {
+	return &LedgerAccountRepository{conn: uow.Conn()}
+}
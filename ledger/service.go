@@ -0,0 +1,23 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// IService is an interface that defines all the service methods of the ledger
+type IService interface {
+	OpenLedgerAccount(accountID string) (*entity.LedgerAccount, error)
+	FindLedgerAccount(identifier string) (*entity.LedgerAccount, error)
+	SearchLedgerAccounts(accountType string) []*entity.LedgerAccount
+	AccountBalance(accountID string) (entity.Money, error)
+	AccountPostings(accountID string) []*entity.Posting
+	AccountJournalEntries(accountID string, from, to time.Time) []*entity.JournalEntry
+
+	PostJournalEntry(newJournalEntry *entity.JournalEntry) error
+	FindJournalEntry(identifier int64) (*entity.JournalEntry, error)
+	IsBalanced() bool
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IService
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/ledger"
+	"github.com/Benyam-S/onepay/tools"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// Service is a type that defines ledger service
+type Service struct {
+	ledgerAccountRepo ledger.ILedgerAccountRepository
+	journalEntryRepo  ledger.IJournalEntryRepository
+}
+
+// NewLedgerService is a function that returns a new ledger service
+func NewLedgerService(ledgerAccountRepository ledger.ILedgerAccountRepository,
+	journalEntryRepository ledger.IJournalEntryRepository) ledger.IService {
+	return &Service{ledgerAccountRepo: ledgerAccountRepository, journalEntryRepo: journalEntryRepository}
+}
+
+// OpenLedgerAccount is a method that returns the ledger account of the provided id, creating it if it doesn't exist
+func (service *Service) OpenLedgerAccount(accountID string) (*entity.LedgerAccount, error) {
+
+	ledgerAccount, err := service.ledgerAccountRepo.Find(accountID)
+	if err == nil {
+		return ledgerAccount, nil
+	}
+
+	accountType, ownerID := tools.ParseLedgerAccountID(accountID)
+	if !tools.IsValidLedgerAccountType(accountType) {
+		return nil, errors.New("invalid ledger account type")
+	}
+
+	ledgerAccount = new(entity.LedgerAccount)
+	ledgerAccount.ID = accountID
+	ledgerAccount.Type = accountType
+	ledgerAccount.OwnerID = ownerID
+	ledgerAccount.Currency = tools.LedgerAccountCurrency(accountID)
+
+	if !entity.IsSupportedCurrency(ledgerAccount.Currency) {
+		return nil, errors.New("invalid ledger account currency")
+	}
+
+	err = service.ledgerAccountRepo.Create(ledgerAccount)
+	if err != nil {
+		return nil, errors.New("unable to open ledger account")
+	}
+	return ledgerAccount, nil
+}
+
+// FindLedgerAccount is a method that finds a certain ledger account using the provided identifier
+func (service *Service) FindLedgerAccount(identifier string) (*entity.LedgerAccount, error) {
+
+	empty, _ := regexp.MatchString(`^\s*$`, identifier)
+	if empty {
+		return nil, errors.New("ledger account not found")
+	}
+
+	ledgerAccount, err := service.ledgerAccountRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("ledger account not found")
+	}
+	return ledgerAccount, nil
+}
+
+// SearchLedgerAccounts is a method that returns all the ledger accounts of the provided account type
+func (service *Service) SearchLedgerAccounts(accountType string) []*entity.LedgerAccount {
+	return service.ledgerAccountRepo.Search(accountType)
+}
+
+// AccountBalance is a method that returns a certain ledger account balance computed from its postings
+func (service *Service) AccountBalance(accountID string) (entity.Money, error) {
+
+	balance, err := service.ledgerAccountRepo.Balance(accountID)
+	if err != nil {
+		return entity.Money{}, errors.New("unable to compute ledger account balance")
+	}
+
+	balance.Currency = tools.LedgerAccountCurrency(accountID)
+	return balance, nil
+}
+
+// AccountPostings is a method that returns all the postings of a certain ledger account
+func (service *Service) AccountPostings(accountID string) []*entity.Posting {
+	return service.journalEntryRepo.Postings(accountID)
+}
+
+// AccountJournalEntries is a method that returns the journal entries of a certain ledger account recorded in the provided period
+func (service *Service) AccountJournalEntries(accountID string, from, to time.Time) []*entity.JournalEntry {
+	return service.journalEntryRepo.SearchByAccount(accountID, from, to)
+}
+
+// PostJournalEntry is a method that validates and records a new journal entry.
+// The postings of a journal entry should sum up to zero so that money is neither created nor destroyed.
+func (service *Service) PostJournalEntry(newJournalEntry *entity.JournalEntry) error {
+
+	// Zero postings don't change any balance so they are left out
+	postings := make([]*entity.Posting, 0)
+	for _, posting := range newJournalEntry.Postings {
+		if !posting.Amount.IsZero() {
+			postings = append(postings, posting)
+		}
+	}
+	newJournalEntry.Postings = postings
+
+	if len(newJournalEntry.Postings) < 2 {
+		return errors.New("journal entry should have at least two postings")
+	}
+
+	// A journal entry that converts money has postings of different currencies, each of them has to balance
+	totals := make(map[string]entity.Money)
+	for _, posting := range newJournalEntry.Postings {
+		totals[posting.Amount.Currency] = totals[posting.Amount.Currency].Add(posting.Amount)
+	}
+
+	for _, total := range totals {
+		if !total.IsZero() {
+			return errors.New(entity.UnbalancedJournalEntryError)
+		}
+	}
+
+	for _, posting := range newJournalEntry.Postings {
+		ledgerAccount, err := service.OpenLedgerAccount(posting.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if ledgerAccount.Currency != posting.Amount.Currency {
+			return errors.New("posting currency doesn't match the ledger account currency")
+		}
+	}
+
+	if newJournalEntry.CreatedAt.IsZero() {
+		newJournalEntry.CreatedAt = time.Now()
+	}
+
+	err := service.journalEntryRepo.Create(newJournalEntry)
+	if err != nil {
+		return errors.New("unable to add new journal entry")
+	}
+	return nil
+}
+
+// FindJournalEntry is a method that finds a certain journal entry using the provided identifier
+func (service *Service) FindJournalEntry(identifier int64) (*entity.JournalEntry, error) {
+
+	journalEntry, err := service.journalEntryRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("journal entry not found")
+	}
+	return journalEntry, nil
+}
+
+// IsBalanced is a method that checks whether all the postings in the ledger sum up to zero
+func (service *Service) IsBalanced() bool {
+
+	total, err := service.journalEntryRepo.Total()
+	if err != nil {
+		return false
+	}
+
+	return total.IsZero()
+}
+
+// WithUnitOfWork is a method that returns a ledger service whose changes are made inside the provided unit of work
+func (service *Service) WithUnitOfWork(uow *unitofwork.UnitOfWork) ledger.IService {
+	return &Service{ledgerAccountRepo: service.ledgerAccountRepo.WithUnitOfWork(uow),
+		journalEntryRepo: service.journalEntryRepo.WithUnitOfWork(uow)}
+}
@@ -0,0 +1,9 @@
+package limit
+
+import "github.com/Benyam-S/onepay/entity"
+
+// ILimitRepository is an interface that defines all the repository methods of a user limit struct
+type ILimitRepository interface {
+	Find(identifier string) (*entity.UserLimit, error)
+	Save(userLimit *entity.UserLimit) error
+}
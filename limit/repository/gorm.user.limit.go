@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/limit"
+	"github.com/jinzhu/gorm"
+)
+
+// LimitRepository is a type that defines a user limit repository
+type LimitRepository struct {
+	conn *gorm.DB
+}
+
+// NewLimitRepository is a function that returns a new user limit repository
+func NewLimitRepository(connection *gorm.DB) limit.ILimitRepository {
+	return &LimitRepository{conn: connection}
+}
+
+// Find is a method that finds a certain user limit from the database using an identifier,
+// also Find() uses only user_id as a key for selection
+func (repo *LimitRepository) Find(identifier string) (*entity.UserLimit, error) {
+	userLimit := new(entity.UserLimit)
+	err := repo.conn.Model(userLimit).
+		Where("user_id = ?", identifier).
+		First(userLimit).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return userLimit, nil
+}
+
+// Save is a method that adds a user limit to the database or replaces the existing one
+func (repo *LimitRepository) Save(userLimit *entity.UserLimit) error {
+
+	err := repo.conn.Save(userLimit).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package limit
+
+import "github.com/Benyam-S/onepay/entity"
+
+// IService is an interface that defines all the service methods of a user limit struct
+type IService interface {
+	FindUserLimit(identifier string) (*entity.UserLimit, error)
+	UpdateUserLimit(userLimit *entity.UserLimit) error
+}
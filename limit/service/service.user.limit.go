@@ -0,0 +1,48 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/limit"
+)
+
+// Service is a type that defines user limit service
+type Service struct {
+	limitRepo limit.ILimitRepository
+}
+
+// NewLimitService is a function that returns a new user limit service
+func NewLimitService(limitRepository limit.ILimitRepository) limit.IService {
+	return &Service{limitRepo: limitRepository}
+}
+
+// FindUserLimit is a method that finds the limit assignment of a certain user.
+// A user without an assignment is in the unverified tier and has no profile override.
+func (service *Service) FindUserLimit(identifier string) (*entity.UserLimit, error) {
+
+	userLimit, err := service.limitRepo.Find(identifier)
+	if err != nil {
+		return &entity.UserLimit{UserID: identifier, Tier: entity.TierUnverified}, nil
+	}
+	return userLimit, nil
+}
+
+// UpdateUserLimit is a method that assigns a verification tier and an optional limit profile to a user
+func (service *Service) UpdateUserLimit(userLimit *entity.UserLimit) error {
+
+	userLimit.Tier = strings.ToLower(strings.TrimSpace(userLimit.Tier))
+	userLimit.Profile = strings.TrimSpace(userLimit.Profile)
+
+	if userLimit.Tier != entity.TierUnverified && userLimit.Tier != entity.TierVerified &&
+		userLimit.Tier != entity.TierMerchant {
+		return errors.New(entity.InvalidTierError)
+	}
+
+	err := service.limitRepo.Save(userLimit)
+	if err != nil {
+		return errors.New("unable to update user limit")
+	}
+	return nil
+}
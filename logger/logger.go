@@ -13,6 +13,7 @@ type Logger struct {
 	walletLogDir     string
 	moneyTokenLogDir string
 	historyLogDir    string
+	journalLogDir    string
 }
 
 // NewLogger is a function that returns a logger for OnePay fatal errors
@@ -21,9 +22,10 @@ func NewLogger(path string) *Logger {
 	walletLogDirectory := filepath.Join(path, "log.wallet.json")
 	historyLogDirectory := filepath.Join(path, "log.history.json")
 	moneyTokenLogDirectory := filepath.Join(path, "log.money.token.json")
+	journalLogDirectory := filepath.Join(path, "log.journal.entry.json")
 
 	return &Logger{walletLogDir: walletLogDirectory, historyLogDir: historyLogDirectory,
-		moneyTokenLogDir: moneyTokenLogDirectory}
+		moneyTokenLogDir: moneyTokenLogDirectory, journalLogDir: journalLogDirectory}
 }
 
 // Must is a function that panics if the provided logger method returns error
@@ -73,6 +75,19 @@ func (logger *Logger) LoggedMoneyTokens() []*entity.MoneyToken {
 	return loggedMoneyTokens
 }
 
+// LoggedJournalEntries is a method that returns all the logged journal entries
+func (logger *Logger) LoggedJournalEntries() []*entity.JournalEntry {
+
+	loggedJournalEntries := make([]*entity.JournalEntry, 0)
+
+	loggedJournalEntriesByte, err := ioutil.ReadFile(logger.journalLogDir)
+	if err == nil {
+		json.Unmarshal(loggedJournalEntriesByte, &loggedJournalEntries)
+	}
+
+	return loggedJournalEntries
+}
+
 // LogWallet is a method that logs a wallet struct in log file
 func (logger *Logger) LogWallet(opWallet *entity.UserWallet) error {
 
@@ -145,6 +160,30 @@ func (logger *Logger) LogMoneyToken(moneyToken *entity.MoneyToken) error {
 	return nil
 }
 
+// LogJournalEntry is a method that logs a journal entry struct in log file
+func (logger *Logger) LogJournalEntry(journalEntry *entity.JournalEntry) error {
+
+	journalEntries := make([]*entity.JournalEntry, 0)
+
+	journalEntriesByte, err := ioutil.ReadFile(logger.journalLogDir)
+	if err == nil {
+		json.Unmarshal(journalEntriesByte, &journalEntries)
+	}
+
+	journalEntries = append(journalEntries, journalEntry)
+	output, err := json.MarshalIndent(journalEntries, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(logger.journalLogDir, output, 7777)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // RemoveWallet is a method that removes a wallet object from the log file
 func (logger *Logger) RemoveWallet(opWallet *entity.UserWallet) error {
 
@@ -249,3 +288,38 @@ func (logger *Logger) RemoveMoneyToken(opMoneyToken *entity.MoneyToken) error {
 
 	return nil
 }
+
+// RemoveJournalEntry is a method that removes a journal entry object from the log file
+func (logger *Logger) RemoveJournalEntry(opJournalEntry *entity.JournalEntry) error {
+
+	journalEntries := make([]*entity.JournalEntry, 0)
+	journalEntriesWithout := make([]*entity.JournalEntry, 0)
+	addedFlag := false
+
+	journalEntriesByte, err := ioutil.ReadFile(logger.journalLogDir)
+	if err == nil {
+		json.Unmarshal(journalEntriesByte, &journalEntries)
+	}
+
+	for _, journalEntry := range journalEntries {
+
+		if journalEntry.Equal(opJournalEntry) && !addedFlag {
+			// This ensures to deleted only one journal entry object that is identical to a journal entry in the file log
+			addedFlag = true
+			continue
+		}
+		journalEntriesWithout = append(journalEntriesWithout, journalEntry)
+	}
+
+	output, err := json.MarshalIndent(journalEntriesWithout, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(logger.journalLogDir, output, 7777)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
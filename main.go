@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -27,14 +28,34 @@ import (
 	"github.com/Benyam-S/onepay/client/http/session"
 	delRepository "github.com/Benyam-S/onepay/deleted/repository"
 	delService "github.com/Benyam-S/onepay/deleted/service"
+	dsRepository "github.com/Benyam-S/onepay/dispute/repository"
+	dsService "github.com/Benyam-S/onepay/dispute/service"
 	"github.com/Benyam-S/onepay/entity"
 	hisRepository "github.com/Benyam-S/onepay/history/repository"
 	hisService "github.com/Benyam-S/onepay/history/service"
+	hlRepository "github.com/Benyam-S/onepay/hold/repository"
+	hlService "github.com/Benyam-S/onepay/hold/service"
+	jbRepository "github.com/Benyam-S/onepay/job/repository"
+	jbService "github.com/Benyam-S/onepay/job/service"
+	ledRepository "github.com/Benyam-S/onepay/ledger/repository"
+	ledService "github.com/Benyam-S/onepay/ledger/service"
+	lmtRepository "github.com/Benyam-S/onepay/limit/repository"
+	lmtService "github.com/Benyam-S/onepay/limit/service"
 	linkRepository "github.com/Benyam-S/onepay/linkedaccount/repository"
 	linkService "github.com/Benyam-S/onepay/linkedaccount/service"
 	"github.com/Benyam-S/onepay/logger"
 	mtRepository "github.com/Benyam-S/onepay/moneytoken/repository"
 	mtService "github.com/Benyam-S/onepay/moneytoken/service"
+	prRepository "github.com/Benyam-S/onepay/paymentrequest/repository"
+	prService "github.com/Benyam-S/onepay/paymentrequest/service"
+	rcRepository "github.com/Benyam-S/onepay/reconciliation/repository"
+	rcService "github.com/Benyam-S/onepay/reconciliation/service"
+	stRepository "github.com/Benyam-S/onepay/scheduledtransfer/repository"
+	stService "github.com/Benyam-S/onepay/scheduledtransfer/service"
+	slRepository "github.com/Benyam-S/onepay/settlement/repository"
+	slService "github.com/Benyam-S/onepay/settlement/service"
+	"github.com/Benyam-S/onepay/tools"
+	"github.com/Benyam-S/onepay/unitofwork"
 	urRepository "github.com/Benyam-S/onepay/user/repository"
 	urService "github.com/Benyam-S/onepay/user/service"
 	walRepository "github.com/Benyam-S/onepay/wallet/repository"
@@ -103,6 +124,55 @@ func initServer() {
 		panic(errors.New("unable to parse onepay config data"))
 	}
 
+	// Fee schedules are optional, methods without a schedule fall back to the flat transaction fee
+	feeSchedules := make([]*entity.FeeSchedule, 0)
+	if feeSchedulesData, ok := onepayConfig["fee_schedules"]; ok {
+		data, _ := json.Marshal(feeSchedulesData)
+		err = json.Unmarshal(data, &feeSchedules)
+		if err != nil {
+			panic(errors.New("unable to parse onepay fee schedules"))
+		}
+	}
+	feeSchedulesData, _ := json.Marshal(feeSchedules)
+
+	// Limit profiles are optional, without them every user gets the daily send limit
+	limitProfiles := make([]*entity.LimitProfile, 0)
+	if limitProfilesData, ok := onepayConfig["limit_profiles"]; ok {
+		data, _ := json.Marshal(limitProfilesData)
+		err = json.Unmarshal(data, &limitProfiles)
+		if err != nil {
+			panic(errors.New("unable to parse onepay limit profiles"))
+		}
+	}
+	limitProfilesData, _ := json.Marshal(limitProfiles)
+
+	// The currency spread is optional, without it money is converted at the exchange rate
+	currencySpread, _ := onepayConfig["currency_spread"].(float64)
+
+	// The bounds of the money token expiry are given in hours and are optional, without them the default bounds are used
+	moneyTokenMinExpiry, _ := onepayConfig["money_token_min_expiry"].(float64)
+	moneyTokenMaxExpiry, _ := onepayConfig["money_token_max_expiry"].(float64)
+
+	// Qr signing keys are optional, without them qr code payloads aren't signed
+	qrSigningKeys := make([]*entity.QRSigningKey, 0)
+	if qrSigningKeysData, ok := onepayConfig["qr_signing_keys"]; ok {
+		data, _ := json.Marshal(qrSigningKeysData)
+		err = json.Unmarshal(data, &qrSigningKeys)
+		if err != nil {
+			panic(errors.New("unable to parse onepay qr signing keys"))
+		}
+
+		for _, key := range qrSigningKeys {
+			if _, err := tools.ParseQRSigningKey(key); err != nil || key.KeyID == "" {
+				panic(errors.New("unable to parse onepay qr signing keys"))
+			}
+		}
+	}
+	qrSigningKeysData, _ := json.Marshal(qrSigningKeys)
+
+	// Once qr signing keys are set only signed payloads are accepted, unless plain codes are allowed explicitly
+	qrAcceptUnsignedCodes, _ := onepayConfig["qr_accept_unsigned_codes"].(bool)
+
 	// Setting environmental variables so they can be used any where on the application
 	os.Setenv("config_files_dir", configFilesDir)
 	os.Setenv("onepay_secret_key", sysConfig.SecretKey)
@@ -110,10 +180,18 @@ func initServer() {
 	os.Setenv("domain_name", sysConfig.DomainName)
 	os.Setenv("server_port", sysConfig.ServerPort)
 
-	os.Setenv(entity.TransactionFee, fmt.Sprintf("%f", transactionFee))
-	os.Setenv(entity.TransactionBaseLimit, fmt.Sprintf("%f", transactionBaseLimit))
-	os.Setenv(entity.WithdrawBaseLimit, fmt.Sprintf("%f", withdrawBaseLimit))
-	os.Setenv(entity.DailyTransactionLimit, fmt.Sprintf("%f", dailyTransactionLimit))
+	// Money values are stored as exact decimal strings so they can be parsed back without float rounding
+	os.Setenv(entity.TransactionFee, entity.MoneyFromFloat(transactionFee, entity.BaseCurrency).String())
+	os.Setenv(entity.TransactionBaseLimit, entity.MoneyFromFloat(transactionBaseLimit, entity.BaseCurrency).String())
+	os.Setenv(entity.WithdrawBaseLimit, entity.MoneyFromFloat(withdrawBaseLimit, entity.BaseCurrency).String())
+	os.Setenv(entity.DailyTransactionLimit, entity.MoneyFromFloat(dailyTransactionLimit, entity.BaseCurrency).String())
+	os.Setenv(entity.FeeSchedules, string(feeSchedulesData))
+	os.Setenv(entity.LimitProfiles, string(limitProfilesData))
+	os.Setenv(entity.CurrencySpread, strconv.FormatFloat(currencySpread, 'f', -1, 64))
+	os.Setenv(entity.MoneyTokenMinExpiry, strconv.FormatFloat(moneyTokenMinExpiry, 'f', -1, 64))
+	os.Setenv(entity.MoneyTokenMaxExpiry, strconv.FormatFloat(moneyTokenMaxExpiry, 'f', -1, 64))
+	os.Setenv(entity.QRSigningKeys, string(qrSigningKeysData))
+	os.Setenv(entity.QRAcceptUnsignedCodes, strconv.FormatBool(qrAcceptUnsignedCodes))
 
 	// Initializing the database with the needed tables and values
 	initDB()
@@ -124,29 +202,55 @@ func initServer() {
 	sessionRepo := urRepository.NewSessionRepository(mysqlDB)
 	apiClientRepo := urRepository.NewAPIClientRepository(mysqlDB)
 	apiTokenRepo := urRepository.NewAPITokenRepository(mysqlDB)
+	staffRepo := urRepository.NewStaffRepository(mysqlDB)
 	walletRepo := walRepository.NewWalletRepository(mysqlDB)
 	historyRepo := hisRepository.NewHistoryRepository(mysqlDB)
 	linkedAccountRepo := linkRepository.NewLinkedAccountRepository(mysqlDB)
 	moneyTokenRepo := mtRepository.NewMoneyTokenRepository(mysqlDB)
+	moneyTokenClaimRepo := mtRepository.NewClaimRepository(mysqlDB)
 	deletedUserRepo := delRepository.NewDeletedUserRepository(mysqlDB)
 	deletedLinkedAccountRepo := delRepository.NewDeletedLinkedAccountRepository(mysqlDB)
 	frozenUserRepo := delRepository.NewFrozenUserRepository(mysqlDB)
 	frozenClientRepo := delRepository.NewFrozenClientRepository(mysqlDB)
 	accountProviderRepo := apRepository.NewAccountProviderRepository(mysqlDB)
+	ledgerAccountRepo := ledRepository.NewLedgerAccountRepository(mysqlDB)
+	journalEntryRepo := ledRepository.NewJournalEntryRepository(mysqlDB)
+	disputeRepo := dsRepository.NewDisputeRepository(mysqlDB)
+	evidenceRepo := dsRepository.NewEvidenceRepository(mysqlDB)
+	scheduledTransferRepo := stRepository.NewScheduledTransferRepository(mysqlDB)
+	paymentRequestRepo := prRepository.NewPaymentRequestRepository(mysqlDB)
+	paymentGroupRepo := prRepository.NewPaymentGroupRepository(mysqlDB)
+	holdRepo := hlRepository.NewHoldRepository(mysqlDB)
+	limitRepo := lmtRepository.NewLimitRepository(mysqlDB)
+	reportRepo := rcRepository.NewReportRepository(mysqlDB)
+	discrepancyRepo := rcRepository.NewDiscrepancyRepository(mysqlDB)
+	statementRepo := slRepository.NewStatementRepository(mysqlDB)
+	settlementItemRepo := slRepository.NewItemRepository(mysqlDB)
+	jobRunRepo := jbRepository.NewJobRunRepository(mysqlDB)
 
 	/* +++++++++++++++++++++++++++ NOTIFIERS +++++++++++++++++++++++++++ */
 	changeNotifier := notifier.NewNotifier(sysConfig.ListenerURI)
 	/* +++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++ */
 
 	userService := urService.NewUserService(userRepo, passwordRepo, preferenceRepo,
-		sessionRepo, apiClientRepo, apiTokenRepo, changeNotifier)
+		sessionRepo, apiClientRepo, apiTokenRepo, staffRepo, changeNotifier)
 	deletedService := delService.NewDeletedService(deletedUserRepo, deletedLinkedAccountRepo,
 		frozenUserRepo, frozenClientRepo)
 	walletService := walService.NewWalletService(walletRepo, changeNotifier)
 	historyService := hisService.NewHistoryService(historyRepo, changeNotifier)
 	linkedAccountService := linkService.NewLinkedAccountService(linkedAccountRepo)
-	moneyTokenService := mtService.NewMoneyTokenService(moneyTokenRepo)
+	moneyTokenService := mtService.NewMoneyTokenService(moneyTokenRepo, moneyTokenClaimRepo, changeNotifier)
 	accountProviderService := apService.NewAccountProviderService(accountProviderRepo)
+	ledgerService := ledService.NewLedgerService(ledgerAccountRepo, journalEntryRepo)
+	disputeService := dsService.NewDisputeService(disputeRepo, evidenceRepo)
+	scheduledTransferService := stService.NewScheduledTransferService(scheduledTransferRepo, changeNotifier)
+	paymentRequestService := prService.NewPaymentRequestService(paymentRequestRepo, paymentGroupRepo, changeNotifier)
+	holdService := hlService.NewHoldService(holdRepo)
+	limitService := lmtService.NewLimitService(limitRepo)
+	reconciliationService := rcService.NewReconciliationService(reportRepo, discrepancyRepo)
+	settlementService := slService.NewSettlementService(statementRepo, settlementItemRepo)
+	jobService := jbService.NewJobService(jobRunRepo)
+	unitOfWorkManager := unitofwork.NewManager(mysqlDB)
 
 	path, _ := os.Getwd()
 	path = filepath.Join(path, "./logger")
@@ -159,7 +263,20 @@ func initServer() {
 	}
 
 	onepay = app.NewApp(walletService, historyService, linkedAccountService,
-		moneyTokenService, accountProviderService, dataLogger, channel)
+		moneyTokenService, accountProviderService, ledgerService, disputeService, scheduledTransferService, paymentRequestService, holdService,
+		limitService, userService, reconciliationService, settlementService, jobService, unitOfWorkManager, dataLogger, channel)
+
+	// Creating the revenue wallet that collects the fees, it has to exist before any transaction is made
+	err = onepay.OpenRevenueWallet()
+	if err != nil {
+		panic(err)
+	}
+
+	// Opening ledger accounts for wallets that were created before the ledger
+	err = onepay.OpenLedger()
+	if err != nil {
+		panic(err)
+	}
 
 	userHandler = urHandler.NewUserHandler(userService, redisClient)
 	userAPIHandler = urAPIHandler.NewUserAPIHandler(onepay, userService, deletedService,
@@ -190,16 +307,53 @@ func initDB() {
 	mysqlDB.AutoMigrate(&entity.UserPassword{})
 	mysqlDB.AutoMigrate(&entity.UserPreference{})
 	mysqlDB.AutoMigrate(&entity.User{})
+	mysqlDB.AutoMigrate(&entity.Staff{})
 	mysqlDB.AutoMigrate(&session.ServerSession{})
 	mysqlDB.AutoMigrate(&api.Client{})
 	mysqlDB.AutoMigrate(&api.Token{})
 	mysqlDB.AutoMigrate(&entity.UserHistory{})
 	mysqlDB.AutoMigrate(&entity.UserWallet{})
 	mysqlDB.AutoMigrate(&entity.MoneyToken{})
+	mysqlDB.AutoMigrate(&entity.MoneyTokenClaim{})
 	mysqlDB.AutoMigrate(&entity.LinkedAccount{})
 	mysqlDB.AutoMigrate(&entity.DeletedUser{})
 	mysqlDB.AutoMigrate(&entity.DeletedLinkedAccount{})
 	mysqlDB.AutoMigrate(&entity.AccountProvider{})
+	mysqlDB.AutoMigrate(&entity.LedgerAccount{})
+	mysqlDB.AutoMigrate(&entity.JournalEntry{})
+	mysqlDB.AutoMigrate(&entity.Posting{})
+	mysqlDB.AutoMigrate(&entity.Dispute{})
+	mysqlDB.AutoMigrate(&entity.DisputeEvidence{})
+	mysqlDB.AutoMigrate(&entity.ScheduledTransfer{})
+	mysqlDB.AutoMigrate(&entity.PaymentRequest{})
+	mysqlDB.AutoMigrate(&entity.PaymentGroup{})
+	mysqlDB.AutoMigrate(&entity.WalletHold{})
+	mysqlDB.AutoMigrate(&entity.UserLimit{})
+	mysqlDB.AutoMigrate(&entity.ReconciliationReport{})
+	mysqlDB.AutoMigrate(&entity.WalletDiscrepancy{})
+	mysqlDB.AutoMigrate(&entity.SettlementStatement{})
+	mysqlDB.AutoMigrate(&entity.SettlementItem{})
+	mysqlDB.AutoMigrate(&entity.JobRun{})
+
+	// Converting money columns that were stored as float to integer minor units
+	err = migrateMoneyColumns()
+	if err != nil {
+		panic(err)
+	}
+
+	// Keying the wallets by user and currency so a user can hold a wallet for each currency
+	err = migrateWalletCurrency()
+	if err != nil {
+		panic(err)
+	}
+
+	// Money tokens created before they could be claimed more than once still have their whole amount and fee locked
+	err = mysqlDB.Exec("UPDATE money_tokens SET remaining = amount, remaining_fee = fee "+
+		"WHERE method = ? AND status = ? AND claim_count = 0 AND remaining = 0",
+		entity.MethodTransactionQRCode, entity.MoneyTokenStatusActive).Error
+	if err != nil {
+		panic(err)
+	}
 
 	/* +++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++ */
 	count := 0
@@ -212,6 +366,75 @@ func initDB() {
 	/* +++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++++ */
 }
 
+// migrateMoneyColumns converts the amount columns that were created as float columns to bigint minor units.
+// Tables that already hold minor units are left as they are, so it is safe to run on every start.
+func migrateMoneyColumns() error {
+
+	tables := []string{"user_wallets", "user_history", "money_tokens", "postings"}
+	scale := int64(math.Pow10(entity.CurrencyExponent(entity.BaseCurrency)))
+	for _, table := range tables {
+
+		var dataType string
+		row := mysqlDB.Raw("SELECT DATA_TYPE FROM information_schema.COLUMNS "+
+			"WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = 'amount'", table).Row()
+		if row.Scan(&dataType) != nil || (dataType != "float" && dataType != "double" && dataType != "decimal") {
+			continue
+		}
+
+		// Widening first so that the multiplication doesn't lose precision
+		statements := []string{
+			fmt.Sprintf("ALTER TABLE %s MODIFY amount DOUBLE NOT NULL", table),
+			fmt.Sprintf("UPDATE %s SET amount = ROUND(amount * %d)", table, scale),
+			fmt.Sprintf("ALTER TABLE %s MODIFY amount BIGINT NOT NULL", table),
+		}
+
+		for _, statement := range statements {
+			err := mysqlDB.Exec(statement).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// migrateWalletCurrency changes the primary key of the user wallets from the user id alone to the user id and currency.
+// AutoMigrate has already added the currency column with the base currency as default, so existing wallets become base currency wallets.
+func migrateWalletCurrency() error {
+
+	count := 0
+	row := mysqlDB.Raw("SELECT COUNT(*) FROM information_schema.KEY_COLUMN_USAGE WHERE TABLE_SCHEMA = DATABASE() " +
+		"AND TABLE_NAME = 'user_wallets' AND CONSTRAINT_NAME = 'PRIMARY' AND COLUMN_NAME = 'currency'").Row()
+	if row.Scan(&count) != nil || count > 0 {
+		return nil
+	}
+
+	// The unique user id index has to be dropped, otherwise a user can't have more than one wallet
+	var indexNames []string
+	rows, err := mysqlDB.Raw("SELECT DISTINCT INDEX_NAME FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE() " +
+		"AND TABLE_NAME = 'user_wallets' AND COLUMN_NAME = 'user_id' AND NON_UNIQUE = 0 AND INDEX_NAME != 'PRIMARY'").Rows()
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var indexName string
+		rows.Scan(&indexName)
+		indexNames = append(indexNames, indexName)
+	}
+	rows.Close()
+
+	for _, indexName := range indexNames {
+		err = mysqlDB.Exec(fmt.Sprintf("ALTER TABLE user_wallets DROP INDEX `%s`", indexName)).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return mysqlDB.Exec("ALTER TABLE user_wallets DROP PRIMARY KEY, ADD PRIMARY KEY (user_id, currency)").Error
+}
+
 func main() {
 
 	configFilesDir = "C:/Users/Administrator/go/src/github.com/Benyam-S/onepay/config"
@@ -231,6 +454,38 @@ func main() {
 		}
 	}()
 
+	// Running the background jobs, each job is run by only one of the server instances at a time
+	scheduler := app.NewScheduler(onepay, redisClient)
+
+	// Making the scheduled transfers that are due
+	scheduler.Register(entity.JobScheduledTransfers, time.Minute, func() error {
+		onepay.RunScheduledTransfers(redisClient)
+		return nil
+	})
+
+	// Releasing the money of holds that have expired without being captured
+	scheduler.Register(entity.JobExpireHolds, time.Minute, func() error {
+		onepay.ExpireHolds(redisClient)
+		return nil
+	})
+
+	// Returning the money left in expired money tokens, claimed in part or not at all, to their senders
+	scheduler.Register(entity.JobReclaimMoneyTokens, time.Minute, onepay.ReclaimExpiredMoneyTokens)
+
+	// Reconciling the wallets with the user histories, scheduled runs only report the discrepancies
+	scheduler.Register(entity.JobReconcileWallets, app.ReconciliationInterval, func() error {
+		_, err := onepay.ReconcileWallets(entity.ReconciliationTriggerScheduled, false)
+		return err
+	})
+
+	// Auditing the ledger and the wallets against their ledger accounts, a failed run reports the problem
+	scheduler.Register(entity.JobAuditLedger, app.LedgerAuditInterval, onepay.RunLedgerAudit)
+
+	// Deleting the job runs that are older than the retention period, so a run row a minute doesn't pile up
+	scheduler.Register(entity.JobPruneJobRuns, app.JobRunPruneInterval, onepay.PruneJobRuns)
+
+	scheduler.Start()
+
 	go func() {
 
 		for {
@@ -242,6 +497,10 @@ func main() {
 				onepay.ReloadMoneyToken()
 				onepay.ReloadWallet()
 				onepay.ReloadHistory()
+				onepay.ReloadJournalEntry()
+
+			case "reload_journal_entry":
+				onepay.ReloadJournalEntry()
 
 			case "reload_money_token":
 				onepay.ReloadMoneyToken()
@@ -4,16 +4,16 @@ import "github.com/Benyam-S/onepay/entity"
 
 // GetAccountInfo is
 func GetAccountInfo(accountID, accessToken string) (*entity.AccountInfo, error) {
-	return &entity.AccountInfo{Amount: 200}, nil
+	return &entity.AccountInfo{Amount: entity.NewMoney(20000, entity.BaseCurrency)}, nil
 }
 
 // RefillAccount is
-func RefillAccount(accountID, accessToken string, amount float64) error {
+func RefillAccount(accountID, accessToken, reference string, amount entity.Money) error {
 	return nil
 }
 
 // WithdrawFromAccount is
-func WithdrawFromAccount(accountID, accessToken string, amount float64) error {
+func WithdrawFromAccount(accountID, accessToken, reference string, amount entity.Money) error {
 	return nil
 }
 
@@ -1,16 +1,30 @@
 package moneytoken
 
-import "github.com/Benyam-S/onepay/entity"
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
 
 // IMoneyTokenRepository is an interface that defines all the repository methods of a money token struct
 type IMoneyTokenRepository interface {
 	Create(newMoneyToken *entity.MoneyToken) error
 	Find(identifier string) (*entity.MoneyToken, error)
-	Search(identifier string) []*entity.MoneyToken
+	FindForUpdate(identifier string) (*entity.MoneyToken, error)
+	Search(identifier string, statuses []string) []*entity.MoneyToken
 	Expired() []*entity.MoneyToken
 	Update(moneyToken *entity.MoneyToken) error
 	UpdateValue(moneyToken *entity.MoneyToken, columnName string, columnValue interface{}) error
+	AddFailedAttempt(identifier string, maxAttempts int) error
 	Delete(identifier string) (*entity.MoneyToken, error)
 	DeleteMultiple(identifier string) ([]*entity.MoneyToken, error)
 	IsUnique(columnName string, columnValue interface{}) bool
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IMoneyTokenRepository
+}
+
+// IClaimRepository is an interface that defines all the repository methods of a money token claim struct
+type IClaimRepository interface {
+	Create(newClaim *entity.MoneyTokenClaim) error
+	Find(code, claimantID string) (*entity.MoneyTokenClaim, error)
+	Search(code string) []*entity.MoneyTokenClaim
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IClaimRepository
 }
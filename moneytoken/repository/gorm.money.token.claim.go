@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/moneytoken"
+	"github.com/Benyam-S/onepay/unitofwork"
+	"github.com/jinzhu/gorm"
+)
+
+// ClaimRepository is a type that defines a money token claim repository
+type ClaimRepository struct {
+	conn *gorm.DB
+}
+
+// NewClaimRepository is a function that returns a new money token claim repository
+func NewClaimRepository(connection *gorm.DB) moneytoken.IClaimRepository {
+	return &ClaimRepository{conn: connection}
+}
+
+// Create is a method that adds a new money token claim to the database
+func (repo *ClaimRepository) Create(newClaim *entity.MoneyTokenClaim) error {
+
+	err := repo.conn.Create(newClaim).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds the claim a certain user has made on a money token
+func (repo *ClaimRepository) Find(code, claimantID string) (*entity.MoneyTokenClaim, error) {
+	claim := new(entity.MoneyTokenClaim)
+	err := repo.conn.Model(claim).
+		Where("code = ? AND claimant_id = ?", code, claimantID).
+		First(claim).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+// Search is a method that returns all the claims made on a certain money token
+func (repo *ClaimRepository) Search(code string) []*entity.MoneyTokenClaim {
+	var claims []*entity.MoneyTokenClaim
+	err := repo.conn.Model(entity.MoneyTokenClaim{}).
+		Where("code = ?", code).
+		Order("id").Find(&claims).Error
+
+	if err != nil {
+		return []*entity.MoneyTokenClaim{}
+	}
+	return claims
+}
+
+// WithUnitOfWork is a method that returns a money token claim repository that runs its queries inside the provided unit of work
+func (repo *ClaimRepository) WithUnitOfWork(uow *unitofwork.UnitOfWork) moneytoken.IClaimRepository {
+	return &ClaimRepository{conn: uow.Conn()}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Benyam-S/onepay/entity"
 	"github.com/Benyam-S/onepay/moneytoken"
 	"github.com/Benyam-S/onepay/tools"
+	"github.com/Benyam-S/onepay/unitofwork"
 	"github.com/jinzhu/gorm"
 )
 
@@ -50,13 +51,30 @@ func (repo *MoneyTokenRepository) Find(identifier string) (*entity.MoneyToken, e
 	return moneyToken, nil
 }
 
+// FindForUpdate is a method that finds a certain money token from the database using an identifier and locks it
+// until the transaction it is read in ends, so claims on the same money token are made one at a time
+func (repo *MoneyTokenRepository) FindForUpdate(identifier string) (*entity.MoneyToken, error) {
+	moneyToken := new(entity.MoneyToken)
+	err := repo.conn.Set("gorm:query_option", "FOR UPDATE").Model(moneyToken).
+		Where("code = ?", identifier).First(moneyToken).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return moneyToken, nil
+}
+
 // Search is a method that search and returns a set of money tokens that is limited to the provided identifier
-// Search uses sender_id only as a key since there is no other important entity left used for searching
-func (repo *MoneyTokenRepository) Search(identifier string) []*entity.MoneyToken {
+// Search uses sender_id only as a key since there is no other important entity left used for searching.
+// If statuses are provided only the money tokens with one of the statuses are returned.
+func (repo *MoneyTokenRepository) Search(identifier string, statuses []string) []*entity.MoneyToken {
 	var moneyTokens []*entity.MoneyToken
-	err := repo.conn.Model(entity.MoneyToken{}).
-		Where("sender_id = ?", identifier).
-		Find(&moneyTokens).Error
+	query := repo.conn.Model(entity.MoneyToken{}).Where("sender_id = ?", identifier)
+	if len(statuses) > 0 {
+		query = query.Where("status IN (?)", statuses)
+	}
+
+	err := query.Order("sent_at DESC").Find(&moneyTokens).Error
 
 	if err != nil {
 		return []*entity.MoneyToken{}
@@ -64,11 +82,11 @@ func (repo *MoneyTokenRepository) Search(identifier string) []*entity.MoneyToken
 	return moneyTokens
 }
 
-// Expired is a method that returns all the expired moneytokens
+// Expired is a method that returns all the active moneytokens that have passed their expiration date
 func (repo *MoneyTokenRepository) Expired() []*entity.MoneyToken {
 	var moneyTokens []*entity.MoneyToken
 	err := repo.conn.Model(entity.MoneyToken{}).
-		Where("expiration_date < ?", time.Now()).
+		Where("status = ? AND expiration_date < ?", entity.MoneyTokenStatusActive, time.Now()).
 		Find(&moneyTokens).Error
 
 	if err != nil {
@@ -111,6 +129,20 @@ func (repo *MoneyTokenRepository) UpdateValue(moneyToken *entity.MoneyToken, col
 	return nil
 }
 
+// AddFailedAttempt is a method that counts a failed attempt on a certain money token and locks the money token
+// once the failed attempts reach the provided max attempts. The count is increased in the database so concurrent attempts are all counted.
+func (repo *MoneyTokenRepository) AddFailedAttempt(identifier string, maxAttempts int) error {
+
+	err := repo.conn.Model(entity.MoneyToken{}).Where("code = ?", identifier).
+		UpdateColumn("failed_attempts", gorm.Expr("failed_attempts + 1")).Error
+	if err != nil {
+		return err
+	}
+
+	return repo.conn.Model(entity.MoneyToken{}).Where("code = ? AND failed_attempts >= ?", identifier, maxAttempts).
+		UpdateColumn("locked", true).Error
+}
+
 // Delete is a method that deletes a certain money token from the database using an identifier.
 // In Delete() code is only used as a key
 func (repo *MoneyTokenRepository) Delete(identifier string) (*entity.MoneyToken, error) {
@@ -149,3 +181,8 @@ func (repo *MoneyTokenRepository) IsUnique(columnName string, columnValue interf
 	repo.conn.Model(&entity.MoneyToken{}).Where(columnName+"=?", columnValue).Count(&totalCount)
 	return 0 >= totalCount
 }
+
+// WithUnitOfWork is a method that returns a money token repository that runs its queries inside the provided unit of work
+func (repo *MoneyTokenRepository) WithUnitOfWork(uow *unitofwork.UnitOfWork) moneytoken.IMoneyTokenRepository {
+	return &MoneyTokenRepository{conn: uow.Conn()}
+}
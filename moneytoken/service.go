@@ -1,15 +1,26 @@
 package moneytoken
 
-import "github.com/Benyam-S/onepay/entity"
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
 
 // IService is an interface that defines all the service methods of a money token struct
 type IService interface {
 	AddMoneyToken(newMoneyToken *entity.MoneyToken) error
 	FindMoneyToken(identifier string) (*entity.MoneyToken, error)
-	SearchMoneyToken(identifier string) []*entity.MoneyToken
+	LockMoneyToken(identifier string) (*entity.MoneyToken, error)
+	SearchMoneyToken(identifier string, statuses ...string) []*entity.MoneyToken
 	ExpiredMoneyTokens() []*entity.MoneyToken
 	UpdateMoneyToken(moneyToken *entity.MoneyToken) error
 	UpdateMoneyTokenSingleValue(code, columnName string, columnValue interface{}) error
+	SetMoneyTokenSecret(moneyToken *entity.MoneyToken, secret string) error
+	CheckMoneyTokenSecret(moneyToken *entity.MoneyToken, secret string) bool
+	AddFailedAttempt(code string, maxAttempts int) error
 	DeleteMoneyToken(code string) (*entity.MoneyToken, error)
 	DeleteMoneyTokens(senderID string) ([]*entity.MoneyToken, error)
+	AddClaim(newClaim *entity.MoneyTokenClaim) error
+	FindClaim(code, claimantID string) (*entity.MoneyTokenClaim, error)
+	MoneyTokenClaims(code string) []*entity.MoneyTokenClaim
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IService
 }
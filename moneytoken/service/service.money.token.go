@@ -1,29 +1,47 @@
 package service
 
 import (
+	"encoding/base64"
 	"errors"
 	"regexp"
-	"time"
 
 	"github.com/Benyam-S/onepay/entity"
 	"github.com/Benyam-S/onepay/moneytoken"
+	"github.com/Benyam-S/onepay/notifier"
+	"github.com/Benyam-S/onepay/tools"
+	"github.com/Benyam-S/onepay/unitofwork"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Service is a type that defines money token service
 type Service struct {
 	moneyTokenRepo moneytoken.IMoneyTokenRepository
+	claimRepo      moneytoken.IClaimRepository
+	notifier       *notifier.Notifier
+	uow            *unitofwork.UnitOfWork
 }
 
 // NewMoneyTokenService is a function that returns a new money token service
-func NewMoneyTokenService(moneyTokenRepository moneytoken.IMoneyTokenRepository) moneytoken.IService {
-	return &Service{moneyTokenRepo: moneyTokenRepository}
+func NewMoneyTokenService(moneyTokenRepository moneytoken.IMoneyTokenRepository,
+	claimRepository moneytoken.IClaimRepository, moneyTokenChangeNotifier *notifier.Notifier) moneytoken.IService {
+	return &Service{moneyTokenRepo: moneyTokenRepository, claimRepo: claimRepository, notifier: moneyTokenChangeNotifier}
 }
 
 // AddMoneyToken is a method that adds a new money token to the system
 func (service *Service) AddMoneyToken(newMoneyToken *entity.MoneyToken) error {
 
-	// Token will expire after 48 hours
-	newMoneyToken.ExpirationDate = time.Now().Add(time.Hour * 48)
+	// The expiration date is picked by the sender so only the state of the new money token is set
+	newMoneyToken.Status = entity.MoneyTokenStatusActive
+
+	// Money tokens are claimed only once unless stated otherwise
+	if newMoneyToken.MaxClaims < 1 {
+		newMoneyToken.MaxClaims = 1
+	}
+
+	if newMoneyToken.ClaimMode == "" {
+		newMoneyToken.ClaimMode = entity.MoneyTokenClaimFixed
+	}
+
 	err := service.moneyTokenRepo.Create(newMoneyToken)
 	if err != nil {
 		return errors.New("unable to add new money token")
@@ -46,15 +64,26 @@ func (service *Service) FindMoneyToken(identifier string) (*entity.MoneyToken, e
 	return moneyToken, nil
 }
 
-// SearchMoneyToken is a method that search and returns a set of money tokens for the provided identifier
-func (service *Service) SearchMoneyToken(identifier string) []*entity.MoneyToken {
+// LockMoneyToken is a method that finds a certain money token using the identifier and locks it for the rest of the unit of work
+func (service *Service) LockMoneyToken(identifier string) (*entity.MoneyToken, error) {
+
+	moneyToken, err := service.moneyTokenRepo.FindForUpdate(identifier)
+	if err != nil {
+		return nil, errors.New("money token not found")
+	}
+	return moneyToken, nil
+}
+
+// SearchMoneyToken is a method that search and returns a set of money tokens for the provided identifier,
+// if statuses are provided only the money tokens with one of the statuses are returned
+func (service *Service) SearchMoneyToken(identifier string, statuses ...string) []*entity.MoneyToken {
 
 	empty, _ := regexp.MatchString(`^\s*$`, identifier)
 	if empty {
 		return []*entity.MoneyToken{}
 	}
 
-	return service.moneyTokenRepo.Search(identifier)
+	return service.moneyTokenRepo.Search(identifier, statuses)
 }
 
 // ExpiredMoneyTokens is a method that returns all the expired money tokens
@@ -62,13 +91,18 @@ func (service *Service) ExpiredMoneyTokens() []*entity.MoneyToken {
 	return service.moneyTokenRepo.Expired()
 }
 
-// UpdateMoneyToken is a method that updates a certain money token values
+// UpdateMoneyToken is a method that updates a certain money token values and notifies the sender
 func (service *Service) UpdateMoneyToken(moneyToken *entity.MoneyToken) error {
 
 	err := service.moneyTokenRepo.Update(moneyToken)
 	if err != nil {
 		return errors.New("unable to update money token")
 	}
+
+	/* ++++++++++++++ NOTIFYING CHANGE +++++++++++++++ */
+	service.notify(func() { service.notifier.NotifyMoneyTokenChange(moneyToken) })
+	/* +++++++++++++++++++++++++++++++++++++++++++++++ */
+
 	return nil
 }
 
@@ -84,6 +118,46 @@ func (service *Service) UpdateMoneyTokenSingleValue(code, columnName string, col
 	return nil
 }
 
+// SetMoneyTokenSecret is a method that validates the provided secret and attaches its hash to the money token
+func (service *Service) SetMoneyTokenSecret(moneyToken *entity.MoneyToken, secret string) error {
+
+	matchSecret, _ := regexp.MatchString(`^[a-zA-Z0-9]{4,12}$`, secret)
+	if !matchSecret {
+		return errors.New(entity.InvalidSecretFormatError)
+	}
+
+	moneyToken.SecretSalt = tools.GenerateRandomString(30)
+	hashedSecret, err := bcrypt.GenerateFromPassword([]byte(secret+moneyToken.SecretSalt), 12)
+	if err != nil {
+		return errors.New("unable to hash money token secret")
+	}
+
+	moneyToken.Secret = base64.StdEncoding.EncodeToString(hashedSecret)
+	moneyToken.Protected = true
+	return nil
+}
+
+// CheckMoneyTokenSecret is a method that checks whether the provided secret matches the secret of the money token
+func (service *Service) CheckMoneyTokenSecret(moneyToken *entity.MoneyToken, secret string) bool {
+
+	if !moneyToken.Protected {
+		return true
+	}
+
+	hashedSecret, _ := base64.StdEncoding.DecodeString(moneyToken.Secret)
+	return bcrypt.CompareHashAndPassword(hashedSecret, []byte(secret+moneyToken.SecretSalt)) == nil
+}
+
+// AddFailedAttempt is a method that counts a failed attempt on a money token, locking it once it reaches the max attempts
+func (service *Service) AddFailedAttempt(code string, maxAttempts int) error {
+
+	err := service.moneyTokenRepo.AddFailedAttempt(code, maxAttempts)
+	if err != nil {
+		return errors.New("unable to update money token")
+	}
+	return nil
+}
+
 // DeleteMoneyToken is a method that deletes an money token from the system
 func (service *Service) DeleteMoneyToken(code string) (*entity.MoneyToken, error) {
 
@@ -103,3 +177,44 @@ func (service *Service) DeleteMoneyTokens(senderID string) ([]*entity.MoneyToken
 	}
 	return moneyTokens, nil
 }
+
+// AddClaim is a method that records a new claim made on a money token
+func (service *Service) AddClaim(newClaim *entity.MoneyTokenClaim) error {
+
+	err := service.claimRepo.Create(newClaim)
+	if err != nil {
+		return errors.New("unable to add new money token claim")
+	}
+	return nil
+}
+
+// FindClaim is a method that finds the claim a certain user has made on a money token
+func (service *Service) FindClaim(code, claimantID string) (*entity.MoneyTokenClaim, error) {
+
+	claim, err := service.claimRepo.Find(code, claimantID)
+	if err != nil {
+		return nil, errors.New("money token claim not found")
+	}
+	return claim, nil
+}
+
+// MoneyTokenClaims is a method that returns all the claims made on a certain money token
+func (service *Service) MoneyTokenClaims(code string) []*entity.MoneyTokenClaim {
+	return service.claimRepo.Search(code)
+}
+
+// WithUnitOfWork is a method that returns a money token service whose changes are made inside the provided unit of work
+func (service *Service) WithUnitOfWork(uow *unitofwork.UnitOfWork) moneytoken.IService {
+	return &Service{moneyTokenRepo: service.moneyTokenRepo.WithUnitOfWork(uow),
+		claimRepo: service.claimRepo.WithUnitOfWork(uow), notifier: service.notifier, uow: uow}
+}
+
+// notify is a method that runs the provided notification immediately or,
+// if the service is bound to a unit of work, once the unit of work has been committed
+func (service *Service) notify(f func()) {
+	if service.uow != nil {
+		service.uow.AfterCommit(f)
+		return
+	}
+	f()
+}
@@ -98,3 +98,66 @@ func (notifier Notifier) NotifyHistoryChange(history *entity.UserHistory) error
 
 	return nil
 }
+
+// NotifyScheduledTransferChange is a method that notify a certain scheduled transfer change to its listener
+func (notifier Notifier) NotifyScheduledTransferChange(transfer *entity.ScheduledTransfer) error {
+
+	client := new(http.Client)
+	jsonOutput, _ := json.MarshalIndent(transfer, "", "\t")
+	output := bytes.NewBuffer(jsonOutput)
+	url := notifier.ListenerURI + "/api/v1/listener/scheduledtransfer"
+
+	request, err := http.NewRequest("PUT", url, output)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NotifyPaymentRequestChange is a method that notify a certain payment request change to its listener
+func (notifier Notifier) NotifyPaymentRequestChange(paymentRequest *entity.PaymentRequest) error {
+
+	client := new(http.Client)
+	jsonOutput, _ := json.MarshalIndent(paymentRequest, "", "\t")
+	output := bytes.NewBuffer(jsonOutput)
+	url := notifier.ListenerURI + "/api/v1/listener/paymentrequest"
+
+	request, err := http.NewRequest("PUT", url, output)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NotifyMoneyTokenChange is a method that notify a certain money token change to its listener
+func (notifier Notifier) NotifyMoneyTokenChange(moneyToken *entity.MoneyToken) error {
+
+	client := new(http.Client)
+	jsonOutput, _ := json.MarshalIndent(moneyToken, "", "\t")
+	output := bytes.NewBuffer(jsonOutput)
+	url := notifier.ListenerURI + "/api/v1/listener/moneytoken"
+
+	request, err := http.NewRequest("PUT", url, output)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
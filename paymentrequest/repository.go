@@ -0,0 +1,31 @@
+package paymentrequest
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// IPaymentRequestRepository is an interface that defines all the repository methods of a payment request struct
+type IPaymentRequestRepository interface {
+	Create(newRequest *entity.PaymentRequest) error
+	Find(identifier int64) (*entity.PaymentRequest, error)
+	FindForUpdate(identifier int64) (*entity.PaymentRequest, error)
+	Search(userID, role string, statuses []string) []*entity.PaymentRequest
+	SearchByGroup(groupID int64) []*entity.PaymentRequest
+	Expire(now time.Time) error
+	Update(request *entity.PaymentRequest) error
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IPaymentRequestRepository
+}
+
+// IPaymentGroupRepository is an interface that defines all the repository methods of a payment group struct
+type IPaymentGroupRepository interface {
+	Create(newGroup *entity.PaymentGroup) error
+	Find(identifier int64) (*entity.PaymentGroup, error)
+	FindForUpdate(identifier int64) (*entity.PaymentGroup, error)
+	Search(initiatorID string) []*entity.PaymentGroup
+	Update(group *entity.PaymentGroup) error
+	CloseIncomplete() error
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IPaymentGroupRepository
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/paymentrequest"
+	"github.com/Benyam-S/onepay/unitofwork"
+	"github.com/jinzhu/gorm"
+)
+
+// PaymentGroupRepository is a type that defines a payment group repository
+type PaymentGroupRepository struct {
+	conn *gorm.DB
+}
+
+// NewPaymentGroupRepository is a function that returns a new payment group repository
+func NewPaymentGroupRepository(connection *gorm.DB) paymentrequest.IPaymentGroupRepository {
+	return &PaymentGroupRepository{conn: connection}
+}
+
+// Create is a method that adds a new payment group to the database
+func (repo *PaymentGroupRepository) Create(newGroup *entity.PaymentGroup) error {
+
+	err := repo.conn.Create(newGroup).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds a certain payment group from the database using an identifier.
+// In Find() id is only used as a key
+func (repo *PaymentGroupRepository) Find(identifier int64) (*entity.PaymentGroup, error) {
+	group := new(entity.PaymentGroup)
+	err := repo.conn.Model(group).
+		Where("id = ?", identifier).First(group).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// FindForUpdate is a method that finds a certain payment group from the database using an identifier and locks it
+// until the transaction it is read in ends
+func (repo *PaymentGroupRepository) FindForUpdate(identifier int64) (*entity.PaymentGroup, error) {
+	group := new(entity.PaymentGroup)
+	err := repo.conn.Set("gorm:query_option", "FOR UPDATE").Model(group).
+		Where("id = ?", identifier).First(group).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// Search is a method that returns all the payment groups a certain user has initiated
+func (repo *PaymentGroupRepository) Search(initiatorID string) []*entity.PaymentGroup {
+	var groups []*entity.PaymentGroup
+	err := repo.conn.Model(entity.PaymentGroup{}).
+		Where("initiator_id = ?", initiatorID).
+		Order("id DESC").Find(&groups).Error
+
+	if err != nil {
+		return []*entity.PaymentGroup{}
+	}
+	return groups
+}
+
+// Update is a method that updates a certain payment group value in the database
+func (repo *PaymentGroupRepository) Update(group *entity.PaymentGroup) error {
+
+	prevGroup := new(entity.PaymentGroup)
+	err := repo.conn.Model(prevGroup).Where("id = ?", group.ID).First(prevGroup).Error
+
+	if err != nil {
+		return err
+	}
+
+	err = repo.conn.Save(group).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CloseIncomplete is a method that marks every open payment group that has a declined, cancelled or expired payment request as incomplete
+func (repo *PaymentGroupRepository) CloseIncomplete() error {
+
+	closedGroups := repo.conn.Model(entity.PaymentRequest{}).Select("group_id").
+		Where("group_id <> 0 AND status IN (?)", []string{entity.PaymentRequestStatusDeclined,
+			entity.PaymentRequestStatusCancelled, entity.PaymentRequestStatusExpired}).QueryExpr()
+
+	err := repo.conn.Model(entity.PaymentGroup{}).
+		Where("status = ? AND id IN (?)", entity.PaymentGroupStatusOpen, closedGroups).
+		Update("status", entity.PaymentGroupStatusIncomplete).Error
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// WithUnitOfWork is a method that returns a payment group repository that runs its queries inside the provided unit of work
+func (repo *PaymentGroupRepository) WithUnitOfWork(uow *unitofwork.UnitOfWork) paymentrequest.IPaymentGroupRepository {
+	return &PaymentGroupRepository{conn: uow.Conn()}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/paymentrequest"
+	"github.com/Benyam-S/onepay/unitofwork"
+	"github.com/jinzhu/gorm"
+)
+
+// PaymentRequestRepository is a type that defines a payment request repository
+type PaymentRequestRepository struct {
+	conn *gorm.DB
+}
+
+// NewPaymentRequestRepository is a function that returns a new payment request repository
+func NewPaymentRequestRepository(connection *gorm.DB) paymentrequest.IPaymentRequestRepository {
+	return &PaymentRequestRepository{conn: connection}
+}
+
+// Create is a method that adds a new payment request to the database
+func (repo *PaymentRequestRepository) Create(newRequest *entity.PaymentRequest) error {
+
+	err := repo.conn.Create(newRequest).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds a certain payment request from the database using an identifier.
+// In Find() id is only used as a key
+func (repo *PaymentRequestRepository) Find(identifier int64) (*entity.PaymentRequest, error) {
+	request := new(entity.PaymentRequest)
+	err := repo.conn.Model(request).
+		Where("id = ?", identifier).First(request).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// FindForUpdate is a method that finds a certain payment request from the database using an identifier and locks it
+// until the transaction it is read in ends
+func (repo *PaymentRequestRepository) FindForUpdate(identifier int64) (*entity.PaymentRequest, error) {
+	request := new(entity.PaymentRequest)
+	err := repo.conn.Set("gorm:query_option", "FOR UPDATE").Model(request).
+		Where("id = ?", identifier).First(request).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// Search is a method that returns the payment requests a certain user has made, if the role is requester,
+// or has received, if the role is payer, that have one of the provided statuses
+func (repo *PaymentRequestRepository) Search(userID, role string, statuses []string) []*entity.PaymentRequest {
+	var requests []*entity.PaymentRequest
+
+	column := "payer_id"
+	if role == "requester" {
+		column = "requester_id"
+	}
+
+	query := repo.conn.Model(entity.PaymentRequest{}).Where(column+" = ?", userID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN (?)", statuses)
+	}
+
+	err := query.Order("id DESC").Find(&requests).Error
+	if err != nil {
+		return []*entity.PaymentRequest{}
+	}
+	return requests
+}
+
+// SearchByGroup is a method that returns all the payment requests that belong to a certain payment group
+func (repo *PaymentRequestRepository) SearchByGroup(groupID int64) []*entity.PaymentRequest {
+	var requests []*entity.PaymentRequest
+	err := repo.conn.Model(entity.PaymentRequest{}).
+		Where("group_id = ?", groupID).
+		Order("id").Find(&requests).Error
+
+	if err != nil {
+		return []*entity.PaymentRequest{}
+	}
+	return requests
+}
+
+// Expire is a method that marks all the pending payment requests whose expiry has passed as expired
+func (repo *PaymentRequestRepository) Expire(now time.Time) error {
+
+	err := repo.conn.Model(entity.PaymentRequest{}).
+		Where("status = ? AND expires_at < ?", entity.PaymentRequestStatusPending, now).
+		Update("status", entity.PaymentRequestStatusExpired).Error
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update is a method that updates a certain payment request value in the database
+func (repo *PaymentRequestRepository) Update(request *entity.PaymentRequest) error {
+
+	prevRequest := new(entity.PaymentRequest)
+	err := repo.conn.Model(prevRequest).Where("id = ?", request.ID).First(prevRequest).Error
+
+	if err != nil {
+		return err
+	}
+
+	err = repo.conn.Save(request).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// WithUnitOfWork is a method that returns a payment request repository that runs its queries inside the provided unit of work
+func (repo *PaymentRequestRepository) WithUnitOfWork(uow *unitofwork.UnitOfWork) paymentrequest.IPaymentRequestRepository {
+	return &PaymentRequestRepository{conn: uow.Conn()}
+}
@@ -0,0 +1,25 @@
+package paymentrequest
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// IService is an interface that defines all the service methods of a payment request struct
+type IService interface {
+	AddPaymentRequest(newRequest *entity.PaymentRequest) error
+	FindPaymentRequest(identifier int64) (*entity.PaymentRequest, error)
+	LockPaymentRequest(identifier int64) (*entity.PaymentRequest, error)
+	SearchPaymentRequests(userID, role string, statuses ...string) []*entity.PaymentRequest
+	UpdatePaymentRequest(request *entity.PaymentRequest) error
+	RemindPaymentRequest(request *entity.PaymentRequest)
+
+	AddPaymentGroup(newGroup *entity.PaymentGroup) error
+	FindPaymentGroup(identifier int64) (*entity.PaymentGroup, error)
+	LockPaymentGroup(identifier int64) (*entity.PaymentGroup, error)
+	SearchPaymentGroups(initiatorID string) []*entity.PaymentGroup
+	GroupPaymentRequests(groupID int64) []*entity.PaymentRequest
+	UpdatePaymentGroup(group *entity.PaymentGroup) error
+
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IService
+}
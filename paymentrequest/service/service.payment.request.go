@@ -0,0 +1,198 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/notifier"
+	"github.com/Benyam-S/onepay/paymentrequest"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
+
+// Service is a type that defines payment request service
+type Service struct {
+	requestRepo paymentrequest.IPaymentRequestRepository
+	groupRepo   paymentrequest.IPaymentGroupRepository
+	notifier    *notifier.Notifier
+	uow         *unitofwork.UnitOfWork
+}
+
+// NewPaymentRequestService is a function that returns a new payment request service
+func NewPaymentRequestService(requestRepository paymentrequest.IPaymentRequestRepository,
+	groupRepository paymentrequest.IPaymentGroupRepository, requestChangeNotifier *notifier.Notifier) paymentrequest.IService {
+	return &Service{requestRepo: requestRepository, groupRepo: groupRepository, notifier: requestChangeNotifier}
+}
+
+// AddPaymentRequest is a method that adds a new payment request to the system and notifies the payer
+func (service *Service) AddPaymentRequest(newRequest *entity.PaymentRequest) error {
+
+	if !newRequest.Amount.IsPositive() {
+		return errors.New(entity.AmountParsingError)
+	}
+
+	if !newRequest.ExpiresAt.After(time.Now()) {
+		return errors.New(entity.ExpiredPaymentRequestError)
+	}
+
+	newRequest.Note = strings.TrimSpace(newRequest.Note)
+	newRequest.Status = entity.PaymentRequestStatusPending
+
+	err := service.requestRepo.Create(newRequest)
+	if err != nil {
+		return errors.New("unable to add new payment request")
+	}
+
+	/* ++++++++++++++ NOTIFYING CHANGE +++++++++++++++ */
+	service.notify(func() { service.notifier.NotifyPaymentRequestChange(newRequest) })
+	/* +++++++++++++++++++++++++++++++++++++++++++++++ */
+
+	return nil
+}
+
+// FindPaymentRequest is a method that finds a certain payment request using the identifier
+func (service *Service) FindPaymentRequest(identifier int64) (*entity.PaymentRequest, error) {
+
+	request, err := service.requestRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("payment request not found")
+	}
+	return request, nil
+}
+
+// LockPaymentRequest is a method that finds a certain payment request using the identifier and locks it for the rest of the unit of work
+func (service *Service) LockPaymentRequest(identifier int64) (*entity.PaymentRequest, error) {
+
+	request, err := service.requestRepo.FindForUpdate(identifier)
+	if err != nil {
+		return nil, errors.New("payment request not found")
+	}
+	return request, nil
+}
+
+// SearchPaymentRequests is a method that returns the payment requests a certain user has made or received with one of the provided statuses.
+// Pending requests whose expiry has passed are marked as expired first so they are listed with the right status.
+func (service *Service) SearchPaymentRequests(userID, role string, statuses ...string) []*entity.PaymentRequest {
+
+	empty, _ := regexp.MatchString(`^\s*$`, userID)
+	if empty {
+		return []*entity.PaymentRequest{}
+	}
+
+	service.expire()
+	return service.requestRepo.Search(userID, role, statuses)
+}
+
+// UpdatePaymentRequest is a method that updates a certain payment request and notifies both of its parties
+func (service *Service) UpdatePaymentRequest(request *entity.PaymentRequest) error {
+
+	err := service.requestRepo.Update(request)
+	if err != nil {
+		return errors.New("unable to update payment request")
+	}
+
+	/* ++++++++++++++ NOTIFYING CHANGE +++++++++++++++ */
+	service.notify(func() { service.notifier.NotifyPaymentRequestChange(request) })
+	/* +++++++++++++++++++++++++++++++++++++++++++++++ */
+
+	return nil
+}
+
+// RemindPaymentRequest is a method that notifies the payer of a payment request again without changing it
+func (service *Service) RemindPaymentRequest(request *entity.PaymentRequest) {
+	service.notify(func() { service.notifier.NotifyPaymentRequestChange(request) })
+}
+
+// AddPaymentGroup is a method that adds a new payment group to the system
+func (service *Service) AddPaymentGroup(newGroup *entity.PaymentGroup) error {
+
+	if !newGroup.Total.IsPositive() {
+		return errors.New(entity.AmountParsingError)
+	}
+
+	newGroup.Description = strings.TrimSpace(newGroup.Description)
+	newGroup.Status = entity.PaymentGroupStatusOpen
+
+	err := service.groupRepo.Create(newGroup)
+	if err != nil {
+		return errors.New("unable to add new payment group")
+	}
+	return nil
+}
+
+// FindPaymentGroup is a method that finds a certain payment group using the identifier
+func (service *Service) FindPaymentGroup(identifier int64) (*entity.PaymentGroup, error) {
+
+	service.expire()
+	group, err := service.groupRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("payment group not found")
+	}
+	return group, nil
+}
+
+// LockPaymentGroup is a method that finds a certain payment group using the identifier and locks it for the rest of the unit of work
+func (service *Service) LockPaymentGroup(identifier int64) (*entity.PaymentGroup, error) {
+
+	service.expire()
+	group, err := service.groupRepo.FindForUpdate(identifier)
+	if err != nil {
+		return nil, errors.New("payment group not found")
+	}
+	return group, nil
+}
+
+// SearchPaymentGroups is a method that returns all the payment groups a certain user has initiated
+func (service *Service) SearchPaymentGroups(initiatorID string) []*entity.PaymentGroup {
+
+	empty, _ := regexp.MatchString(`^\s*$`, initiatorID)
+	if empty {
+		return []*entity.PaymentGroup{}
+	}
+
+	service.expire()
+	return service.groupRepo.Search(initiatorID)
+}
+
+// GroupPaymentRequests is a method that returns the payment requests of every participant of a certain payment group.
+// Pending requests whose expiry has passed are marked as expired first.
+func (service *Service) GroupPaymentRequests(groupID int64) []*entity.PaymentRequest {
+
+	service.expire()
+	return service.requestRepo.SearchByGroup(groupID)
+}
+
+// UpdatePaymentGroup is a method that updates a certain payment group
+func (service *Service) UpdatePaymentGroup(group *entity.PaymentGroup) error {
+
+	err := service.groupRepo.Update(group)
+	if err != nil {
+		return errors.New("unable to update payment group")
+	}
+	return nil
+}
+
+// expire is a method that marks the pending payment requests whose expiry has passed as expired
+// and closes the open payment groups that can't be completed anymore
+func (service *Service) expire() {
+	service.requestRepo.Expire(time.Now())
+	service.groupRepo.CloseIncomplete()
+}
+
+// WithUnitOfWork is a method that returns a payment request service whose changes are made inside the provided unit of work
+func (service *Service) WithUnitOfWork(uow *unitofwork.UnitOfWork) paymentrequest.IService {
+	return &Service{requestRepo: service.requestRepo.WithUnitOfWork(uow),
+		groupRepo: service.groupRepo.WithUnitOfWork(uow), notifier: service.notifier, uow: uow}
+}
+
+// notify is a method that runs the provided notification immediately or,
+// if the service is bound to a unit of work, once the unit of work has been committed
+func (service *Service) notify(f func()) {
+	if service.uow != nil {
+		service.uow.AfterCommit(f)
+		return
+	}
+	f()
+}
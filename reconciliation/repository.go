@@ -0,0 +1,19 @@
+package reconciliation
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// IReportRepository is an interface that defines all the repository methods of a reconciliation report struct
+type IReportRepository interface {
+	Create(newReport *entity.ReconciliationReport) error
+	Find(identifier int64) (*entity.ReconciliationReport, error)
+	Search(pageNum int64) ([]*entity.ReconciliationReport, int64)
+	Update(report *entity.ReconciliationReport) error
+}
+
+// IDiscrepancyRepository is an interface that defines all the repository methods of a wallet discrepancy struct
+type IDiscrepancyRepository interface {
+	Create(newDiscrepancy *entity.WalletDiscrepancy) error
+	Search(reportID int64) []*entity.WalletDiscrepancy
+}
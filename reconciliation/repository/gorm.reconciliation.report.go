@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"math"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/reconciliation"
+	"github.com/jinzhu/gorm"
+)
+
+// ReportRepository is a type that defines a reconciliation report repository
+type ReportRepository struct {
+	conn *gorm.DB
+}
+
+// NewReportRepository is a function that returns a new reconciliation report repository
+func NewReportRepository(connection *gorm.DB) reconciliation.IReportRepository {
+	return &ReportRepository{conn: connection}
+}
+
+// Create is a method that adds a new reconciliation report to the database
+func (repo *ReportRepository) Create(newReport *entity.ReconciliationReport) error {
+
+	err := repo.conn.Create(newReport).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds a certain reconciliation report from the database using an identifier.
+// In Find() id is only used as a key
+func (repo *ReportRepository) Find(identifier int64) (*entity.ReconciliationReport, error) {
+	report := new(entity.ReconciliationReport)
+	err := repo.conn.Model(report).
+		Where("id = ?", identifier).First(report).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Search is a method that returns a page of the reconciliation reports, the latest first, along with the number of pages
+func (repo *ReportRepository) Search(pageNum int64) ([]*entity.ReconciliationReport, int64) {
+
+	var reports []*entity.ReconciliationReport
+	var count float64
+
+	repo.conn.Model(entity.ReconciliationReport{}).Count(&count)
+	err := repo.conn.Model(entity.ReconciliationReport{}).
+		Order("id DESC").Limit(10).Offset(pageNum * 10).Find(&reports).Error
+
+	if err != nil {
+		return []*entity.ReconciliationReport{}, 0
+	}
+
+	var pageCount int64 = int64(math.Ceil(count / 10.0))
+	return reports, pageCount
+}
+
+// Update is a method that updates a certain reconciliation report value in the database
+func (repo *ReportRepository) Update(report *entity.ReconciliationReport) error {
+
+	prevReport := new(entity.ReconciliationReport)
+	err := repo.conn.Model(prevReport).Where("id = ?", report.ID).First(prevReport).Error
+
+	if err != nil {
+		return err
+	}
+
+	err = repo.conn.Save(report).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/reconciliation"
+	"github.com/jinzhu/gorm"
+)
+
+// DiscrepancyRepository is a type that defines a wallet discrepancy repository
+type DiscrepancyRepository struct {
+	conn *gorm.DB
+}
+
+// NewDiscrepancyRepository is a function that returns a new wallet discrepancy repository
+func NewDiscrepancyRepository(connection *gorm.DB) reconciliation.IDiscrepancyRepository {
+	return &DiscrepancyRepository{conn: connection}
+}
+
+// Create is a method that adds a new wallet discrepancy to the database
+func (repo *DiscrepancyRepository) Create(newDiscrepancy *entity.WalletDiscrepancy) error {
+
+	err := repo.conn.Create(newDiscrepancy).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Search is a method that returns all the wallet discrepancies found by a certain reconciliation report
+func (repo *DiscrepancyRepository) Search(reportID int64) []*entity.WalletDiscrepancy {
+	var discrepancies []*entity.WalletDiscrepancy
+	err := repo.conn.Model(entity.WalletDiscrepancy{}).
+		Where("report_id = ?", reportID).
+		Order("id").Find(&discrepancies).Error
+
+	if err != nil {
+		return []*entity.WalletDiscrepancy{}
+	}
+	return discrepancies
+}
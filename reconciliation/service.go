@@ -0,0 +1,16 @@
+package reconciliation
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// IService is an interface that defines all the service methods of a reconciliation report struct
+type IService interface {
+	AddReport(newReport *entity.ReconciliationReport) error
+	FindReport(identifier int64) (*entity.ReconciliationReport, error)
+	SearchReports(pageNum int64) ([]*entity.ReconciliationReport, int64)
+	UpdateReport(report *entity.ReconciliationReport) error
+
+	AddDiscrepancy(newDiscrepancy *entity.WalletDiscrepancy) error
+	ReportDiscrepancies(reportID int64) []*entity.WalletDiscrepancy
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/reconciliation"
+)
+
+// Service is a type that defines reconciliation service
+type Service struct {
+	reportRepo      reconciliation.IReportRepository
+	discrepancyRepo reconciliation.IDiscrepancyRepository
+}
+
+// NewReconciliationService is a function that returns a new reconciliation service
+func NewReconciliationService(reportRepository reconciliation.IReportRepository,
+	discrepancyRepository reconciliation.IDiscrepancyRepository) reconciliation.IService {
+	return &Service{reportRepo: reportRepository, discrepancyRepo: discrepancyRepository}
+}
+
+// AddReport is a method that adds a new reconciliation report to the system
+func (service *Service) AddReport(newReport *entity.ReconciliationReport) error {
+
+	err := service.reportRepo.Create(newReport)
+	if err != nil {
+		return errors.New("unable to add new reconciliation report")
+	}
+	return nil
+}
+
+// FindReport is a method that finds a certain reconciliation report using the identifier
+func (service *Service) FindReport(identifier int64) (*entity.ReconciliationReport, error) {
+
+	report, err := service.reportRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("reconciliation report not found")
+	}
+	return report, nil
+}
+
+// SearchReports is a method that returns a page of the reconciliation reports along with the number of pages
+func (service *Service) SearchReports(pageNum int64) ([]*entity.ReconciliationReport, int64) {
+
+	if pageNum < 0 {
+		pageNum = 0
+	}
+
+	return service.reportRepo.Search(pageNum)
+}
+
+// UpdateReport is a method that updates a certain reconciliation report
+func (service *Service) UpdateReport(report *entity.ReconciliationReport) error {
+
+	err := service.reportRepo.Update(report)
+	if err != nil {
+		return errors.New("unable to update reconciliation report")
+	}
+	return nil
+}
+
+// AddDiscrepancy is a method that adds a new wallet discrepancy to a reconciliation report
+func (service *Service) AddDiscrepancy(newDiscrepancy *entity.WalletDiscrepancy) error {
+
+	err := service.discrepancyRepo.Create(newDiscrepancy)
+	if err != nil {
+		return errors.New("unable to add new wallet discrepancy")
+	}
+	return nil
+}
+
+// ReportDiscrepancies is a method that returns all the wallet discrepancies found by a certain reconciliation report
+func (service *Service) ReportDiscrepancies(reportID int64) []*entity.WalletDiscrepancy {
+	return service.discrepancyRepo.Search(reportID)
+}
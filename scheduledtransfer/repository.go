@@ -0,0 +1,20 @@
+package scheduledtransfer
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// IScheduledTransferRepository is an interface that defines all the repository methods of a scheduled transfer struct
+type IScheduledTransferRepository interface {
+	Create(newTransfer *entity.ScheduledTransfer) error
+	Find(identifier int64) (*entity.ScheduledTransfer, error)
+	Search(senderID string) []*entity.ScheduledTransfer
+	Due(now time.Time) []*entity.ScheduledTransfer
+	Claim(transfer *entity.ScheduledTransfer) (bool, error)
+	Requeue(claimedBefore time.Time) error
+	Cancel(identifier int64, senderID string) (bool, error)
+	Update(transfer *entity.ScheduledTransfer) error
+	UpdateUnlessCancelled(transfer *entity.ScheduledTransfer) (bool, error)
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/scheduledtransfer"
+	"github.com/jinzhu/gorm"
+)
+
+// ScheduledTransferRepository is a type that defines a scheduled transfer repository
+type ScheduledTransferRepository struct {
+	conn *gorm.DB
+}
+
+// NewScheduledTransferRepository is a function that returns a new scheduled transfer repository
+func NewScheduledTransferRepository(connection *gorm.DB) scheduledtransfer.IScheduledTransferRepository {
+	return &ScheduledTransferRepository{conn: connection}
+}
+
+// Create is a method that adds a new scheduled transfer to the database
+func (repo *ScheduledTransferRepository) Create(newTransfer *entity.ScheduledTransfer) error {
+
+	err := repo.conn.Create(newTransfer).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds a certain scheduled transfer from the database using an identifier.
+// In Find() id is only used as a key
+func (repo *ScheduledTransferRepository) Find(identifier int64) (*entity.ScheduledTransfer, error) {
+	transfer := new(entity.ScheduledTransfer)
+	err := repo.conn.Model(transfer).
+		Where("id = ?", identifier).First(transfer).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// Search is a method that returns all the scheduled transfers of a certain sender
+func (repo *ScheduledTransferRepository) Search(senderID string) []*entity.ScheduledTransfer {
+	var transfers []*entity.ScheduledTransfer
+	err := repo.conn.Model(entity.ScheduledTransfer{}).
+		Where("sender_id = ?", senderID).
+		Order("id DESC").Find(&transfers).Error
+
+	if err != nil {
+		return []*entity.ScheduledTransfer{}
+	}
+	return transfers
+}
+
+// Due is a method that returns all the active scheduled transfers whose next run time has been reached
+func (repo *ScheduledTransferRepository) Due(now time.Time) []*entity.ScheduledTransfer {
+	var transfers []*entity.ScheduledTransfer
+	err := repo.conn.Model(entity.ScheduledTransfer{}).
+		Where("status = ? AND next_run_at <= ?", entity.ScheduledTransferStatusActive, now).
+		Order("next_run_at").Find(&transfers).Error
+
+	if err != nil {
+		return []*entity.ScheduledTransfer{}
+	}
+	return transfers
+}
+
+// Claim is a method that marks an active scheduled transfer as running, only if it hasn't been changed since it was read.
+// It returns false if another scheduler has already claimed or changed the scheduled transfer.
+func (repo *ScheduledTransferRepository) Claim(transfer *entity.ScheduledTransfer) (bool, error) {
+
+	claimedAt := time.Now()
+	result := repo.conn.Model(entity.ScheduledTransfer{}).
+		Where("id = ? AND status = ? AND next_run_at = ?", transfer.ID,
+			entity.ScheduledTransferStatusActive, transfer.NextRunAt).
+		Updates(map[string]interface{}{"status": entity.ScheduledTransferStatusRunning, "claimed_at": claimedAt})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	transfer.Status = entity.ScheduledTransferStatusRunning
+	transfer.ClaimedAt = &claimedAt
+	return true, nil
+}
+
+// Requeue is a method that makes the scheduled transfers that have been running since before the provided time active again,
+// so a run that has been interrupted is retried. The interrupted run is counted as a failed attempt.
+func (repo *ScheduledTransferRepository) Requeue(claimedBefore time.Time) error {
+
+	return repo.conn.Model(entity.ScheduledTransfer{}).
+		Where("status = ? AND claimed_at < ?", entity.ScheduledTransferStatusRunning, claimedBefore).
+		Updates(map[string]interface{}{
+			"status":     entity.ScheduledTransferStatusActive,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": entity.ScheduledTransferInterruptedError,
+			"claimed_at": nil,
+		}).Error
+}
+
+// Cancel is a method that cancels a certain sender's scheduled transfer, only if it is still active or running.
+// It returns false if the scheduled transfer can't be found or has already been closed.
+func (repo *ScheduledTransferRepository) Cancel(identifier int64, senderID string) (bool, error) {
+
+	result := repo.conn.Model(entity.ScheduledTransfer{}).
+		Where("id = ? AND sender_id = ? AND status IN (?)", identifier, senderID,
+			[]string{entity.ScheduledTransferStatusActive, entity.ScheduledTransferStatusRunning}).
+		Update("status", entity.ScheduledTransferStatusCancelled)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Update is a method that updates a certain scheduled transfer value in the database
+func (repo *ScheduledTransferRepository) Update(transfer *entity.ScheduledTransfer) error {
+
+	prevTransfer := new(entity.ScheduledTransfer)
+	err := repo.conn.Model(prevTransfer).Where("id = ?", transfer.ID).First(prevTransfer).Error
+
+	if err != nil {
+		return err
+	}
+
+	err = repo.conn.Save(transfer).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateUnlessCancelled is a method that updates the run values of a certain scheduled transfer in the database,
+// only if it hasn't been cancelled. It returns false if the scheduled transfer has been cancelled in the meantime.
+func (repo *ScheduledTransferRepository) UpdateUnlessCancelled(transfer *entity.ScheduledTransfer) (bool, error) {
+
+	result := repo.conn.Model(entity.ScheduledTransfer{}).
+		Where("id = ? AND status <> ?", transfer.ID, entity.ScheduledTransferStatusCancelled).
+		Updates(map[string]interface{}{
+			"occurrences":  transfer.Occurrences,
+			"scheduled_at": transfer.ScheduledAt,
+			"next_run_at":  transfer.NextRunAt,
+			"attempts":     transfer.Attempts,
+			"status":       transfer.Status,
+			"last_error":   transfer.LastError,
+			"last_run_at":  transfer.LastRunAt,
+			"claimed_at":   nil,
+		})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,20 @@
+package scheduledtransfer
+
+import (
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// IService is an interface that defines all the service methods of a scheduled transfer struct
+type IService interface {
+	AddScheduledTransfer(newTransfer *entity.ScheduledTransfer) error
+	FindScheduledTransfer(identifier int64) (*entity.ScheduledTransfer, error)
+	SearchScheduledTransfers(senderID string) []*entity.ScheduledTransfer
+	DueScheduledTransfers(now time.Time) []*entity.ScheduledTransfer
+	ClaimScheduledTransfer(transfer *entity.ScheduledTransfer) bool
+	RequeueScheduledTransfers(claimedBefore time.Time) error
+	CancelScheduledTransfer(identifier int64, senderID string) (*entity.ScheduledTransfer, error)
+	UpdateScheduledTransfer(transfer *entity.ScheduledTransfer) error
+	UpdateScheduledTransferUnlessCancelled(transfer *entity.ScheduledTransfer) (bool, error)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/notifier"
+	"github.com/Benyam-S/onepay/scheduledtransfer"
+)
+
+// Service is a type that defines scheduled transfer service
+type Service struct {
+	transferRepo scheduledtransfer.IScheduledTransferRepository
+	notifier     *notifier.Notifier
+}
+
+// NewScheduledTransferService is a function that returns a new scheduled transfer service
+func NewScheduledTransferService(transferRepository scheduledtransfer.IScheduledTransferRepository,
+	transferChangeNotifier *notifier.Notifier) scheduledtransfer.IService {
+	return &Service{transferRepo: transferRepository, notifier: transferChangeNotifier}
+}
+
+// AddScheduledTransfer is a method that adds a new scheduled transfer to the system
+func (service *Service) AddScheduledTransfer(newTransfer *entity.ScheduledTransfer) error {
+
+	switch newTransfer.Frequency {
+	case entity.FrequencyOnce, entity.FrequencyDaily, entity.FrequencyWeekly, entity.FrequencyMonthly:
+	default:
+		return errors.New(entity.InvalidFrequencyError)
+	}
+
+	if !newTransfer.Amount.IsPositive() {
+		return errors.New(entity.AmountParsingError)
+	}
+
+	newTransfer.Status = entity.ScheduledTransferStatusActive
+	newTransfer.ScheduledAt = newTransfer.StartAt
+	newTransfer.NextRunAt = newTransfer.StartAt
+
+	err := service.transferRepo.Create(newTransfer)
+	if err != nil {
+		return errors.New("unable to add new scheduled transfer")
+	}
+	return nil
+}
+
+// FindScheduledTransfer is a method that finds a certain scheduled transfer using the identifier
+func (service *Service) FindScheduledTransfer(identifier int64) (*entity.ScheduledTransfer, error) {
+
+	transfer, err := service.transferRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("scheduled transfer not found")
+	}
+	return transfer, nil
+}
+
+// SearchScheduledTransfers is a method that returns all the scheduled transfers of a certain sender
+func (service *Service) SearchScheduledTransfers(senderID string) []*entity.ScheduledTransfer {
+
+	empty, _ := regexp.MatchString(`^\s*$`, senderID)
+	if empty {
+		return []*entity.ScheduledTransfer{}
+	}
+
+	return service.transferRepo.Search(senderID)
+}
+
+// DueScheduledTransfers is a method that returns all the scheduled transfers that should be run by now
+func (service *Service) DueScheduledTransfers(now time.Time) []*entity.ScheduledTransfer {
+	return service.transferRepo.Due(now)
+}
+
+// ClaimScheduledTransfer is a method that reserves a due scheduled transfer for the caller so it is run only once
+func (service *Service) ClaimScheduledTransfer(transfer *entity.ScheduledTransfer) bool {
+
+	claimed, err := service.transferRepo.Claim(transfer)
+	if err != nil {
+		return false
+	}
+	return claimed
+}
+
+// RequeueScheduledTransfers is a method that makes the scheduled transfers whose run has been claimed before the provided time
+// and never finished active again, so they are retried
+func (service *Service) RequeueScheduledTransfers(claimedBefore time.Time) error {
+
+	err := service.transferRepo.Requeue(claimedBefore)
+	if err != nil {
+		return errors.New("unable to requeue scheduled transfers")
+	}
+	return nil
+}
+
+// CancelScheduledTransfer is a method that cancels a certain sender's active or running scheduled transfer and notifies the sender
+func (service *Service) CancelScheduledTransfer(identifier int64, senderID string) (*entity.ScheduledTransfer, error) {
+
+	cancelled, err := service.transferRepo.Cancel(identifier, senderID)
+	if err != nil {
+		return nil, errors.New("unable to cancel scheduled transfer")
+	}
+
+	transfer, err := service.transferRepo.Find(identifier)
+	if err != nil || transfer.SenderID != senderID {
+		return nil, errors.New("scheduled transfer not found")
+	}
+
+	if !cancelled {
+		return nil, errors.New("scheduled transfer is not active")
+	}
+
+	/* ++++++++++++++ NOTIFYING CHANGE +++++++++++++++ */
+	service.notifier.NotifyScheduledTransferChange(transfer)
+	/* +++++++++++++++++++++++++++++++++++++++++++++++ */
+
+	return transfer, nil
+}
+
+// UpdateScheduledTransfer is a method that updates a certain scheduled transfer and notifies its sender
+func (service *Service) UpdateScheduledTransfer(transfer *entity.ScheduledTransfer) error {
+
+	err := service.transferRepo.Update(transfer)
+	if err != nil {
+		return errors.New("unable to update scheduled transfer")
+	}
+
+	/* ++++++++++++++ NOTIFYING CHANGE +++++++++++++++ */
+	service.notifier.NotifyScheduledTransferChange(transfer)
+	/* +++++++++++++++++++++++++++++++++++++++++++++++ */
+
+	return nil
+}
+
+// UpdateScheduledTransferUnlessCancelled is a method that updates a certain scheduled transfer and notifies its sender,
+// only if the scheduled transfer hasn't been cancelled. It returns false if the scheduled transfer has been cancelled.
+func (service *Service) UpdateScheduledTransferUnlessCancelled(transfer *entity.ScheduledTransfer) (bool, error) {
+
+	updated, err := service.transferRepo.UpdateUnlessCancelled(transfer)
+	if err != nil {
+		return false, errors.New("unable to update scheduled transfer")
+	}
+
+	if !updated {
+		return false, nil
+	}
+
+	/* ++++++++++++++ NOTIFYING CHANGE +++++++++++++++ */
+	service.notifier.NotifyScheduledTransferChange(transfer)
+	/* +++++++++++++++++++++++++++++++++++++++++++++++ */
+
+	return true, nil
+}
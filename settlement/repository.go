@@ -0,0 +1,23 @@
+package settlement
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// IStatementRepository is an interface that defines all the repository methods of a settlement statement struct
+type IStatementRepository interface {
+	Create(newStatement *entity.SettlementStatement) error
+	Find(identifier int64) (*entity.SettlementStatement, error)
+	Search(accountProviderID string, pageNum int64) ([]*entity.SettlementStatement, int64)
+	Update(statement *entity.SettlementStatement) error
+}
+
+// IItemRepository is an interface that defines all the repository methods of a settlement item struct
+type IItemRepository interface {
+	Create(newItem *entity.SettlementItem) error
+	Find(identifier int64) (*entity.SettlementItem, error)
+	Search(statementID int64) []*entity.SettlementItem
+	SearchByStatus(statuses []string) []*entity.SettlementItem
+	SearchByHistory(historyID int64) []*entity.SettlementItem
+	Update(item *entity.SettlementItem) error
+}
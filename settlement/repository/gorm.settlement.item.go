@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/settlement"
+	"github.com/jinzhu/gorm"
+)
+
+// ItemRepository is a type that defines a settlement item repository
+type ItemRepository struct {
+	conn *gorm.DB
+}
+
+// NewItemRepository is a function that returns a new settlement item repository
+func NewItemRepository(connection *gorm.DB) settlement.IItemRepository {
+	return &ItemRepository{conn: connection}
+}
+
+// Create is a method that adds a new settlement item to the database
+func (repo *ItemRepository) Create(newItem *entity.SettlementItem) error {
+
+	err := repo.conn.Create(newItem).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds a certain settlement item from the database using an identifier.
+// In Find() id is only used as a key
+func (repo *ItemRepository) Find(identifier int64) (*entity.SettlementItem, error) {
+	item := new(entity.SettlementItem)
+	err := repo.conn.Model(item).
+		Where("id = ?", identifier).First(item).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Search is a method that returns all the items of a certain settlement statement
+func (repo *ItemRepository) Search(statementID int64) []*entity.SettlementItem {
+	var items []*entity.SettlementItem
+	err := repo.conn.Model(entity.SettlementItem{}).
+		Where("statement_id = ?", statementID).
+		Order("id").Find(&items).Error
+
+	if err != nil {
+		return []*entity.SettlementItem{}
+	}
+	return items
+}
+
+// SearchByStatus is a method that returns all the settlement items that have one of the provided statuses, the oldest first
+func (repo *ItemRepository) SearchByStatus(statuses []string) []*entity.SettlementItem {
+	var items []*entity.SettlementItem
+	err := repo.conn.Model(entity.SettlementItem{}).
+		Where("status IN (?)", statuses).
+		Order("id").Find(&items).Error
+
+	if err != nil {
+		return []*entity.SettlementItem{}
+	}
+	return items
+}
+
+// SearchByHistory is a method that returns all the settlement items that have been matched with a certain history
+func (repo *ItemRepository) SearchByHistory(historyID int64) []*entity.SettlementItem {
+	var items []*entity.SettlementItem
+	err := repo.conn.Model(entity.SettlementItem{}).
+		Where("history_id = ?", historyID).
+		Order("id").Find(&items).Error
+
+	if err != nil {
+		return []*entity.SettlementItem{}
+	}
+	return items
+}
+
+// Update is a method that updates a certain settlement item value in the database
+func (repo *ItemRepository) Update(item *entity.SettlementItem) error {
+
+	prevItem := new(entity.SettlementItem)
+	err := repo.conn.Model(prevItem).Where("id = ?", item.ID).First(prevItem).Error
+
+	if err != nil {
+		return err
+	}
+
+	err = repo.conn.Save(item).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
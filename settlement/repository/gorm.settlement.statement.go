@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"math"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/settlement"
+	"github.com/jinzhu/gorm"
+)
+
+// StatementRepository is a type that defines a settlement statement repository
+type StatementRepository struct {
+	conn *gorm.DB
+}
+
+// NewStatementRepository is a function that returns a new settlement statement repository
+func NewStatementRepository(connection *gorm.DB) settlement.IStatementRepository {
+	return &StatementRepository{conn: connection}
+}
+
+// Create is a method that adds a new settlement statement to the database
+func (repo *StatementRepository) Create(newStatement *entity.SettlementStatement) error {
+
+	err := repo.conn.Create(newStatement).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find is a method that finds a certain settlement statement from the database using an identifier.
+// In Find() id is only used as a key
+func (repo *StatementRepository) Find(identifier int64) (*entity.SettlementStatement, error) {
+	statement := new(entity.SettlementStatement)
+	err := repo.conn.Model(statement).
+		Where("id = ?", identifier).First(statement).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// Search is a method that returns a page of the settlement statements, the latest first, along with the number of pages.
+// If an account provider id is provided only the statements of that account provider are returned.
+func (repo *StatementRepository) Search(accountProviderID string, pageNum int64) ([]*entity.SettlementStatement, int64) {
+
+	var statements []*entity.SettlementStatement
+	var count float64
+
+	query := repo.conn.Model(entity.SettlementStatement{})
+	if accountProviderID != "" {
+		query = query.Where("account_provider_id = ?", accountProviderID)
+	}
+
+	query.Count(&count)
+	err := query.Order("id DESC").Limit(10).Offset(pageNum * 10).Find(&statements).Error
+
+	if err != nil {
+		return []*entity.SettlementStatement{}, 0
+	}
+
+	var pageCount int64 = int64(math.Ceil(count / 10.0))
+	return statements, pageCount
+}
+
+// Update is a method that updates a certain settlement statement value in the database
+func (repo *StatementRepository) Update(statement *entity.SettlementStatement) error {
+
+	prevStatement := new(entity.SettlementStatement)
+	err := repo.conn.Model(prevStatement).Where("id = ?", statement.ID).First(prevStatement).Error
+
+	if err != nil {
+		return err
+	}
+
+	err = repo.conn.Save(statement).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
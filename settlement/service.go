@@ -0,0 +1,20 @@
+package settlement
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// IService is an interface that defines all the service methods of a settlement statement struct
+type IService interface {
+	AddStatement(newStatement *entity.SettlementStatement) error
+	FindStatement(identifier int64) (*entity.SettlementStatement, error)
+	SearchStatements(accountProviderID string, pageNum int64) ([]*entity.SettlementStatement, int64)
+	UpdateStatement(statement *entity.SettlementStatement) error
+
+	AddItem(newItem *entity.SettlementItem) error
+	FindItem(identifier int64) (*entity.SettlementItem, error)
+	StatementItems(statementID int64) []*entity.SettlementItem
+	ItemsByStatus(statuses ...string) []*entity.SettlementItem
+	HistoryItems(historyID int64) []*entity.SettlementItem
+	UpdateItem(item *entity.SettlementItem) error
+}
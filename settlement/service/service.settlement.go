@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/settlement"
+)
+
+// Service is a type that defines settlement service
+type Service struct {
+	statementRepo settlement.IStatementRepository
+	itemRepo      settlement.IItemRepository
+}
+
+// NewSettlementService is a function that returns a new settlement service
+func NewSettlementService(statementRepository settlement.IStatementRepository,
+	itemRepository settlement.IItemRepository) settlement.IService {
+	return &Service{statementRepo: statementRepository, itemRepo: itemRepository}
+}
+
+// AddStatement is a method that adds a new settlement statement to the system
+func (service *Service) AddStatement(newStatement *entity.SettlementStatement) error {
+
+	if newStatement.Format != entity.SettlementFormatCSV && newStatement.Format != entity.SettlementFormatFixedWidth {
+		return errors.New(entity.InvalidSettlementFormatError)
+	}
+
+	newStatement.FileName = strings.TrimSpace(newStatement.FileName)
+
+	err := service.statementRepo.Create(newStatement)
+	if err != nil {
+		return errors.New("unable to add new settlement statement")
+	}
+	return nil
+}
+
+// FindStatement is a method that finds a certain settlement statement using the identifier
+func (service *Service) FindStatement(identifier int64) (*entity.SettlementStatement, error) {
+
+	statement, err := service.statementRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("settlement statement not found")
+	}
+	return statement, nil
+}
+
+// SearchStatements is a method that returns a page of the settlement statements along with the number of pages
+func (service *Service) SearchStatements(accountProviderID string, pageNum int64) ([]*entity.SettlementStatement, int64) {
+
+	if pageNum < 0 {
+		pageNum = 0
+	}
+
+	return service.statementRepo.Search(strings.TrimSpace(accountProviderID), pageNum)
+}
+
+// UpdateStatement is a method that updates a certain settlement statement
+func (service *Service) UpdateStatement(statement *entity.SettlementStatement) error {
+
+	err := service.statementRepo.Update(statement)
+	if err != nil {
+		return errors.New("unable to update settlement statement")
+	}
+	return nil
+}
+
+// AddItem is a method that adds a new settlement item to the system
+func (service *Service) AddItem(newItem *entity.SettlementItem) error {
+
+	err := service.itemRepo.Create(newItem)
+	if err != nil {
+		return errors.New("unable to add new settlement item")
+	}
+	return nil
+}
+
+// FindItem is a method that finds a certain settlement item using the identifier
+func (service *Service) FindItem(identifier int64) (*entity.SettlementItem, error) {
+
+	item, err := service.itemRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("settlement item not found")
+	}
+	return item, nil
+}
+
+// StatementItems is a method that returns all the items of a certain settlement statement
+func (service *Service) StatementItems(statementID int64) []*entity.SettlementItem {
+	return service.itemRepo.Search(statementID)
+}
+
+// ItemsByStatus is a method that returns all the settlement items that have one of the provided statuses
+func (service *Service) ItemsByStatus(statuses ...string) []*entity.SettlementItem {
+
+	if len(statuses) == 0 {
+		return []*entity.SettlementItem{}
+	}
+
+	return service.itemRepo.SearchByStatus(statuses)
+}
+
+// HistoryItems is a method that returns all the settlement items that have been matched with a certain history
+func (service *Service) HistoryItems(historyID int64) []*entity.SettlementItem {
+	return service.itemRepo.SearchByHistory(historyID)
+}
+
+// UpdateItem is a method that updates a certain settlement item
+func (service *Service) UpdateItem(item *entity.SettlementItem) error {
+
+	err := service.itemRepo.Update(item)
+	if err != nil {
+		return errors.New("unable to update settlement item")
+	}
+	return nil
+}
@@ -1,19 +1,29 @@
 package tools
 
-import "strings"
+import (
+	"errors"
+	"strings"
+
+	"github.com/Benyam-S/onepay/entity"
+)
 
 // IsValidBaseCurrency is a function that check whether the provided base currency is valid or not
 func IsValidBaseCurrency(base string) bool {
+	return entity.IsSupportedCurrency(base)
+}
 
-	listOfValidBaseCurrencies := []string{"ETB"}
-	isValid := false
+// ParseCurrency is a function that validates the currency provided in a request.
+// An empty currency means the base currency.
+func ParseCurrency(currency string) (string, error) {
+
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return entity.BaseCurrency, nil
+	}
 
-	for _, validBaseCurrency := range listOfValidBaseCurrencies {
-		if validBaseCurrency == strings.ToUpper(base) {
-			isValid = true
-			break
-		}
+	if !entity.IsSupportedCurrency(currency) {
+		return "", errors.New(entity.UnsupportedCurrencyError)
 	}
 
-	return isValid
+	return currency, nil
 }
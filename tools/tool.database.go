@@ -18,6 +18,12 @@ func SetValue(redisClient *redis.Client, key string, value string, expiry time.D
 	return nil
 }
 
+// SetValueIfAbsent is a function that adds a key value pair to a redis database only if the key doesn't exist yet.
+// It returns false if the key already exists.
+func SetValueIfAbsent(redisClient *redis.Client, key string, value string, expiry time.Duration) (bool, error) {
+	return redisClient.SetNX(key, value, expiry).Result()
+}
+
 // GetValue is a function that searchs for a value of a provided key on a redis database
 func GetValue(redisClient *redis.Client, key string) (string, error) {
 	// should refine and analyze the key
@@ -62,3 +68,12 @@ func AnalyzeKeyValuePair(redisClient *redis.Client, key, value string) error {
 
 	return nil
 }
+
+// releaseScript is a redis script that removes a key only if it still holds the provided value
+var releaseScript = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`)
+
+// RemoveValueIfEqual is a function that removes a key value pair from a redis database only if the key still holds the provided value.
+// It is used for releasing locks, so a lock that has expired and been taken by someone else isn't released.
+func RemoveValueIfEqual(redisClient *redis.Client, key, value string) error {
+	return releaseScript.Run(redisClient, []string{key}, value).Err()
+}
@@ -0,0 +1,248 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// An EMVCo merchant presented qr code is a list of data objects, each made of a two digit id, a two digit length and its value.
+// The data objects used by OnePay are
+//
+//	00   payload format indicator, always '01'
+//	01   point of initiation method, '11' for a static and '12' for a dynamic qr code
+//	26   merchant account information, a template holding OnePay's identifier in '00' and the merchant's OnePay id in '01'
+//	52   merchant category code
+//	53   ISO 4217 numeric code of the transaction currency
+//	54   transaction amount, only in dynamic qr codes
+//	58   country code
+//	59   merchant name
+//	60   merchant city
+//	62   additional data, a template holding the payment token code as its reference label in '05'
+//	63   CRC-16/CCITT-FALSE checksum of the whole payload up to and including '6304'
+//
+// Merchant account information of other schemes may use any id from 02 to 51, only OnePay's template is read when decoding.
+
+const (
+	emvPayloadFormatIndicator = "00"
+	emvPointOfInitiation      = "01"
+	emvMerchantAccount        = "26"
+	emvMerchantCategoryCode   = "52"
+	emvCurrency               = "53"
+	emvAmount                 = "54"
+	emvCountryCode            = "58"
+	emvMerchantName           = "59"
+	emvMerchantCity           = "60"
+	emvAdditionalData         = "62"
+	emvCRC                    = "63"
+
+	emvMerchantAccountGUI = "00"
+	emvMerchantAccountID  = "01"
+	emvReferenceLabel     = "05"
+
+	emvMerchantNameLength = 25
+	emvMerchantCityLength = 15
+)
+
+// IsEMVQRPayload is a function that checks whether a scanned qr code payload is an EMVCo merchant presented qr code
+func IsEMVQRPayload(payload string) bool {
+	return strings.HasPrefix(strings.TrimSpace(payload), emvPayloadFormatIndicator+"0201")
+}
+
+// EncodeEMVQRCode is a function that encodes an EMVCo merchant presented qr code payload.
+// The amount and reference label are only encoded for dynamic qr codes.
+func EncodeEMVQRCode(emvQRCode *entity.EMVQRCode) (string, error) {
+
+	currency, ok := entity.CurrencyNumericCode(emvQRCode.Amount.Currency)
+	if !ok {
+		return "", errors.New(entity.UnsupportedCurrencyError)
+	}
+
+	merchantAccount, err := emvDataObject(emvMerchantAccountGUI, entity.EMVMerchantAccountGUI)
+	if err != nil {
+		return "", err
+	}
+
+	merchantID, err := emvDataObject(emvMerchantAccountID, emvQRCode.MerchantID)
+	if err != nil {
+		return "", err
+	}
+	merchantAccount += merchantID
+
+	dataObjects := [][2]string{
+		{emvPayloadFormatIndicator, "01"},
+		{emvPointOfInitiation, emvQRCode.PointOfInitiation},
+		{emvMerchantAccount, merchantAccount},
+		{emvMerchantCategoryCode, emvQRCode.MerchantCategoryCode},
+		{emvCurrency, currency},
+	}
+
+	if emvQRCode.IsDynamic() {
+		dataObjects = append(dataObjects, [2]string{emvAmount, emvQRCode.Amount.String()})
+	}
+
+	dataObjects = append(dataObjects,
+		[2]string{emvCountryCode, emvQRCode.CountryCode},
+		[2]string{emvMerchantName, emvText(emvQRCode.MerchantName, emvMerchantNameLength)},
+		[2]string{emvMerchantCity, emvText(emvQRCode.MerchantCity, emvMerchantCityLength)})
+
+	if emvQRCode.IsDynamic() && emvQRCode.ReferenceLabel != "" {
+		referenceLabel, err := emvDataObject(emvReferenceLabel, emvQRCode.ReferenceLabel)
+		if err != nil {
+			return "", err
+		}
+		dataObjects = append(dataObjects, [2]string{emvAdditionalData, referenceLabel})
+	}
+
+	payload := ""
+	for _, dataObject := range dataObjects {
+		encoded, err := emvDataObject(dataObject[0], dataObject[1])
+		if err != nil {
+			return "", err
+		}
+		payload += encoded
+	}
+
+	payload += emvCRC + "04"
+	return payload + fmt.Sprintf("%04X", crc16(payload)), nil
+}
+
+// DecodeEMVQRCode is a function that decodes an EMVCo merchant presented qr code payload of a OnePay merchant
+func DecodeEMVQRCode(payload string) (*entity.EMVQRCode, error) {
+
+	payload = strings.TrimSpace(payload)
+
+	// The checksum is the last data object and covers everything before its value
+	if len(payload) < 8 || payload[len(payload)-8:len(payload)-4] != emvCRC+"04" {
+		return nil, errors.New(entity.InvalidEMVQRCodeError)
+	}
+
+	checksum := payload[len(payload)-4:]
+	if !strings.EqualFold(checksum, fmt.Sprintf("%04X", crc16(payload[:len(payload)-4]))) {
+		return nil, errors.New(entity.InvalidEMVQRCodeError)
+	}
+
+	dataObjects, err := emvDataObjects(payload[:len(payload)-8])
+	if err != nil || dataObjects[emvPayloadFormatIndicator] != "01" {
+		return nil, errors.New(entity.InvalidEMVQRCodeError)
+	}
+
+	emvQRCode := new(entity.EMVQRCode)
+
+	// Finding OnePay's merchant account information between the ones of the other schemes
+	for id := 2; id <= 51; id++ {
+		template, ok := dataObjects[fmt.Sprintf("%02d", id)]
+		if !ok {
+			continue
+		}
+
+		merchantAccount, err := emvDataObjects(template)
+		if err == nil && merchantAccount[emvMerchantAccountGUI] == entity.EMVMerchantAccountGUI {
+			emvQRCode.MerchantID = merchantAccount[emvMerchantAccountID]
+			break
+		}
+	}
+
+	if emvQRCode.MerchantID == "" {
+		return nil, errors.New(entity.ForeignEMVQRCodeError)
+	}
+
+	currency, ok := entity.CurrencyFromNumericCode(dataObjects[emvCurrency])
+	if !ok {
+		return nil, errors.New(entity.UnsupportedCurrencyError)
+	}
+
+	emvQRCode.PointOfInitiation = dataObjects[emvPointOfInitiation]
+	if emvQRCode.PointOfInitiation == "" {
+		emvQRCode.PointOfInitiation = entity.EMVStaticQRCode
+	}
+
+	emvQRCode.Amount = entity.NewMoney(0, currency)
+	if amount, ok := dataObjects[emvAmount]; ok {
+		emvQRCode.Amount, err = entity.ParseMoney(amount, currency)
+		if err != nil {
+			return nil, errors.New(entity.InvalidEMVQRCodeError)
+		}
+	}
+
+	emvQRCode.MerchantCategoryCode = dataObjects[emvMerchantCategoryCode]
+	emvQRCode.CountryCode = dataObjects[emvCountryCode]
+	emvQRCode.MerchantName = dataObjects[emvMerchantName]
+	emvQRCode.MerchantCity = dataObjects[emvMerchantCity]
+
+	if template, ok := dataObjects[emvAdditionalData]; ok {
+		additionalData, err := emvDataObjects(template)
+		if err != nil {
+			return nil, errors.New(entity.InvalidEMVQRCodeError)
+		}
+		emvQRCode.ReferenceLabel = additionalData[emvReferenceLabel]
+	}
+
+	return emvQRCode, nil
+}
+
+// emvDataObject is a function that encodes a single data object from its id and value
+func emvDataObject(id, value string) (string, error) {
+
+	if len(value) == 0 || len(value) > 99 {
+		return "", errors.New(entity.InvalidEMVQRCodeError)
+	}
+
+	return fmt.Sprintf("%s%02d%s", id, len(value), value), nil
+}
+
+// emvDataObjects is a function that decodes a list of data objects to their values mapped by their id
+func emvDataObjects(payload string) (map[string]string, error) {
+
+	dataObjects := make(map[string]string)
+	for len(payload) > 0 {
+		if len(payload) < 4 {
+			return nil, errors.New(entity.InvalidEMVQRCodeError)
+		}
+
+		length, err := strconv.Atoi(payload[2:4])
+		if err != nil || length < 0 || length > len(payload)-4 {
+			return nil, errors.New(entity.InvalidEMVQRCodeError)
+		}
+
+		dataObjects[payload[:2]] = payload[4 : 4+length]
+		payload = payload[4+length:]
+	}
+
+	return dataObjects, nil
+}
+
+// crc16 is a function that calculates the CRC-16/CCITT-FALSE checksum used by EMVCo qr codes
+func crc16(data string) uint16 {
+
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
+
+// emvText is a function that keeps the printable ascii characters of a value, cut down to the provided number of characters,
+// since the merchant name and city of an EMVCo qr code can only hold them
+func emvText(value string, length int) string {
+
+	text := ""
+	for _, char := range value {
+		if char >= ' ' && char <= '~' && len(text) < length {
+			text += string(char)
+		}
+	}
+
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,226 @@
+package tools
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// withEMVCRC is a function that appends the checksum data object to a list of encoded data objects
+func withEMVCRC(dataObjects string) string {
+	payload := dataObjects + emvCRC + "04"
+	return payload + fmt.Sprintf("%04X", crc16(payload))
+}
+
+func TestCRC16(t *testing.T) {
+
+	tests := []struct {
+		data string
+		want uint16
+	}{
+		{"", 0xFFFF},
+		{"123456789", 0x29B1},
+		{"A", 0xB915},
+	}
+
+	for _, test := range tests {
+		if got := crc16(test.data); got != test.want {
+			t.Errorf("crc16(%q) = %04X, want %04X", test.data, got, test.want)
+		}
+	}
+}
+
+func TestEMVQRCodeRoundTrip(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		emvQRCode *entity.EMVQRCode
+	}{
+		{
+			name: "static",
+			emvQRCode: &entity.EMVQRCode{
+				PointOfInitiation:    entity.EMVStaticQRCode,
+				MerchantID:           "OP-123456",
+				MerchantCategoryCode: entity.EMVMerchantCategoryCode,
+				Amount:               entity.NewMoney(0, "ETB"),
+				CountryCode:          entity.EMVCountryCode,
+				MerchantName:         "Abebe Shop",
+				MerchantCity:         entity.EMVMerchantCity,
+			},
+		},
+		{
+			name: "dynamic with reference label",
+			emvQRCode: &entity.EMVQRCode{
+				PointOfInitiation:    entity.EMVDynamicQRCode,
+				MerchantID:           "OP-123456",
+				MerchantCategoryCode: entity.EMVMerchantCategoryCode,
+				Amount:               entity.NewMoney(12550, "USD"),
+				CountryCode:          entity.EMVCountryCode,
+				MerchantName:         "Abebe Shop",
+				MerchantCity:         entity.EMVMerchantCity,
+				ReferenceLabel:       "TOKEN123",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			payload, err := EncodeEMVQRCode(test.emvQRCode)
+			if err != nil {
+				t.Fatalf("EncodeEMVQRCode() error = %v", err)
+			}
+
+			if !IsEMVQRPayload(payload) {
+				t.Errorf("IsEMVQRPayload(%q) = false, want true", payload)
+			}
+
+			decoded, err := DecodeEMVQRCode(payload)
+			if err != nil {
+				t.Fatalf("DecodeEMVQRCode(%q) error = %v", payload, err)
+			}
+
+			if !reflect.DeepEqual(decoded, test.emvQRCode) {
+				t.Errorf("DecodeEMVQRCode(%q) = %+v, want %+v", payload, decoded, test.emvQRCode)
+			}
+		})
+	}
+}
+
+func TestEncodeEMVQRCode(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		emvQRCode *entity.EMVQRCode
+		want      string
+		wantErr   string
+	}{
+		{
+			name: "static",
+			emvQRCode: &entity.EMVQRCode{
+				PointOfInitiation:    entity.EMVStaticQRCode,
+				MerchantID:           "M1",
+				MerchantCategoryCode: "0000",
+				Amount:               entity.NewMoney(500, "ETB"),
+				CountryCode:          "ET",
+				MerchantName:         "Shop",
+				MerchantCity:         "Adama",
+				ReferenceLabel:       "ignored",
+			},
+			want: withEMVCRC("000201010211" + "2619" + "0009et.onepay" + "0102M1" +
+				"52040000" + "5303230" + "5802ET" + "5904Shop" + "6005Adama"),
+		},
+		{
+			name: "dynamic",
+			emvQRCode: &entity.EMVQRCode{
+				PointOfInitiation:    entity.EMVDynamicQRCode,
+				MerchantID:           "M1",
+				MerchantCategoryCode: "0000",
+				Amount:               entity.NewMoney(500, "ETB"),
+				CountryCode:          "ET",
+				MerchantName:         "Shop",
+				MerchantCity:         "Adama",
+				ReferenceLabel:       "REF",
+			},
+			want: withEMVCRC("000201010212" + "2619" + "0009et.onepay" + "0102M1" +
+				"52040000" + "5303230" + "54045.00" + "5802ET" + "5904Shop" + "6005Adama" + "62070503REF"),
+		},
+		{
+			name: "name and city are cut down to printable ascii",
+			emvQRCode: &entity.EMVQRCode{
+				PointOfInitiation:    entity.EMVStaticQRCode,
+				MerchantID:           "M1",
+				MerchantCategoryCode: "0000",
+				Amount:               entity.NewMoney(0, "ETB"),
+				CountryCode:          "ET",
+				MerchantName:         "ሱቅ A Very Long Merchant Name Indeed",
+				MerchantCity:         "Addis Ababa City Center",
+			},
+			want: withEMVCRC("000201010211" + "2619" + "0009et.onepay" + "0102M1" +
+				"52040000" + "5303230" + "5802ET" + "5924A Very Long Merchant Nam" + "6015Addis Ababa Cit"),
+		},
+		{
+			name: "unsupported currency",
+			emvQRCode: &entity.EMVQRCode{
+				PointOfInitiation: entity.EMVStaticQRCode,
+				MerchantID:        "M1",
+				Amount:            entity.Money{Minor: 500, Currency: "XYZ"},
+			},
+			wantErr: entity.UnsupportedCurrencyError,
+		},
+		{
+			name: "missing merchant id",
+			emvQRCode: &entity.EMVQRCode{
+				PointOfInitiation: entity.EMVStaticQRCode,
+				Amount:            entity.NewMoney(0, "ETB"),
+			},
+			wantErr: entity.InvalidEMVQRCodeError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, err := EncodeEMVQRCode(test.emvQRCode)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("EncodeEMVQRCode() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("EncodeEMVQRCode() error = %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("EncodeEMVQRCode() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodeEMVQRCodeMalformed(t *testing.T) {
+
+	onepayAccount := "2619" + "0009et.onepay" + "0102M1"
+	valid := withEMVCRC("000201010211" + onepayAccount + "5303230")
+
+	tests := []struct {
+		name    string
+		payload string
+		wantErr string
+	}{
+		{"empty", "", entity.InvalidEMVQRCodeError},
+		{"too short", "6304", entity.InvalidEMVQRCodeError},
+		{"missing checksum", "000201010211" + onepayAccount + "5303230", entity.InvalidEMVQRCodeError},
+		{"wrong checksum", valid[:len(valid)-4] + "0000", entity.InvalidEMVQRCodeError},
+		{"tampered value", strings.Replace(valid, "0102M1", "0102M2", 1), entity.InvalidEMVQRCodeError},
+		{"length past the payload", withEMVCRC("000201" + "5399230"), entity.InvalidEMVQRCodeError},
+		{"negative length", withEMVCRC("000201" + "53-1230"), entity.InvalidEMVQRCodeError},
+		{"non numeric length", withEMVCRC("000201" + "53xx230"), entity.InvalidEMVQRCodeError},
+		{"truncated data object", withEMVCRC("000201" + "530"), entity.InvalidEMVQRCodeError},
+		{"wrong payload format", withEMVCRC("000202" + onepayAccount + "5303230"), entity.InvalidEMVQRCodeError},
+		{"foreign merchant account", withEMVCRC("000201" + "2617" + "0007et.other" + "0101X" + "5303230"),
+			entity.ForeignEMVQRCodeError},
+		{"unsupported currency", withEMVCRC("000201" + onepayAccount + "5303999"), entity.UnsupportedCurrencyError},
+		{"invalid amount", withEMVCRC("000201" + onepayAccount + "5303230" + "5403abc"), entity.InvalidEMVQRCodeError},
+		{"malformed additional data", withEMVCRC("000201" + onepayAccount + "5303230" + "62030599"),
+			entity.InvalidEMVQRCodeError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := DecodeEMVQRCode(test.payload)
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("DecodeEMVQRCode(%q) error = %v, want %q", test.payload, err, test.wantErr)
+			}
+		})
+	}
+
+	if _, err := DecodeEMVQRCode(valid); err != nil {
+		t.Errorf("DecodeEMVQRCode(%q) error = %v, want nil", valid, err)
+	}
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"strings"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// LedgerAccountID is a function that returns the ledger account id for the provided account type and owner.
+// System accounts such as the fee income account have no owner so their id is only the account type.
+func LedgerAccountID(accountType, ownerID string) string {
+	if ownerID == "" {
+		return accountType
+	}
+	return accountType + ":" + ownerID
+}
+
+// CurrencyLedgerAccountID is a function that returns the ledger account id for the provided account type and owner
+// that holds money of the provided currency. Accounts of the base currency keep the plain id.
+func CurrencyLedgerAccountID(accountType, ownerID, currency string) string {
+	currency = strings.ToUpper(currency)
+	if currency == "" || currency == entity.BaseCurrency {
+		return LedgerAccountID(accountType, ownerID)
+	}
+	return LedgerAccountID(accountType, ownerID) + "/" + currency
+}
+
+// LedgerAccountCurrency is a function that returns the currency of the money held in the provided ledger account
+func LedgerAccountCurrency(accountID string) string {
+	index := strings.LastIndex(accountID, "/")
+	if index == -1 {
+		return entity.BaseCurrency
+	}
+	return accountID[index+1:]
+}
+
+// ParseLedgerAccountID is a function that splits a ledger account id to its account type and owner
+func ParseLedgerAccountID(accountID string) (string, string) {
+	if index := strings.LastIndex(accountID, "/"); index != -1 {
+		accountID = accountID[:index]
+	}
+
+	parts := strings.SplitN(accountID, ":", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// IsValidLedgerAccountType is a function that checks whether the provided ledger account type is valid or not
+func IsValidLedgerAccountType(accountType string) bool {
+
+	validAccountTypes := []string{entity.LedgerAccountWallet, entity.LedgerAccountFeeIncome,
+		entity.LedgerAccountProviderClearing, entity.LedgerAccountMoneyTokenHolding, entity.LedgerAccountOpeningBalance,
+		entity.LedgerAccountCurrencyExchange, entity.LedgerAccountDisputeHolding,
+		entity.LedgerAccountWalletHold}
+	for _, validAccountType := range validAccountTypes {
+		if validAccountType == accountType {
+			return true
+		}
+	}
+
+	return false
+}
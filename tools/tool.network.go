@@ -1,6 +1,8 @@
 package tools
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"net"
 	"net/http"
@@ -37,3 +39,23 @@ func GetIP(r *http.Request) (string, error) {
 	}
 	return "", errors.New("no valid ip found")
 }
+
+// RequestFingerprint is a function that returns a hash that identifies the method, path and form values of a request.
+// The form values are encoded in a sorted order so the same payload always gives the same fingerprint.
+func RequestFingerprint(r *http.Request) (string, error) {
+
+	// The url encoded body and query values are parsed first, since ParseMultipartForm drops their parsing error
+	// when the body isn't multipart
+	err := r.ParseForm()
+	if err != nil {
+		return "", err
+	}
+
+	err = r.ParseMultipartForm(32 << 20)
+	if err != nil && err != http.ErrNotMultipart {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(r.Method + " " + r.URL.Path + "?" + r.Form.Encode()))
+	return hex.EncodeToString(hash[:]), nil
+}
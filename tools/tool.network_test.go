@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// formRequest is a function that creates a request with an url encoded body
+func formRequest(method, target, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+// multipartRequest is a function that creates a request with a multipart body holding the provided fields in order
+func multipartRequest(method, target string, fields [][2]string) *http.Request {
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for _, field := range fields {
+		writer.WriteField(field[0], field[1])
+	}
+	writer.Close()
+
+	r := httptest.NewRequest(method, target, body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	return r
+}
+
+func TestRequestFingerprint(t *testing.T) {
+
+	fingerprint := func(r *http.Request) string {
+		value, err := RequestFingerprint(r)
+		if err != nil {
+			t.Fatalf("RequestFingerprint() error = %v", err)
+		}
+		return value
+	}
+
+	base := fingerprint(formRequest(http.MethodPost, "/api/v1/transfer", "amount=10&receiver=OP-1"))
+
+	hash := sha256.Sum256([]byte("POST /api/v1/transfer?amount=10&receiver=OP-1"))
+	if want := hex.EncodeToString(hash[:]); base != want {
+		t.Errorf("RequestFingerprint() = %q, want %q", base, want)
+	}
+
+	tests := []struct {
+		name string
+		r    *http.Request
+		same bool
+	}{
+		{"same payload", formRequest(http.MethodPost, "/api/v1/transfer", "amount=10&receiver=OP-1"), true},
+		{"fields in another order", formRequest(http.MethodPost, "/api/v1/transfer", "receiver=OP-1&amount=10"), true},
+		{"fields in the query", formRequest(http.MethodPost, "/api/v1/transfer?receiver=OP-1", "amount=10"), true},
+		{"multipart body", multipartRequest(http.MethodPost, "/api/v1/transfer",
+			[][2]string{{"receiver", "OP-1"}, {"amount", "10"}}), true},
+		{"another amount", formRequest(http.MethodPost, "/api/v1/transfer", "amount=11&receiver=OP-1"), false},
+		{"an extra field", formRequest(http.MethodPost, "/api/v1/transfer", "amount=10&receiver=OP-1&note=x"), false},
+		{"another path", formRequest(http.MethodPost, "/api/v1/withdraw", "amount=10&receiver=OP-1"), false},
+		{"another method", formRequest(http.MethodPut, "/api/v1/transfer", "amount=10&receiver=OP-1"), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := fingerprint(test.r); (got == base) != test.same {
+				t.Errorf("RequestFingerprint() = %q, base fingerprint %q, want same %v", got, base, test.same)
+			}
+		})
+	}
+}
+
+func TestRequestFingerprintMalformedBody(t *testing.T) {
+
+	if _, err := RequestFingerprint(formRequest(http.MethodPost, "/api/v1/transfer", "amount=%zz")); err == nil {
+		t.Errorf("RequestFingerprint() with a malformed body error = nil, want an error")
+	}
+}
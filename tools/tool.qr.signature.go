@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// The signed envelope of a qr code payload is a compact JWS signed with ES256, its header names the signing key in 'kid'
+// and its claims are
+//
+//	code   the money token code
+//	amt    the money token amount as a decimal string
+//	cur    the currency of the amount
+//	snd    the OnePay id of the money token's sender
+//	exp    the expiration date of the money token as a unix timestamp when the envelope was signed
+//
+// The public keys are published as a JWKS so terminals can check the envelope without calling the server.
+
+// QRClaims is a type that defines the claims of a qr code payload's signed envelope
+type QRClaims struct {
+	Code     string `json:"code"`
+	Amount   string `json:"amt"`
+	Currency string `json:"cur"`
+	SenderID string `json:"snd"`
+	jwt.StandardClaims
+}
+
+// JSONWebKey is a type that defines a public key in the JSON Web Key format
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+}
+
+// ParseQRSigningKey is a function that parses the PEM encoded private key of a qr signing key
+func ParseQRSigningKey(key *entity.QRSigningKey) (*ecdsa.PrivateKey, error) {
+
+	privateKey, err := jwt.ParseECPrivateKeyFromPEM([]byte(key.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+
+	if privateKey.Curve.Params().Name != "P-256" {
+		return nil, errors.New("qr signing key should be a P-256 key")
+	}
+
+	return privateKey, nil
+}
+
+// SignQRClaims is a function that returns the signed envelope of the provided claims using the qr signing key
+func SignQRClaims(claims *QRClaims, key *entity.QRSigningKey) (string, error) {
+
+	privateKey, err := ParseQRSigningKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = key.KeyID
+	return token.SignedString(privateKey)
+}
+
+// VerifyQREnvelope is a function that checks the signature of a signed envelope against the qr signing keys and returns its claims.
+// The expiration date isn't checked since refreshing a money token extends it, the money token's own expiration date is used instead.
+func VerifyQREnvelope(envelope string, keys []*entity.QRSigningKey) (*QRClaims, error) {
+
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.ParseWithClaims(envelope, &QRClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodES256 {
+			return nil, errors.New("error in signing method")
+		}
+
+		keyID, _ := token.Header["kid"].(string)
+		for _, key := range keys {
+			if key.KeyID == keyID {
+				privateKey, err := ParseQRSigningKey(key)
+				if err != nil {
+					return nil, err
+				}
+				return &privateKey.PublicKey, nil
+			}
+		}
+
+		return nil, errors.New("unknown signing key")
+	})
+
+	if err != nil {
+		return nil, errors.New(entity.InvalidQRSignatureError)
+	}
+
+	claims, ok := token.Claims.(*QRClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New(entity.InvalidQRSignatureError)
+	}
+
+	return claims, nil
+}
+
+// QRJSONWebKeys is a function that returns the public keys of the qr signing keys in the JSON Web Key format
+func QRJSONWebKeys(keys []*entity.QRSigningKey) ([]*JSONWebKey, error) {
+
+	jsonWebKeys := make([]*JSONWebKey, 0)
+	for _, key := range keys {
+		privateKey, err := ParseQRSigningKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonWebKeys = append(jsonWebKeys, &JSONWebKey{
+			KeyType:   "EC",
+			Curve:     "P-256",
+			X:         encodeCoordinate(privateKey.PublicKey.X),
+			Y:         encodeCoordinate(privateKey.PublicKey.Y),
+			KeyID:     key.KeyID,
+			Use:       "sig",
+			Algorithm: jwt.SigningMethodES256.Alg(),
+		})
+	}
+
+	return jsonWebKeys, nil
+}
+
+// encodeCoordinate is a function that encodes a P-256 curve coordinate as the 32 byte base64url value used by JSON Web Keys
+func encodeCoordinate(coordinate *big.Int) string {
+
+	bytes := make([]byte, 32)
+	coordinate.FillBytes(bytes)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
@@ -0,0 +1,158 @@
+package tools
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// newQRSigningKey is a function that generates a qr signing key on the provided curve for testing
+func newQRSigningKey(t *testing.T, keyID string, curve elliptic.Curve) *entity.QRSigningKey {
+
+	privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() error = %v", err)
+	}
+
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("x509.MarshalECPrivateKey() error = %v", err)
+	}
+
+	encoded := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	return &entity.QRSigningKey{KeyID: keyID, PrivateKey: string(encoded)}
+}
+
+func TestQREnvelope(t *testing.T) {
+
+	currentKey := newQRSigningKey(t, "current", elliptic.P256())
+	previousKey := newQRSigningKey(t, "previous", elliptic.P256())
+	unknownKey := newQRSigningKey(t, "unknown", elliptic.P256())
+	impostorKey := newQRSigningKey(t, "current", elliptic.P256())
+
+	claims := &QRClaims{Code: "ABC123", Amount: "10.00", Currency: "ETB", SenderID: "OP-1"}
+	claims.ExpiresAt = 1
+
+	sign := func(key *entity.QRSigningKey) string {
+		envelope, err := SignQRClaims(claims, key)
+		if err != nil {
+			t.Fatalf("SignQRClaims() error = %v", err)
+		}
+		return envelope
+	}
+
+	hmacEnvelope, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	// Replacing the claims of a valid envelope with the claims of another one while keeping its signature
+	parts := strings.Split(sign(currentKey), ".")
+	otherClaims := *claims
+	otherClaims.Amount = "1000.00"
+	otherEnvelope, err := SignQRClaims(&otherClaims, currentKey)
+	if err != nil {
+		t.Fatalf("SignQRClaims() error = %v", err)
+	}
+	tamperedEnvelope := parts[0] + "." + strings.Split(otherEnvelope, ".")[1] + "." + parts[2]
+
+	keys := []*entity.QRSigningKey{currentKey, previousKey}
+
+	tests := []struct {
+		name     string
+		envelope string
+		valid    bool
+	}{
+		{"signed by the current key", sign(currentKey), true},
+		{"signed by a rotated out key that is still listed", sign(previousKey), true},
+		{"signed by an unknown key", sign(unknownKey), false},
+		{"signed by another key with a listed key id", sign(impostorKey), false},
+		{"tampered claims", tamperedEnvelope, false},
+		{"signed with another algorithm", hmacEnvelope, false},
+		{"not an envelope", "not-an-envelope", false},
+		{"empty", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, err := VerifyQREnvelope(test.envelope, keys)
+			if !test.valid {
+				if err == nil || err.Error() != entity.InvalidQRSignatureError {
+					t.Fatalf("VerifyQREnvelope() error = %v, want %q", err, entity.InvalidQRSignatureError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("VerifyQREnvelope() error = %v", err)
+			}
+
+			// The expiration date of the envelope isn't checked
+			if !reflect.DeepEqual(got, claims) {
+				t.Errorf("VerifyQREnvelope() = %+v, want %+v", got, claims)
+			}
+		})
+	}
+}
+
+func TestParseQRSigningKey(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		key     *entity.QRSigningKey
+		wantErr bool
+	}{
+		{"P-256 key", newQRSigningKey(t, "p256", elliptic.P256()), false},
+		{"P-384 key", newQRSigningKey(t, "p384", elliptic.P384()), true},
+		{"not a PEM key", &entity.QRSigningKey{KeyID: "invalid", PrivateKey: "invalid"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseQRSigningKey(test.key)
+			if (err != nil) != test.wantErr {
+				t.Errorf("ParseQRSigningKey() error = %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+
+	if _, err := SignQRClaims(&QRClaims{}, &entity.QRSigningKey{PrivateKey: "invalid"}); err == nil {
+		t.Errorf("SignQRClaims() with an invalid key error = nil, want an error")
+	}
+}
+
+func TestQRJSONWebKeys(t *testing.T) {
+
+	key := newQRSigningKey(t, "current", elliptic.P256())
+	privateKey, err := ParseQRSigningKey(key)
+	if err != nil {
+		t.Fatalf("ParseQRSigningKey() error = %v", err)
+	}
+
+	jsonWebKeys, err := QRJSONWebKeys([]*entity.QRSigningKey{key})
+	if err != nil {
+		t.Fatalf("QRJSONWebKeys() error = %v", err)
+	}
+
+	want := &JSONWebKey{KeyType: "EC", Curve: "P-256", X: encodeCoordinate(privateKey.PublicKey.X),
+		Y: encodeCoordinate(privateKey.PublicKey.Y), KeyID: "current", Use: "sig", Algorithm: "ES256"}
+
+	if len(jsonWebKeys) != 1 || !reflect.DeepEqual(jsonWebKeys[0], want) {
+		t.Errorf("QRJSONWebKeys() = %+v, want [%+v]", jsonWebKeys, want)
+	}
+
+	// Coordinates with leading zero bytes are still encoded as 32 bytes
+	if got, want := encodeCoordinate(big.NewInt(1)), "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE"; got != want {
+		t.Errorf("encodeCoordinate(1) = %q, want %q", got, want)
+	}
+}
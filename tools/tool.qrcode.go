@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/skip2/go-qrcode"
+)
+
+// A OnePay qr code payload is an uri of the form
+//
+//	onepay://receive/<code>   for a transaction money token, the scanner receives the money
+//	onepay://pay/<code>       for a payment money token, the scanner pays the money
+//
+// so apps and merchant terminals can tell what a scanned code is for before asking the server about it.
+// When qr signing keys have been configured the payload also carries a signed envelope of the money token in its 'sig' query value,
+// e.g. onepay://pay/<code>?sig=<envelope>.
+
+// qrCodeLogoRatio is the width of the logo overlay relative to the width of the qr code
+const qrCodeLogoRatio = 5
+
+// QRPayload is a function that returns the qr code payload of a money token with the provided payload type.
+// The signed envelope is left out if it is empty.
+func QRPayload(payloadType, code, envelope string) string {
+
+	payload := entity.QRPayloadScheme + "://" + payloadType + "/" + url.PathEscape(code)
+	if envelope != "" {
+		payload += "?sig=" + url.QueryEscape(envelope)
+	}
+
+	return payload
+}
+
+// ParseQRPayload is a function that parses a OnePay qr code payload, returning its payload type, money token code and signed envelope
+func ParseQRPayload(payload string) (string, string, string, error) {
+
+	payloadURL, err := url.Parse(strings.TrimSpace(payload))
+	if err != nil || payloadURL.Scheme != entity.QRPayloadScheme {
+		return "", "", "", errors.New(entity.InvalidMoneyTokenError)
+	}
+
+	escapedCode := strings.TrimPrefix(payloadURL.EscapedPath(), "/")
+	code, err := url.PathUnescape(escapedCode)
+	if err != nil || code == "" || strings.Contains(escapedCode, "/") {
+		return "", "", "", errors.New(entity.InvalidMoneyTokenError)
+	}
+
+	switch payloadURL.Host {
+	case entity.QRPayloadReceive, entity.QRPayloadPay:
+		return payloadURL.Host, code, payloadURL.Query().Get("sig"), nil
+	}
+
+	return "", "", "", errors.New(entity.InvalidMoneyTokenError)
+}
+
+// QRCodeLevel is a function that returns the qr code error correction level from its name, L, M, Q or H
+func QRCodeLevel(level string) (qrcode.RecoveryLevel, error) {
+
+	switch strings.ToUpper(level) {
+	case "L":
+		return qrcode.Low, nil
+	case "M":
+		return qrcode.Medium, nil
+	case "Q":
+		return qrcode.High, nil
+	case "H":
+		return qrcode.Highest, nil
+	}
+
+	return 0, errors.New(entity.InvalidQRCodeLevelError)
+}
+
+// QRCodeLogo is a function that reads the logo that is overlaid on qr codes from the assets folder
+func QRCodeLogo() (image.Image, error) {
+
+	wd, _ := os.Getwd()
+	file, err := os.Open(filepath.Join(wd, "./assets/images", "qr.logo.png"))
+	if err != nil {
+		return nil, errors.New(entity.QRCodeLogoNotFoundError)
+	}
+	defer file.Close()
+
+	return png.Decode(file)
+}
+
+// QRCodePNG is a function that renders the content as a png qr code of the provided size in pixels.
+// If a logo is provided it is drawn at the center of the qr code.
+func QRCodePNG(content string, size int, level qrcode.RecoveryLevel, logo image.Image) ([]byte, error) {
+
+	qrCode, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, err
+	}
+
+	qrImage := qrCode.Image(size)
+	canvas := image.NewRGBA(qrImage.Bounds())
+	draw.Draw(canvas, canvas.Bounds(), qrImage, qrImage.Bounds().Min, draw.Src)
+
+	if logo != nil {
+		width := canvas.Bounds().Dx()
+		side := width / qrCodeLogoRatio
+		margin := side / 10
+		center := width / 2
+		background := image.Rect(center-side/2-margin, center-side/2-margin, center+side/2+margin, center+side/2+margin)
+		draw.Draw(canvas, background, image.NewUniform(color.White), image.Point{}, draw.Src)
+		drawScaled(canvas, image.Rect(center-side/2, center-side/2, center+side/2, center+side/2), logo)
+	}
+
+	var buffer bytes.Buffer
+	err = png.Encode(&buffer, canvas)
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// QRCodeSVG is a function that renders the content as a svg qr code of the provided size in pixels.
+// If a logo is provided it is embedded at the center of the qr code.
+func QRCodeSVG(content string, size int, level qrcode.RecoveryLevel, logo image.Image) ([]byte, error) {
+
+	qrCode, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, err
+	}
+
+	// The bitmap includes the quiet zone around the qr code, one unit of the view box is one module
+	bitmap := qrCode.Bitmap()
+	modules := len(bitmap)
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, modules, modules)
+	fmt.Fprintf(&buffer, `<rect width="%d" height="%d" fill="#ffffff"/>`, modules, modules)
+
+	// Adjacent dark modules of a row are drawn as a single run
+	buffer.WriteString(`<path fill="#000000" d="`)
+	for y, row := range bitmap {
+		for x := 0; x < modules; x++ {
+			if !row[x] {
+				continue
+			}
+
+			start := x
+			for x < modules && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buffer, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	buffer.WriteString(`"/>`)
+
+	if logo != nil {
+		var logoBuffer bytes.Buffer
+		err = png.Encode(&logoBuffer, logo)
+		if err != nil {
+			return nil, err
+		}
+
+		side := float64(modules) / qrCodeLogoRatio
+		margin := side / 10
+		start := (float64(modules) - side) / 2
+		fmt.Fprintf(&buffer, `<rect x="%g" y="%g" width="%g" height="%g" fill="#ffffff"/>`,
+			start-margin, start-margin, side+2*margin, side+2*margin)
+		fmt.Fprintf(&buffer, `<image x="%g" y="%g" width="%g" height="%g" href="data:image/png;base64,%s"/>`,
+			start, start, side, side, base64.StdEncoding.EncodeToString(logoBuffer.Bytes()))
+	}
+
+	buffer.WriteString("</svg>")
+	return buffer.Bytes(), nil
+}
+
+// drawScaled is a function that draws the source image scaled to fit the destination rectangle, keeping its aspect ratio
+func drawScaled(canvas draw.Image, rect image.Rectangle, source image.Image) {
+
+	bounds := source.Bounds()
+	if bounds.Empty() || rect.Empty() {
+		return
+	}
+
+	// Fitting the longer side of the source image into the rectangle
+	width, height := rect.Dx(), rect.Dy()
+	if bounds.Dx()*height > bounds.Dy()*width {
+		height = bounds.Dy() * width / bounds.Dx()
+	} else {
+		width = bounds.Dx() * height / bounds.Dy()
+	}
+
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			scaled.Set(x, y, source.At(bounds.Min.X+x*bounds.Dx()/width, bounds.Min.Y+y*bounds.Dy()/height))
+		}
+	}
+
+	offset := image.Pt(rect.Min.X+(rect.Dx()-width)/2, rect.Min.Y+(rect.Dy()-height)/2)
+	draw.Draw(canvas, scaled.Bounds().Add(offset), scaled, image.Point{}, draw.Over)
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+func TestQRPayloadRoundTrip(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		payloadType string
+		code        string
+		envelope    string
+		want        string
+	}{
+		{"receive without envelope", entity.QRPayloadReceive, "ABC123", "", "onepay://receive/ABC123"},
+		{"pay with envelope", entity.QRPayloadPay, "ABC123", "a.b-c_d.e", "onepay://pay/ABC123?sig=a.b-c_d.e"},
+		{"code with a slash", entity.QRPayloadPay, "AB/C", "", "onepay://pay/AB%2FC"},
+		{"envelope with reserved characters", entity.QRPayloadPay, "ABC", "a+b=&c", "onepay://pay/ABC?sig=a%2Bb%3D%26c"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			payload := QRPayload(test.payloadType, test.code, test.envelope)
+			if payload != test.want {
+				t.Errorf("QRPayload() = %q, want %q", payload, test.want)
+			}
+
+			payloadType, code, envelope, err := ParseQRPayload(payload)
+			if err != nil {
+				t.Fatalf("ParseQRPayload(%q) error = %v", payload, err)
+			}
+
+			if payloadType != test.payloadType || code != test.code || envelope != test.envelope {
+				t.Errorf("ParseQRPayload(%q) = %q, %q, %q, want %q, %q, %q", payload,
+					payloadType, code, envelope, test.payloadType, test.code, test.envelope)
+			}
+		})
+	}
+}
+
+func TestParseQRPayloadInvalid(t *testing.T) {
+
+	payloads := []string{
+		"",
+		"ABC123",
+		"https://receive/ABC123",
+		"onepay://send/ABC123",
+		"onepay://pay/",
+		"onepay://pay/AB/C",
+		"onepay://pay/%ZZ",
+	}
+
+	for _, payload := range payloads {
+		if _, _, _, err := ParseQRPayload(payload); err == nil || err.Error() != entity.InvalidMoneyTokenError {
+			t.Errorf("ParseQRPayload(%q) error = %v, want %q", payload, err, entity.InvalidMoneyTokenError)
+		}
+	}
+}
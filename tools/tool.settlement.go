@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// The fixed width bank format has a header record, one detail record per settled transaction and a trailer record.
+// Every record starts with its record type, the detail record is laid out as
+//
+//	1      record type 'D'
+//	2-21   provider reference, left aligned and padded with spaces
+//	22     direction, 'D' for money debited from the account (recharge) and 'C' for money credited to it (withdrawal)
+//	23-37  amount in minor units, padded with leading zeros
+//	38-40  currency code
+//	41-48  settlement date as YYYYMMDD
+//
+// and the trailer record carries the number of detail records in positions 2-9.
+const fixedWidthDetailLength = 48
+
+// ParseSettlementCSV is a function that parses a settlement statement of comma separated values.
+// The first row has to name the columns, 'reference', 'type', 'amount' and 'date' are required while 'currency' is optional.
+func ParseSettlementCSV(statement io.Reader) ([]*entity.SettlementItem, error) {
+
+	reader := csv.NewReader(statement)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New(entity.EmptySettlementError)
+	}
+
+	columns := make(map[string]int)
+	for index, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = index
+	}
+
+	for _, column := range []string{"reference", "type", "amount", "date"} {
+		if _, ok := columns[column]; !ok {
+			return nil, errors.New(entity.InvalidSettlementFormatError)
+		}
+	}
+
+	value := func(record []string, column string) string {
+		index, ok := columns[column]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	items := make([]*entity.SettlementItem, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.New(entity.InvalidSettlementLineError)
+		}
+
+		item, err := settlementItem(value(record, "reference"), value(record, "type"),
+			value(record, "currency"), value(record, "date"), func(currency string) (entity.Money, error) {
+				return entity.ParseMoney(value(record, "amount"), currency)
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New(entity.EmptySettlementError)
+	}
+
+	return items, nil
+}
+
+// ParseSettlementFixedWidth is a function that parses a settlement statement of the fixed width bank format.
+// If the statement has a trailer record its count has to match the number of detail records.
+func ParseSettlementFixedWidth(statement io.Reader) ([]*entity.SettlementItem, error) {
+
+	scanner := bufio.NewScanner(statement)
+	items := make([]*entity.SettlementItem, 0)
+	trailerCount := -1
+
+	for scanner.Scan() {
+
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		switch line[0] {
+		case 'H':
+			continue
+
+		case 'T':
+			count, err := strconv.Atoi(strings.TrimSpace(fixedWidthField(line, 2, 9)))
+			if err != nil {
+				return nil, errors.New(entity.InvalidSettlementLineError)
+			}
+			trailerCount = count
+
+		case 'D':
+			if len(line) < fixedWidthDetailLength {
+				return nil, errors.New(entity.InvalidSettlementLineError)
+			}
+
+			item, err := settlementItem(fixedWidthField(line, 2, 21), fixedWidthField(line, 22, 22),
+				fixedWidthField(line, 38, 40), fixedWidthField(line, 41, 48), func(currency string) (entity.Money, error) {
+					minor, err := strconv.ParseInt(fixedWidthField(line, 23, 37), 10, 64)
+					if err != nil {
+						return entity.Money{}, errors.New(entity.AmountParsingError)
+					}
+					return entity.NewMoney(minor, currency), nil
+				})
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+
+		default:
+			return nil, errors.New(entity.InvalidSettlementLineError)
+		}
+	}
+
+	if scanner.Err() != nil {
+		return nil, errors.New(entity.InvalidSettlementFormatError)
+	}
+
+	if trailerCount != -1 && trailerCount != len(items) {
+		return nil, errors.New(entity.InvalidSettlementFormatError)
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New(entity.EmptySettlementError)
+	}
+
+	return items, nil
+}
+
+// fixedWidthField is a function that returns the trimmed value found between the provided one based positions of a line
+func fixedWidthField(line string, from, to int) string {
+	if from > len(line) {
+		return ""
+	}
+	if to > len(line) {
+		to = len(line)
+	}
+	return strings.TrimSpace(line[from-1 : to])
+}
+
+// settlementItem is a function that creates a settlement item from the values of a single statement line.
+// The amount is parsed by the provided function once the currency is known.
+func settlementItem(reference, direction, currency, date string,
+	parseAmount func(currency string) (entity.Money, error)) (*entity.SettlementItem, error) {
+
+	if reference == "" {
+		return nil, errors.New(entity.InvalidSettlementLineError)
+	}
+
+	item := new(entity.SettlementItem)
+	item.Reference = reference
+
+	switch strings.ToUpper(direction) {
+	case "D", "DEBIT", "RECHARGE":
+		item.Method = entity.MethodRecharged
+	case "C", "CREDIT", "WITHDRAW", "WITHDRAWAL":
+		item.Method = entity.MethodWithdrawn
+	default:
+		return nil, errors.New(entity.InvalidSettlementLineError)
+	}
+
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		currency = entity.BaseCurrency
+	}
+
+	if !entity.IsSupportedCurrency(currency) {
+		return nil, errors.New(entity.UnsupportedCurrencyError)
+	}
+
+	amount, err := parseAmount(currency)
+	if err != nil || !amount.IsPositive() {
+		return nil, errors.New(entity.InvalidSettlementLineError)
+	}
+	item.Amount = amount
+
+	for _, layout := range []string{"20060102", "2006-01-02", time.RFC3339} {
+		settledAt, err := time.ParseInLocation(layout, date, time.Local)
+		if err == nil {
+			item.SettledAt = settledAt
+			return item, nil
+		}
+	}
+
+	return nil, errors.New(entity.InvalidSettlementLineError)
+}
@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// settlementDate is a function that returns the local midnight of the provided date, the way settlement dates are parsed
+func settlementDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+// fixedWidthDetail is a function that lays out a detail record of the fixed width bank format
+func fixedWidthDetail(reference, direction string, minor int64, currency, date string) string {
+	return fmt.Sprintf("D%-20s%s%015d%-3s%s", reference, direction, minor, currency, date)
+}
+
+func TestParseSettlementCSV(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		statement string
+		want      []*entity.SettlementItem
+		wantErr   string
+	}{
+		{
+			name: "recharge and withdrawal",
+			statement: "reference,type,amount,currency,date\n" +
+				"REF1,recharge,100.50,etb,2024-03-01\n" +
+				"REF2, C ,20,USD,20240302\n",
+			want: []*entity.SettlementItem{
+				{Reference: "REF1", Method: entity.MethodRecharged, Amount: entity.NewMoney(10050, "ETB"),
+					SettledAt: settlementDate(2024, time.March, 1)},
+				{Reference: "REF2", Method: entity.MethodWithdrawn, Amount: entity.NewMoney(2000, "USD"),
+					SettledAt: settlementDate(2024, time.March, 2)},
+			},
+		},
+		{
+			name:      "columns in any order and currency defaulting to the base currency",
+			statement: "Date, Amount, Reference, Type\n2024-03-01, 5, REF1, debit\n",
+			want: []*entity.SettlementItem{
+				{Reference: "REF1", Method: entity.MethodRecharged, Amount: entity.NewMoney(500, entity.BaseCurrency),
+					SettledAt: settlementDate(2024, time.March, 1)},
+			},
+		},
+		{name: "empty statement", statement: "", wantErr: entity.EmptySettlementError},
+		{name: "header only", statement: "reference,type,amount,date\n", wantErr: entity.EmptySettlementError},
+		{name: "missing column", statement: "reference,type,amount\nREF1,D,5\n", wantErr: entity.InvalidSettlementFormatError},
+		{name: "missing reference", statement: "reference,type,amount,date\n,D,5,2024-03-01\n",
+			wantErr: entity.InvalidSettlementLineError},
+		{name: "unknown type", statement: "reference,type,amount,date\nREF1,X,5,2024-03-01\n",
+			wantErr: entity.InvalidSettlementLineError},
+		{name: "unsupported currency", statement: "reference,type,amount,currency,date\nREF1,D,5,XYZ,2024-03-01\n",
+			wantErr: entity.UnsupportedCurrencyError},
+		{name: "invalid amount", statement: "reference,type,amount,date\nREF1,D,abc,2024-03-01\n",
+			wantErr: entity.InvalidSettlementLineError},
+		{name: "zero amount", statement: "reference,type,amount,date\nREF1,D,0,2024-03-01\n",
+			wantErr: entity.InvalidSettlementLineError},
+		{name: "invalid date", statement: "reference,type,amount,date\nREF1,D,5,01/03/2024\n",
+			wantErr: entity.InvalidSettlementLineError},
+		{name: "wrong number of fields", statement: "reference,type,amount,date\nREF1,D,5\n",
+			wantErr: entity.InvalidSettlementLineError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, err := ParseSettlementCSV(strings.NewReader(test.statement))
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("ParseSettlementCSV() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseSettlementCSV() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseSettlementCSV() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseSettlementFixedWidth(t *testing.T) {
+
+	recharge := fixedWidthDetail("REF1", "D", 10050, "ETB", "20240301")
+	withdrawal := fixedWidthDetail("REF2", "C", 2000, "USD", "20240302")
+	items := []*entity.SettlementItem{
+		{Reference: "REF1", Method: entity.MethodRecharged, Amount: entity.NewMoney(10050, "ETB"),
+			SettledAt: settlementDate(2024, time.March, 1)},
+		{Reference: "REF2", Method: entity.MethodWithdrawn, Amount: entity.NewMoney(2000, "USD"),
+			SettledAt: settlementDate(2024, time.March, 2)},
+	}
+
+	tests := []struct {
+		name      string
+		statement string
+		want      []*entity.SettlementItem
+		wantErr   string
+	}{
+		{
+			name:      "header, details and trailer",
+			statement: "HBANK 20240302\n" + recharge + "\n" + withdrawal + "\n" + "T00000002\n",
+			want:      items,
+		},
+		{
+			name:      "without trailer, with windows line endings and blank lines",
+			statement: recharge + "\r\n\r\n" + withdrawal + "\r\n",
+			want:      items,
+		},
+		{name: "empty statement", statement: "", wantErr: entity.EmptySettlementError},
+		{name: "header and trailer only", statement: "HBANK\nT00000000\n", wantErr: entity.EmptySettlementError},
+		{name: "trailer count mismatch", statement: recharge + "\nT00000002\n", wantErr: entity.InvalidSettlementFormatError},
+		{name: "invalid trailer count", statement: recharge + "\nTxx\n", wantErr: entity.InvalidSettlementLineError},
+		{name: "unknown record type", statement: "X" + recharge[1:] + "\n", wantErr: entity.InvalidSettlementLineError},
+		{name: "short detail record", statement: recharge[:fixedWidthDetailLength-1] + "\n",
+			wantErr: entity.InvalidSettlementLineError},
+		{name: "unknown direction", statement: fixedWidthDetail("REF1", "X", 100, "ETB", "20240301") + "\n",
+			wantErr: entity.InvalidSettlementLineError},
+		{name: "non numeric amount", statement: strings.Replace(recharge, "000000000010050", "0000000000100x0", 1) + "\n",
+			wantErr: entity.InvalidSettlementLineError},
+		{name: "zero amount", statement: fixedWidthDetail("REF1", "D", 0, "ETB", "20240301") + "\n",
+			wantErr: entity.InvalidSettlementLineError},
+		{name: "unsupported currency", statement: fixedWidthDetail("REF1", "D", 100, "XYZ", "20240301") + "\n",
+			wantErr: entity.UnsupportedCurrencyError},
+		{name: "invalid date", statement: fixedWidthDetail("REF1", "D", 100, "ETB", "20241301") + "\n",
+			wantErr: entity.InvalidSettlementLineError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, err := ParseSettlementFixedWidth(strings.NewReader(test.statement))
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("ParseSettlementFixedWidth() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseSettlementFixedWidth() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseSettlementFixedWidth() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFixedWidthField(t *testing.T) {
+
+	tests := []struct {
+		line     string
+		from, to int
+		want     string
+	}{
+		{"ABCDEF", 2, 4, "BCD"},
+		{"A  B  ", 2, 6, "B"},
+		{"ABC", 2, 9, "BC"},
+		{"ABC", 4, 9, ""},
+	}
+
+	for _, test := range tests {
+		if got := fixedWidthField(test.line, test.from, test.to); got != test.want {
+			t.Errorf("fixedWidthField(%q, %d, %d) = %q, want %q", test.line, test.from, test.to, got, test.want)
+		}
+	}
+}
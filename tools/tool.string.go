@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/rand"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -71,6 +72,12 @@ func GenerateMoneyTokenCode() string {
 
 }
 
+// GenerateProviderReference is a function that generates the reference an account provider settles a recharge or a withdrawal with.
+// It is short enough to fit the reference field of the fixed width settlement format.
+func GenerateProviderReference() string {
+	return "OP" + strings.ToUpper(GenerateRandomString(14))
+}
+
 // IDWOutPrefix is a function that returns an id without it's prefix
 func IDWOutPrefix(id string) string {
 
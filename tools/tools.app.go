@@ -3,7 +3,6 @@ package tools
 import (
 	"os"
 	"path/filepath"
-	"strconv"
 
 	"github.com/Benyam-S/onepay/entity"
 )
@@ -55,7 +54,7 @@ func ClosingStatement(opUser *entity.Staff, histories []*entity.UserHistory,
 	for _, history := range histories {
 		line := "Sender ID: " + history.SenderID + "		Receiver ID: " + history.ReceiverID +
 			"	Sent At: " + history.SentAt.String() + "	Received At: " + history.ReceivedAt.String() +
-			"	Method: " + history.Method + "	Amount:" + strconv.FormatFloat(history.Amount, 'f', 2, 64) + "\n"
+			"	Method: " + history.Method + "	Amount:" + history.Amount.String() + " " + history.Amount.Currency + "\n"
 
 		_, err = file.WriteString(line)
 		if err != nil {
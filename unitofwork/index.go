@@ -0,0 +1,79 @@
+package unitofwork
+
+import (
+	"errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Manager is a type that defines a unit of work manager that begins database transactions
+type Manager struct {
+	conn *gorm.DB
+}
+
+// UnitOfWork is a type that defines a database transaction shared by all the repositories taking part in a single operation.
+// Either every change made through the unit of work is committed or none of them is.
+type UnitOfWork struct {
+	tx          *gorm.DB
+	afterCommit []func()
+	done        bool
+}
+
+// NewManager is a function that returns a new unit of work manager
+func NewManager(connection *gorm.DB) *Manager {
+	return &Manager{conn: connection}
+}
+
+// Begin is a method that starts a new unit of work backed by a database transaction
+func (manager *Manager) Begin() (*UnitOfWork, error) {
+
+	tx := manager.conn.Begin()
+	if tx.Error != nil {
+		return nil, errors.New("unable to begin transaction")
+	}
+
+	return &UnitOfWork{tx: tx}, nil
+}
+
+// Conn is a method that returns the database connection that is bound to the unit of work's transaction
+func (uow *UnitOfWork) Conn() *gorm.DB {
+	return uow.tx
+}
+
+// AfterCommit is a method that registers a function that should only run once the unit of work has been committed,
+// such as notifying listeners about a change
+func (uow *UnitOfWork) AfterCommit(f func()) {
+	uow.afterCommit = append(uow.afterCommit, f)
+}
+
+// Commit is a method that commits all the changes made through the unit of work
+func (uow *UnitOfWork) Commit() error {
+
+	if uow.done {
+		return errors.New("transaction has already been closed")
+	}
+
+	uow.done = true
+	err := uow.tx.Commit().Error
+	if err != nil {
+		return errors.New("unable to commit transaction")
+	}
+
+	for _, f := range uow.afterCommit {
+		f()
+	}
+
+	return nil
+}
+
+// Rollback is a method that discards all the changes made through the unit of work.
+// Calling Rollback after Commit does nothing, so it can safely be deferred.
+func (uow *UnitOfWork) Rollback() error {
+
+	if uow.done {
+		return nil
+	}
+
+	uow.done = true
+	return uow.tx.Rollback().Error
+}
@@ -21,6 +21,11 @@ type IUserRepository interface {
 	IsUniqueRegx(columnName string, columnPattern string) bool
 }
 
+// IStaffRepository is an interface that defines all the repository methods of a staff member struct
+type IStaffRepository interface {
+	Find(identifier string) (*entity.Staff, error)
+}
+
 // IPasswordRepository is an interface that defines all the repository methods of a user's password struct
 type IPasswordRepository interface {
 	Create(newOPPassword *entity.UserPassword) error
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/user"
+	"github.com/jinzhu/gorm"
+)
+
+// StaffRepository is a type that defines a staff member repository
+type StaffRepository struct {
+	conn *gorm.DB
+}
+
+// NewStaffRepository is a function that returns a new staff member repository
+func NewStaffRepository(connection *gorm.DB) user.IStaffRepository {
+	return &StaffRepository{conn: connection}
+}
+
+// Find is a method that finds a certain staff member from the database using an identifier,
+// also Find() uses only user_id as a key for selection
+func (repo *StaffRepository) Find(identifier string) (*entity.Staff, error) {
+	staffMember := new(entity.Staff)
+	err := repo.conn.Model(staffMember).
+		Where("user_id = ?", identifier).
+		First(staffMember).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return staffMember, nil
+}
@@ -55,4 +55,6 @@ type IService interface {
 	UpdateAPIToken(apiToken *api.Token) error
 	DeleteAPIToken(identifier string) (*api.Token, error)
 	DeleteAPITokens(identifier string) ([]*api.Token, error)
+
+	FindStaffMember(identifier string) (*entity.Staff, error)
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/Benyam-S/onepay/entity"
 )
@@ -28,7 +29,7 @@ func (service *Service) FindUserPreference(identifier string) (*entity.UserPrefe
 func (service *Service) ValidateUserPreference(columnName, columValue string) (interface{}, error) {
 
 	// Column name screeing
-	validColumnNames := []string{"two_step_verification"}
+	validColumnNames := []string{"two_step_verification", "time_zone"}
 	isValidColumnName := false
 
 	for _, validColumnName := range validColumnNames {
@@ -51,6 +52,15 @@ func (service *Service) ValidateUserPreference(columnName, columValue string) (i
 		return value, nil
 	}
 
+	if columnName == "time_zone" {
+		location, err := time.LoadLocation(columValue)
+		if err != nil || columValue == "" || columValue == "Local" {
+			return nil, errors.New("invalid value used")
+		}
+
+		return location.String(), nil
+	}
+
 	return columValue, nil
 }
 
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/Benyam-S/onepay/entity"
+)
+
+// FindStaffMember is a method that find and return a staff member that matchs the identifier value
+func (service *Service) FindStaffMember(identifier string) (*entity.Staff, error) {
+
+	empty, _ := regexp.MatchString(`^\s*$`, identifier)
+	if empty {
+		return nil, errors.New("staff member not found")
+	}
+
+	staffMember, err := service.staffRepo.Find(identifier)
+	if err != nil {
+		return nil, errors.New("staff member not found")
+	}
+
+	return staffMember, nil
+}
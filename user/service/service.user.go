@@ -31,6 +31,7 @@ type Service struct {
 	walletRepo        wallet.IWalletRepository
 	apiClientRepo     user.IAPIClientRepository
 	apiTokenRepo      user.IAPITokenRepository
+	staffRepo         user.IStaffRepository
 	notifier          *notifier.Notifier
 }
 
@@ -38,10 +39,11 @@ type Service struct {
 func NewUserService(userRepository user.IUserRepository,
 	passwordRepository user.IPasswordRepository, preferenceRepository user.IPreferenceRepository,
 	sessionRepository user.ISessionRepository, apiClientRepository user.IAPIClientRepository,
-	apiTokenRepository user.IAPITokenRepository, profileChangeNotifier *notifier.Notifier) user.IService {
+	apiTokenRepository user.IAPITokenRepository, staffRepository user.IStaffRepository,
+	profileChangeNotifier *notifier.Notifier) user.IService {
 	return &Service{userRepo: userRepository, passwordRepo: passwordRepository, preferenceRepo: preferenceRepository,
 		sessionRepo: sessionRepository, apiClientRepo: apiClientRepository,
-		apiTokenRepo: apiTokenRepository, notifier: profileChangeNotifier}
+		apiTokenRepo: apiTokenRepository, staffRepo: staffRepository, notifier: profileChangeNotifier}
 }
 
 // AddUser is a method that adds a new OnePay user to the system along with the password
@@ -62,6 +64,7 @@ func (service *Service) AddUser(opUser *entity.User, opPassword *entity.UserPass
 	// Since user preference is initiated with a default value it can be created here.
 	userPreference := new(entity.UserPreference)
 	userPreference.UserID = opUser.UserID
+	userPreference.TimeZone = entity.DefaultTimeZone
 	err = service.preferenceRepo.Create(userPreference)
 	if err != nil {
 		// Cleaning up if password is not add to the database
@@ -1,12 +1,25 @@
 package wallet
 
-import "github.com/Benyam-S/onepay/entity"
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
 
 // IWalletRepository is an interface that defines all the repository method of a user's wallet
 type IWalletRepository interface {
 	Create(newOPWallet *entity.UserWallet) error
-	Find(identifier string) (*entity.UserWallet, error)
+	Find(identifier, currency string) (*entity.UserWallet, error)
+	Search(userID string) []*entity.UserWallet
+	All() []*entity.UserWallet
 	Update(opWallet *entity.UserWallet) error
+	Debit(opWallet *entity.UserWallet, amount entity.Money) error
+	Credit(opWallet *entity.UserWallet, amount entity.Money) error
+	Hold(opWallet *entity.UserWallet, amount entity.Money) error
+	Release(opWallet *entity.UserWallet, amount entity.Money) error
+	DebitHeld(opWallet *entity.UserWallet, amount entity.Money) error
+	Seize(opWallet *entity.UserWallet, amount entity.Money) error
 	UpdateSeen(opWallet *entity.UserWallet, value bool) error
-	Delete(identifier string) (*entity.UserWallet, error)
+	UpdateQuarantined(opWallet *entity.UserWallet, value bool) error
+	Delete(identifier string) ([]*entity.UserWallet, error)
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IWalletRepository
 }
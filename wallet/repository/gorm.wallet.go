@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"errors"
+
 	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
 	"github.com/Benyam-S/onepay/wallet"
 	"github.com/jinzhu/gorm"
 )
@@ -26,12 +29,12 @@ func (repo *WalletRepository) Create(newOPWallet *entity.UserWallet) error {
 	return nil
 }
 
-// Find is a method that finds a certain user's wallet from the database using an identifier.
+// Find is a method that finds a certain user's wallet of the provided currency from the database using an identifier.
 // In Find() user_id is only used as a key
-func (repo *WalletRepository) Find(identifier string) (*entity.UserWallet, error) {
+func (repo *WalletRepository) Find(identifier, currency string) (*entity.UserWallet, error) {
 	opWallet := new(entity.UserWallet)
 	err := repo.conn.Model(opWallet).
-		Where("user_id = ?", identifier).
+		Where("user_id = ? AND currency = ?", identifier, currency).
 		First(opWallet).Error
 
 	if err != nil {
@@ -40,24 +43,230 @@ func (repo *WalletRepository) Find(identifier string) (*entity.UserWallet, error
 	return opWallet, nil
 }
 
-// Update is a method that updates a certain user's wallet value in the database
+// Search is a method that returns all the wallets of a certain user from the database
+func (repo *WalletRepository) Search(userID string) []*entity.UserWallet {
+	var opWallets []*entity.UserWallet
+	err := repo.conn.Model(entity.UserWallet{}).Where("user_id = ?", userID).
+		Order("currency").Find(&opWallets).Error
+
+	if err != nil {
+		return []*entity.UserWallet{}
+	}
+	return opWallets
+}
+
+// All is a method that returns all the user wallets found in the database
+func (repo *WalletRepository) All() []*entity.UserWallet {
+	var opWallets []*entity.UserWallet
+	err := repo.conn.Model(entity.UserWallet{}).Find(&opWallets).Error
+
+	if err != nil {
+		return []*entity.UserWallet{}
+	}
+	return opWallets
+}
+
+// Update is a method that updates a certain user's wallet value in the database.
+// The update only succeeds if the wallet hasn't been changed since it was read, in other word if the version still matches.
 func (repo *WalletRepository) Update(opWallet *entity.UserWallet) error {
 
 	prevOPWallet := new(entity.UserWallet)
-	err := repo.conn.Model(prevOPWallet).Where("user_id = ?", opWallet.UserID).First(prevOPWallet).Error
+	err := repo.conn.Model(prevOPWallet).Where("user_id = ? AND currency = ?", opWallet.UserID, opWallet.Currency).
+		First(prevOPWallet).Error
 
 	if err != nil {
 		return err
 	}
 
-	err = repo.conn.Save(opWallet).Error
+	result := repo.conn.Model(entity.UserWallet{}).
+		Where("user_id = ? AND currency = ? AND version = ?", opWallet.UserID, opWallet.Currency, opWallet.Version).
+		Updates(map[string]interface{}{"amount": opWallet.Amount, "seen": opWallet.Seen,
+			"version": gorm.Expr("version + 1")})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New(entity.WalletConflictError)
+	}
+
+	opWallet.Version++
+	return nil
+}
+
+// Debit is a method that subtracts the provided amount from a certain user's wallet in the database.
+// The debit only succeeds if the wallet version still matches, the wallet isn't quarantined and it holds enough amount.
+func (repo *WalletRepository) Debit(opWallet *entity.UserWallet, amount entity.Money) error {
+
+	result := repo.conn.Model(entity.UserWallet{}).
+		Where("user_id = ? AND currency = ? AND version = ? AND quarantined = false AND amount >= ?",
+			opWallet.UserID, opWallet.Currency, opWallet.Version, amount.Minor).
+		Updates(map[string]interface{}{"amount": gorm.Expr("amount - ?", amount.Minor), "seen": false,
+			"version": gorm.Expr("version + 1")})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return repo.conditionFailure(opWallet)
+	}
+
+	opWallet.Amount = opWallet.Amount.Sub(amount)
+	opWallet.Seen = false
+	opWallet.Version++
+	return nil
+}
+
+// Credit is a method that adds the provided amount to a certain user's wallet in the database.
+// The credit only succeeds if the wallet version still matches.
+func (repo *WalletRepository) Credit(opWallet *entity.UserWallet, amount entity.Money) error {
+
+	result := repo.conn.Model(entity.UserWallet{}).
+		Where("user_id = ? AND currency = ? AND version = ?", opWallet.UserID, opWallet.Currency, opWallet.Version).
+		Updates(map[string]interface{}{"amount": gorm.Expr("amount + ?", amount.Minor), "seen": false,
+			"version": gorm.Expr("version + 1")})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return repo.conditionFailure(opWallet)
+	}
+
+	opWallet.Amount = opWallet.Amount.Add(amount)
+	opWallet.Seen = false
+	opWallet.Version++
+	return nil
+}
+
+// Hold is a method that moves the provided amount from the available amount of a certain user's wallet to its held amount.
+// The hold only succeeds if the wallet version still matches, the wallet isn't quarantined and it has enough available amount.
+func (repo *WalletRepository) Hold(opWallet *entity.UserWallet, amount entity.Money) error {
+
+	result := repo.conn.Model(entity.UserWallet{}).
+		Where("user_id = ? AND currency = ? AND version = ? AND quarantined = false AND amount >= ?",
+			opWallet.UserID, opWallet.Currency, opWallet.Version, amount.Minor).
+		Updates(map[string]interface{}{"amount": gorm.Expr("amount - ?", amount.Minor),
+			"held": gorm.Expr("held + ?", amount.Minor), "seen": false, "version": gorm.Expr("version + 1")})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return repo.conditionFailure(opWallet)
+	}
+
+	opWallet.Amount = opWallet.Amount.Sub(amount)
+	opWallet.Held = opWallet.Held.Add(amount)
+	opWallet.Seen = false
+	opWallet.Version++
+	return nil
+}
+
+// Release is a method that moves the provided amount from the held amount of a certain user's wallet back to its available amount.
+// The release only succeeds if the wallet version still matches and the wallet holds enough held amount.
+func (repo *WalletRepository) Release(opWallet *entity.UserWallet, amount entity.Money) error {
+
+	result := repo.conn.Model(entity.UserWallet{}).
+		Where("user_id = ? AND currency = ? AND version = ? AND held >= ?",
+			opWallet.UserID, opWallet.Currency, opWallet.Version, amount.Minor).
+		Updates(map[string]interface{}{"amount": gorm.Expr("amount + ?", amount.Minor),
+			"held": gorm.Expr("held - ?", amount.Minor), "seen": false, "version": gorm.Expr("version + 1")})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return repo.conditionFailure(opWallet)
+	}
+
+	opWallet.Amount = opWallet.Amount.Add(amount)
+	opWallet.Held = opWallet.Held.Sub(amount)
+	opWallet.Seen = false
+	opWallet.Version++
+	return nil
+}
+
+// DebitHeld is a method that subtracts the provided amount from the held amount of a certain user's wallet.
+// The debit only succeeds if the wallet version still matches and the wallet holds enough held amount.
+func (repo *WalletRepository) DebitHeld(opWallet *entity.UserWallet, amount entity.Money) error {
+
+	result := repo.conn.Model(entity.UserWallet{}).
+		Where("user_id = ? AND currency = ? AND version = ? AND held >= ?",
+			opWallet.UserID, opWallet.Currency, opWallet.Version, amount.Minor).
+		Updates(map[string]interface{}{"held": gorm.Expr("held - ?", amount.Minor), "seen": false,
+			"version": gorm.Expr("version + 1")})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return repo.conditionFailure(opWallet)
+	}
+
+	opWallet.Held = opWallet.Held.Sub(amount)
+	opWallet.Seen = false
+	opWallet.Version++
+	return nil
+}
+
+// Seize is a method that subtracts the provided amount from a certain user's wallet in the database even if it is quarantined.
+// The seizure only succeeds if the wallet version still matches and it holds enough amount.
+func (repo *WalletRepository) Seize(opWallet *entity.UserWallet, amount entity.Money) error {
+
+	result := repo.conn.Model(entity.UserWallet{}).
+		Where("user_id = ? AND currency = ? AND version = ? AND amount >= ?",
+			opWallet.UserID, opWallet.Currency, opWallet.Version, amount.Minor).
+		Updates(map[string]interface{}{"amount": gorm.Expr("amount - ?", amount.Minor), "seen": false,
+			"version": gorm.Expr("version + 1")})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		if err := repo.conditionFailure(opWallet); err.Error() != entity.QuarantinedWalletError {
+			return err
+		}
+		return errors.New(entity.InsufficientBalanceError)
+	}
+
+	opWallet.Amount = opWallet.Amount.Sub(amount)
+	opWallet.Seen = false
+	opWallet.Version++
+	return nil
+}
+
+// conditionFailure is a method that finds out why a conditional wallet update hasn't changed any row.
+// A locking read is used so the latest committed wallet is compared even inside a transaction.
+func (repo *WalletRepository) conditionFailure(opWallet *entity.UserWallet) error {
+
+	prevOPWallet := new(entity.UserWallet)
+	err := repo.conn.Set("gorm:query_option", "FOR UPDATE").Model(prevOPWallet).
+		Where("user_id = ? AND currency = ?", opWallet.UserID, opWallet.Currency).First(prevOPWallet).Error
+
 	if err != nil {
 		return err
 	}
-	return nil
+
+	if prevOPWallet.Version != opWallet.Version {
+		return errors.New(entity.WalletConflictError)
+	}
+
+	if prevOPWallet.Quarantined {
+		return errors.New(entity.QuarantinedWalletError)
+	}
+
+	return errors.New(entity.InsufficientBalanceError)
 }
 
-// UpdateSeen is a method that updates a certain user wallet's seen value in the database
+// UpdateSeen is a method that updates the seen value of all the wallets of a certain user in the database
 func (repo *WalletRepository) UpdateSeen(opWallet *entity.UserWallet, value bool) error {
 
 	prevOPWallet := new(entity.UserWallet)
@@ -75,16 +284,49 @@ func (repo *WalletRepository) UpdateSeen(opWallet *entity.UserWallet, value bool
 	return nil
 }
 
-// Delete is a method that deletes a certain user's wallet from the database using an identifier.
+// UpdateQuarantined is a method that quarantines or releases a certain user's wallet in the database.
+// The update only succeeds if the wallet version still matches.
+func (repo *WalletRepository) UpdateQuarantined(opWallet *entity.UserWallet, value bool) error {
+
+	result := repo.conn.Model(entity.UserWallet{}).
+		Where("user_id = ? AND currency = ? AND version = ?", opWallet.UserID, opWallet.Currency, opWallet.Version).
+		Updates(map[string]interface{}{"quarantined": value, "version": gorm.Expr("version + 1")})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New(entity.WalletConflictError)
+	}
+
+	opWallet.Quarantined = value
+	opWallet.Version++
+	return nil
+}
+
+// Delete is a method that deletes all the wallets of a certain user from the database using an identifier.
 // In Delete() user_id is only used as a key
-func (repo *WalletRepository) Delete(identifier string) (*entity.UserWallet, error) {
-	opWallet := new(entity.UserWallet)
-	err := repo.conn.Model(opWallet).Where("user_id = ?", identifier).First(opWallet).Error
+func (repo *WalletRepository) Delete(identifier string) ([]*entity.UserWallet, error) {
+	var opWallets []*entity.UserWallet
+	err := repo.conn.Model(entity.UserWallet{}).Where("user_id = ?", identifier).Find(&opWallets).Error
 
 	if err != nil {
 		return nil, err
 	}
 
-	repo.conn.Delete(opWallet)
-	return opWallet, nil
+	if len(opWallets) == 0 {
+		return nil, errors.New("no wallet found")
+	}
+
+	err = repo.conn.Where("user_id = ?", identifier).Delete(entity.UserWallet{}).Error
+	if err != nil {
+		return nil, err
+	}
+	return opWallets, nil
+}
+
+// WithUnitOfWork is a method that returns a user's wallet repository that runs its queries inside the provided unit of work
+func (repo *WalletRepository) WithUnitOfWork(uow *unitofwork.UnitOfWork) wallet.IWalletRepository {
+	return &WalletRepository{conn: uow.Conn()}
 }
@@ -1,12 +1,25 @@
 package wallet
 
-import "github.com/Benyam-S/onepay/entity"
+import (
+	"github.com/Benyam-S/onepay/entity"
+	"github.com/Benyam-S/onepay/unitofwork"
+)
 
 // IService is an interface that defines all the service methods of a user wallet struct
 type IService interface {
 	AddWallet(newWallet *entity.UserWallet) error
-	FindWallet(identifier string) (*entity.UserWallet, error)
+	FindWallet(identifier, currency string) (*entity.UserWallet, error)
+	SearchWallets(userID string) []*entity.UserWallet
+	AllWallets() []*entity.UserWallet
 	UpdateWallet(wallet *entity.UserWallet) error
+	DebitWallet(wallet *entity.UserWallet, amount entity.Money) error
+	CreditWallet(wallet *entity.UserWallet, amount entity.Money) error
+	HoldWallet(wallet *entity.UserWallet, amount entity.Money) error
+	ReleaseWallet(wallet *entity.UserWallet, amount entity.Money) error
+	DebitHeldWallet(wallet *entity.UserWallet, amount entity.Money) error
+	SeizeWallet(wallet *entity.UserWallet, amount entity.Money) error
 	UpdateWalletSeen(userID string, columnValue bool) error
-	DeleteWallet(identifier string) (*entity.UserWallet, error)
+	QuarantineWallet(wallet *entity.UserWallet, quarantined bool) error
+	DeleteWallets(identifier string) ([]*entity.UserWallet, error)
+	WithUnitOfWork(uow *unitofwork.UnitOfWork) IService
 }
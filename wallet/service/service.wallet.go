@@ -3,9 +3,11 @@ package service
 import (
 	"errors"
 	"regexp"
+	"strings"
 
 	"github.com/Benyam-S/onepay/entity"
 	"github.com/Benyam-S/onepay/notifier"
+	"github.com/Benyam-S/onepay/unitofwork"
 	"github.com/Benyam-S/onepay/wallet"
 )
 
@@ -13,6 +15,7 @@ import (
 type Service struct {
 	walletRepo wallet.IWalletRepository
 	notifier   *notifier.Notifier
+	uow        *unitofwork.UnitOfWork
 }
 
 // NewWalletService is a function that returns a new user wallet service
@@ -23,6 +26,15 @@ func NewWalletService(walletRepository wallet.IWalletRepository, walletChangeNot
 // AddWallet is a method that adds a new user wallet to the system
 func (service *Service) AddWallet(newWallet *entity.UserWallet) error {
 
+	if newWallet.Currency == "" {
+		newWallet.Currency = entity.NewMoney(0, newWallet.Amount.Currency).Currency
+	}
+
+	newWallet.Currency = strings.ToUpper(newWallet.Currency)
+	if !entity.IsSupportedCurrency(newWallet.Currency) {
+		return errors.New(entity.UnsupportedCurrencyError)
+	}
+
 	// Since a new wallet has no update, wallet has to be seen
 	newWallet.Seen = true
 
@@ -33,21 +45,33 @@ func (service *Service) AddWallet(newWallet *entity.UserWallet) error {
 	return nil
 }
 
-// FindWallet is a method that finds a user's wallet using the provided identifier
-func (service *Service) FindWallet(identifier string) (*entity.UserWallet, error) {
+// FindWallet is a method that finds a user's wallet of the provided currency using the provided identifier.
+// If no currency is provided the wallet of the base currency is returned.
+func (service *Service) FindWallet(identifier, currency string) (*entity.UserWallet, error) {
 
 	empty, _ := regexp.MatchString(`^\s*$`, identifier)
 	if empty {
 		return nil, errors.New("user wallet not found")
 	}
 
-	opWallet, err := service.walletRepo.Find(identifier)
+	currency = entity.NewMoney(0, currency).Currency
+	opWallet, err := service.walletRepo.Find(identifier, currency)
 	if err != nil {
 		return nil, errors.New("user wallet not found")
 	}
 	return opWallet, nil
 }
 
+// SearchWallets is a method that returns all the wallets of a certain user
+func (service *Service) SearchWallets(userID string) []*entity.UserWallet {
+	return service.walletRepo.Search(userID)
+}
+
+// AllWallets is a method that returns all the user wallets in the system
+func (service *Service) AllWallets() []*entity.UserWallet {
+	return service.walletRepo.All()
+}
+
 // UpdateWallet is a method that updates a certain user's wallet
 func (service *Service) UpdateWallet(wallet *entity.UserWallet) error {
 
@@ -56,11 +80,109 @@ func (service *Service) UpdateWallet(wallet *entity.UserWallet) error {
 
 	err := service.walletRepo.Update(wallet)
 	if err != nil {
+		if err.Error() == entity.WalletConflictError {
+			return err
+		}
+		return errors.New("unable to update user wallet")
+	}
+
+	/* ++++++++++++++ NOTIFYING CHANGE +++++++++++++++ */
+	service.notify(func() { service.notifier.NotifyWalletChange(wallet.UserID) })
+	/* +++++++++++++++++++++++++++++++++++++++++++++++ */
+
+	return nil
+}
+
+// DebitWallet is a method that subtracts the provided amount from a certain user's wallet.
+// It fails with a wallet conflict error if the wallet has been changed since it was read.
+func (service *Service) DebitWallet(wallet *entity.UserWallet, amount entity.Money) error {
+
+	if amount.IsNegative() {
+		return errors.New("invalid debit amount")
+	}
+
+	err := service.walletRepo.Debit(wallet, amount)
+	if err != nil {
+		if err.Error() == entity.WalletConflictError || err.Error() == entity.InsufficientBalanceError ||
+			err.Error() == entity.QuarantinedWalletError {
+			return err
+		}
+		return errors.New("unable to update user wallet")
+	}
+
+	/* ++++++++++++++ NOTIFYING CHANGE +++++++++++++++ */
+	service.notify(func() { service.notifier.NotifyWalletChange(wallet.UserID) })
+	/* +++++++++++++++++++++++++++++++++++++++++++++++ */
+
+	return nil
+}
+
+// CreditWallet is a method that adds the provided amount to a certain user's wallet.
+// It fails with a wallet conflict error if the wallet has been changed since it was read.
+func (service *Service) CreditWallet(wallet *entity.UserWallet, amount entity.Money) error {
+
+	if amount.IsNegative() {
+		return errors.New("invalid credit amount")
+	}
+
+	err := service.walletRepo.Credit(wallet, amount)
+	if err != nil {
+		if err.Error() == entity.WalletConflictError {
+			return err
+		}
 		return errors.New("unable to update user wallet")
 	}
 
 	/* ++++++++++++++ NOTIFYING CHANGE +++++++++++++++ */
-	service.notifier.NotifyWalletChange(wallet.UserID)
+	service.notify(func() { service.notifier.NotifyWalletChange(wallet.UserID) })
+	/* +++++++++++++++++++++++++++++++++++++++++++++++ */
+
+	return nil
+}
+
+// HoldWallet is a method that reserves the provided amount of a certain user's wallet by moving it from the available to the held amount.
+// It fails with a wallet conflict error if the wallet has been changed since it was read.
+func (service *Service) HoldWallet(wallet *entity.UserWallet, amount entity.Money) error {
+	return service.changeHeld(wallet, amount, service.walletRepo.Hold)
+}
+
+// ReleaseWallet is a method that moves the provided amount of a certain user's wallet from the held back to the available amount.
+// It fails with a wallet conflict error if the wallet has been changed since it was read.
+func (service *Service) ReleaseWallet(wallet *entity.UserWallet, amount entity.Money) error {
+	return service.changeHeld(wallet, amount, service.walletRepo.Release)
+}
+
+// DebitHeldWallet is a method that subtracts the provided amount from the held amount of a certain user's wallet.
+// It fails with a wallet conflict error if the wallet has been changed since it was read.
+func (service *Service) DebitHeldWallet(wallet *entity.UserWallet, amount entity.Money) error {
+	return service.changeHeld(wallet, amount, service.walletRepo.DebitHeld)
+}
+
+// SeizeWallet is a method that subtracts the provided amount from a certain user's wallet even if it is quarantined.
+// It is only meant for money the platform takes into its own custody, like the holding of a disputed payment.
+func (service *Service) SeizeWallet(wallet *entity.UserWallet, amount entity.Money) error {
+	return service.changeHeld(wallet, amount, service.walletRepo.Seize)
+}
+
+// changeHeld is a method that applies a conditional change to the held amount of a certain user's wallet and notifies the change
+func (service *Service) changeHeld(wallet *entity.UserWallet, amount entity.Money,
+	change func(opWallet *entity.UserWallet, amount entity.Money) error) error {
+
+	if amount.IsNegative() {
+		return errors.New("invalid hold amount")
+	}
+
+	err := change(wallet, amount)
+	if err != nil {
+		if err.Error() == entity.WalletConflictError || err.Error() == entity.InsufficientBalanceError ||
+			err.Error() == entity.QuarantinedWalletError {
+			return err
+		}
+		return errors.New("unable to update user wallet")
+	}
+
+	/* ++++++++++++++ NOTIFYING CHANGE +++++++++++++++ */
+	service.notify(func() { service.notifier.NotifyWalletChange(wallet.UserID) })
 	/* +++++++++++++++++++++++++++++++++++++++++++++++ */
 
 	return nil
@@ -77,12 +199,41 @@ func (service *Service) UpdateWalletSeen(userID string, columnValue bool) error
 	return nil
 }
 
-// DeleteWallet is a method that deletes an user's wallet from the system
-func (service *Service) DeleteWallet(identifier string) (*entity.UserWallet, error) {
+// QuarantineWallet is a method that quarantines or releases a certain user's wallet.
+// No money can leave a quarantined wallet, but it can still be credited.
+func (service *Service) QuarantineWallet(wallet *entity.UserWallet, quarantined bool) error {
 
-	opWallet, err := service.walletRepo.Delete(identifier)
+	err := service.walletRepo.UpdateQuarantined(wallet, quarantined)
+	if err != nil {
+		if err.Error() == entity.WalletConflictError {
+			return err
+		}
+		return errors.New("unable to update user wallet")
+	}
+	return nil
+}
+
+// DeleteWallets is a method that deletes all the wallets of a user from the system
+func (service *Service) DeleteWallets(identifier string) ([]*entity.UserWallet, error) {
+
+	opWallets, err := service.walletRepo.Delete(identifier)
 	if err != nil {
 		return nil, errors.New("unable to delete user wallet")
 	}
-	return opWallet, nil
+	return opWallets, nil
+}
+
+// WithUnitOfWork is a method that returns a user wallet service whose changes are made inside the provided unit of work
+func (service *Service) WithUnitOfWork(uow *unitofwork.UnitOfWork) wallet.IService {
+	return &Service{walletRepo: service.walletRepo.WithUnitOfWork(uow), notifier: service.notifier, uow: uow}
+}
+
+// notify is a method that runs the provided notification immediately or,
+// if the service is bound to a unit of work, once the unit of work has been committed
+func (service *Service) notify(f func()) {
+	if service.uow != nil {
+		service.uow.AfterCommit(f)
+		return
+	}
+	f()
 }